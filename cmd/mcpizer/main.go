@@ -2,68 +2,71 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/i2y/mcpizer/configs"
 	"github.com/i2y/mcpizer/internal/adapter/inbound/mcphttp"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/bodylog"
 	"github.com/i2y/mcpizer/internal/adapter/outbound/grpcinvoker"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/hostpolicy"
 	"github.com/i2y/mcpizer/internal/adapter/outbound/httpinvoker"
 	"github.com/i2y/mcpizer/internal/adapter/outbound/invoker"
 	"github.com/i2y/mcpizer/internal/adapter/outbound/openapi"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/tlsclient"
 	"github.com/i2y/mcpizer/internal/domain"
 	"github.com/i2y/mcpizer/internal/usecase"
 
 	// Import outbound adapters needed for syncUC
+	"github.com/i2y/mcpizer/internal/adapter/outbound/boltrepo"
 	connectadapter "github.com/i2y/mcpizer/internal/adapter/outbound/connect"
 	"github.com/i2y/mcpizer/internal/adapter/outbound/github"
 	grpcadapter "github.com/i2y/mcpizer/internal/adapter/outbound/grpc"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/memrepo"
 	protoadapter "github.com/i2y/mcpizer/internal/adapter/outbound/proto"
 
 	// "github.com/i2y/mcpizer/internal/adapter/inbound/mcphttp" // Replaced by mcp-go server
 	// "github.com/i2y/mcpizer/internal/adapter/outbound/httpinvoker" // Not used here anymore
 
-	// "github.com/i2y/mcpizer/internal/adapter/outbound/memrepo" // Not used here anymore
-
 	// mcp-go imports
 	// mcp "github.com/mark3labs/mcp-go/mcp" // Not used directly in main yet
 	mcpGoServer "github.com/mark3labs/mcp-go/server"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0" // Use appropriate version
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-// Config holds application configuration loaded from environment variables.
-type Config struct {
-	ListenAddr         string        `envconfig:"LISTEN_ADDR" default:":8080"`
-	OpenAPIDefaultHost string        `envconfig:"OPENAPI_DEFAULT_HOST"`
-	ShutdownTimeout    time.Duration `envconfig:"SHUTDOWN_TIMEOUT" default:"5s"`
-	SchemaSources      []string      `envconfig:"SCHEMA_SOURCES"`
-	// TODO: Add fields for schema_sources, auth_token, log level, client timeouts etc.
-	// Example for slice: SchemaSources []string `envconfig:"SCHEMA_SOURCES"` (comma-separated)
-	HTTPClientTimeout time.Duration `envconfig:"HTTP_CLIENT_TIMEOUT" default:"10s"`
-}
-
 func main() {
 	// === Command Line Flags ===
 	var transport string
 	var configFile string
+	var dumpToolsFile string
+	var validate bool
 	flag.StringVar(&transport, "transport", "sse", "Transport mode: sse or stdio")
 	flag.StringVar(&configFile, "config", "", "Path to config file (overrides MCPIZER_CONFIG_FILE)")
+	flag.StringVar(&dumpToolsFile, "dump-tools", "", "Write all generated tools as JSON to this file and exit")
+	flag.BoolVar(&validate, "validate", false, "Generate the tools of every schema source, report failed sources and skipped operations, and exit")
 	flag.Parse()
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -115,70 +118,137 @@ func main() {
 	logger.Info("OpenTelemetry initialized.")
 
 	// === MCP Server (mark3labs/mcp-go) ===
-	mcpSrv := mcpGoServer.NewMCPServer(
-		"mcpizer", // Server name
-		"0.1.0",   // Server version - TODO: Get from build info?
-		// TODO: Add server.WithHooks() if needed later
-	)
-	logger.Info("MCP server (mark3labs/mcp-go) initialized.")
+	instructions, err := serverInstructions(cfg)
+	if err != nil {
+		logger.Error("Failed to load server instructions.", slog.Any("error", err))
+		os.Exit(1)
+	}
+	mcpSrv := newMCPServer(instructions)
+	logger.Info("MCP server (mark3labs/mcp-go) initialized.", slog.Bool("instructions", instructions != ""))
 
 	// === Dependency Injection ===
 	logger.Info("Initializing dependencies...")
 
 	// --- HTTP Client (Needed by Invoker & Fetcher) ---
-	httpClient := &http.Client{
-		Timeout: cfg.HTTPClientTimeout,
-	}
-	logger.Debug("HTTP Client configured.", slog.Duration("timeout", cfg.HTTPClientTimeout))
-
-	// --- Schema Fetchers (Outbound - Needed by Sync Use Case) ---
-	openapiFetcher := openapi.NewSchemaFetcher(httpClient, logger)
-	grpcFetcher := grpcadapter.NewSchemaFetcher(logger)
-	githubFetcher := github.NewFetcher(logger)
-	protoFetcher := protoadapter.NewSchemaFetcher(httpClient, logger)
-	connectFetcher := connectadapter.NewSchemaFetcher(logger)
-	fetchers := map[domain.SchemaType]usecase.SchemaFetcher{
-		domain.SchemaTypeOpenAPI: openapiFetcher,
-		domain.SchemaTypeGRPC:    grpcFetcher,
-		domain.SchemaTypeGitHub:  githubFetcher,
-		domain.SchemaTypeProto:   protoFetcher,
-		domain.SchemaTypeConnect: connectFetcher,
+	httpClient := newHTTPClient(cfg)
+	logger.Debug("HTTP Client configured.",
+		slog.Duration("timeout", cfg.HTTPClientTimeout),
+		slog.Duration("dial_timeout", cfg.HTTPDialTimeout),
+		slog.Duration("tls_handshake_timeout", cfg.HTTPTLSHandshakeTimeout),
+		slog.Duration("response_header_timeout", cfg.HTTPResponseHeaderTimeout),
+		slog.Int("max_idle_conns", cfg.HTTPMaxIdleConns),
+		slog.Int("max_idle_conns_per_host", cfg.HTTPMaxIdleConnsPerHost),
+		slog.Duration("idle_conn_timeout", cfg.HTTPIdleConnTimeout))
+
+	// --- Outbound Host Policy (SSRF protection for HTTP fetchers & invokers) ---
+	hostPolicy, err := hostpolicy.New(cfg.OutboundAllowHosts, cfg.OutboundDenyHosts)
+	if err != nil {
+		logger.Error("Invalid outbound host policy.", slog.Any("error", err))
+		os.Exit(1)
+	}
+	if !hostPolicy.Empty() {
+		httpClient = hostPolicy.WrapClient(httpClient)
+		logger.Info("Outbound host policy enabled.",
+			slog.Any("allow", cfg.OutboundAllowHosts),
+			slog.Any("deny", cfg.OutboundDenyHosts))
 	}
-	logger.Debug("Schema fetchers initialized.")
 
-	// --- Tool Generators (Outbound - Needed by Sync Use Case) ---
-	openapiGenerator := openapi.NewToolGenerator(logger)
-	grpcGenerator := grpcadapter.NewToolGenerator(logger)
-	protoGenerator := protoadapter.NewGenerator(logger)
-	connectGenerator := connectadapter.NewGenerator(logger)
-	generators := map[domain.SchemaType]usecase.ToolGenerator{
-		domain.SchemaTypeOpenAPI:      openapiGenerator,
-		domain.SchemaTypeGRPC:         grpcGenerator,
-		domain.SchemaTypeProto:        protoGenerator,
-		domain.SchemaTypeConnect:      connectGenerator,
-		domain.SchemaTypeConnectProto: protoGenerator, // Reuse proto generator for Connect+proto
+	// --- Schema Fetchers & Tool Generators (Outbound - Needed by Sync Use Case) ---
+	grpcDialOpts := grpcMessageSizeOptions(cfg.GRPCMaxSendMsgSize, cfg.GRPCMaxRecvMsgSize)
+	grpcProxyOpts, err := grpcadapter.ProxyDialOptions(cfg.GRPCProxy)
+	if err != nil {
+		logger.Error("Invalid gRPC proxy.", slog.Any("error", err))
+		os.Exit(1)
+	}
+	grpcDialOpts = append(grpcDialOpts, grpcProxyOpts...)
+	fetchers := newFetchers(httpClient, grpcadapter.Readiness{
+		Timeout:  cfg.GRPCReadyTimeout,
+		Interval: cfg.GRPCReadyInterval,
+	}, grpcadapter.ReflectionRetry{
+		Attempts: cfg.GRPCReflectionAttempts,
+		Backoff:  cfg.GRPCReflectionRetryBackoff,
+	}, logger, grpcDialOpts...)
+	generators := newGenerators(logger, generatorOptions{
+		openAPI: []openapi.GeneratorOption{
+			openapi.WithDefaultHost(cfg.OpenAPIDefaultHost),
+			openapi.WithBodyParamName(cfg.OpenAPIBodyParam),
+			openapi.WithTextOutputFallback(cfg.OpenAPITextOutputFallback),
+			openapi.WithEndpointInDescription(cfg.OpenAPIEndpointInDescription),
+			openapi.WithPreserveNameCase(cfg.OpenAPIPreserveNameCase),
+			openapi.WithNamespace(cfg.OpenAPINamespace),
+			openapi.WithUndeclaredPathParams(cfg.OpenAPIUndeclaredPathParams),
+		},
+		grpc: []grpcadapter.GeneratorOption{
+			grpcadapter.WithPackageComponents(cfg.GRPCToolNamePackageComponents),
+		},
+	})
+	if err := usecase.CheckRegistrations(fetchers, generators); err != nil {
+		logger.Error("Schema fetcher/generator registration is incomplete.", slog.Any("error", err))
+		os.Exit(1)
+	}
+	logger.Debug("Schema fetchers and tool generators initialized.")
+	if err := usecase.ValidateToolNameAffix(cfg.ToolNamePrefix, cfg.ToolNameSuffix); err != nil {
+		logger.Error("Invalid tool name prefix/suffix.", slog.Any("error", err))
+		os.Exit(1)
 	}
-	logger.Debug("Tool generators initialized.")
 
 	// --- Tool Invokers (Outbound - Needed by Sync Use Case Tool Handlers) ---
-	httpInv := httpinvoker.New(httpClient, logger)
-	grpcInv := grpcinvoker.NewInvoker(logger)
-	connectInv := connectadapter.NewInvoker(logger)
+	bodyLog := bodylog.Config{Enabled: cfg.LogBodies, MaxLength: cfg.LogBodyMaxLength}
+	extraParams := httpinvoker.ExtraParamsPolicy(cfg.HTTPExtraParams)
+	if !extraParams.Valid() {
+		logger.Error("Invalid HTTP extra params policy (expected drop, error, or merge).", slog.String("policy", cfg.HTTPExtraParams))
+		os.Exit(1)
+	}
+	invokerClient := httpClient
+	if cfg.HTTPH2C {
+		invokerClient = tlsclient.H2C(httpClient)
+		logger.Info("Cleartext HTTP/2 (h2c) enabled for http:// upstream invocations.")
+	}
+	httpInv := httpinvoker.New(invokerClient, logger,
+		httpinvoker.WithBodyLogging(bodyLog),
+		httpinvoker.WithExtraParamsPolicy(extraParams),
+		httpinvoker.WithUseNumber(cfg.JSONUseNumber),
+		httpinvoker.WithDateNormalization(cfg.HTTPNormalizeDates),
+		httpinvoker.WithCallTimeout(cfg.HTTPCallTimeout),
+		httpinvoker.WithMethodTimeouts(cfg.HTTPMethodTimeouts),
+		httpinvoker.WithServerFallback(cfg.HTTPServerFallback),
+	)
+	grpcInv := grpcinvoker.NewInvoker(logger,
+		grpcinvoker.WithBodyLogging(bodyLog),
+		grpcinvoker.WithDialOptions(grpcDialOpts...),
+		grpcinvoker.WithCallTimeout(cfg.GRPCCallTimeout),
+	)
+	connectInv := connectadapter.NewInvokerWithClient(invokerClient, logger,
+		connectadapter.WithBodyLogging(bodyLog),
+		connectadapter.WithUseNumber(cfg.JSONUseNumber),
+	)
 	toolInvoker := invoker.NewRouter(httpInv, grpcInv, connectInv, logger)
 	logger.Debug("Tool invokers initialized (HTTP, gRPC, and Connect-RPC with router).")
 
+	// --- Tool Repository (mirrors registered tools for lookup and listing) ---
+	var toolRepo usecase.ToolRepository
+	switch cfg.ToolRepository {
+	case "memory":
+		toolRepo = memrepo.NewInMemoryToolRepository(logger)
+	case "bolt":
+		boltRepo, err := boltrepo.NewBoltToolRepository(cfg.ToolRepositoryPath, logger)
+		if err != nil {
+			logger.Error("Failed to open tool repository.", slog.Any("error", err))
+			os.Exit(1)
+		}
+		defer boltRepo.Close()
+		toolRepo = boltRepo
+	default:
+		logger.Error("Invalid tool repository (expected memory or bolt).", slog.String("repository", cfg.ToolRepository))
+		os.Exit(1)
+	}
+
 	// === Use Case (Admin Sync Only for now) ===
 	// Pass real dependencies needed for registration and handlers
-	// Convert config SchemaSource to usecase SchemaSourceConfig
-	sourceConfigs := make([]usecase.SchemaSourceConfig, len(cfg.SchemaSources))
-	for i, source := range cfg.SchemaSources {
-		sourceConfigs[i] = usecase.SchemaSourceConfig{
-			URL:     source.URL,
-			Headers: source.Headers,
-			Server:  source.Server,
-			Type:    source.Type,
-			Mode:    source.Mode,
-		}
+	sourceConfigs := toSourceConfigs(cfg.SchemaSources)
+	toolCallTimeout := cfg.ToolCallTimeout
+	if toolCallTimeout <= 0 {
+		toolCallTimeout = cfg.HTTPClientTimeout
 	}
 	syncUC := usecase.NewSyncSchemaUseCase(
 		sourceConfigs,
@@ -187,18 +257,66 @@ func main() {
 		mcpSrv,      // Pass the mcp-go server instance
 		toolInvoker, // Pass the invoker for handlers
 		logger,
+		usecase.WithToolNameAffix(cfg.ToolNamePrefix, cfg.ToolNameSuffix),
+		usecase.WithToolRepository(toolRepo),
+		usecase.WithInvocationTimeout(toolCallTimeout),
+		usecase.WithMaxTools(cfg.MaxToolsPerSource, cfg.MaxTools),
+		usecase.WithByteFieldDecoding(cfg.DecodeByteFields),
+		usecase.WithRequestIDHeader(cfg.RequestIDHeader),
 	)
 	// syncUC := usecase.NewSyncSchemaUseCase(cfg.SchemaSources, nil, nil, nil, logger) // Placeholder dependencies - REMOVED
+	if cfg.DescribeTool {
+		if err := syncUC.RegisterDescribeTool(); err != nil {
+			logger.Error("Failed to register the describe tool.", slog.Any("error", err))
+			os.Exit(1)
+		}
+	}
+	if cfg.SyncTool {
+		syncUC.RegisterSyncTool()
+	}
+
+	// === Validation (inspection / CI check) ===
+	if validate {
+		if !writeValidationReport(os.Stdout, syncUC.Validate(ctx)) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// === Tool Dump (inspection / CI snapshot) ===
+	if dumpToolsFile != "" {
+		generated, genErr := syncUC.GenerateAll(ctx)
+		if err := writeToolDump(dumpToolsFile, generated); err != nil {
+			logger.Error("Failed to write tool dump.", slog.String("file", dumpToolsFile), slog.Any("error", err))
+			os.Exit(1)
+		}
+		logger.Info("Wrote generated tools.", slog.String("file", dumpToolsFile), slog.Int("tool_count", len(generated)))
+		if genErr != nil {
+			logger.Error("Some schema sources failed to generate tools.", slog.Any("error", genErr))
+			os.Exit(1)
+		}
+		return
+	}
 
 	// === Initial Schema Sync ===
-	// Run initial sync synchronously before starting servers
-	logger.Info("Performing initial schema synchronization...")
-	if err := syncUC.SyncAllConfiguredSources(context.Background()); err != nil {
-		logger.Error("Initial schema sync failed. Server startup continuing, but tools may be missing.", slog.Any("error", err))
-		// Decide if you want to exit here based on sync failure
-		// os.Exit(1)
+	// Tools persisted by a previous run are served right away and the sync runs in the
+	// background; otherwise run the initial sync synchronously before starting servers.
+	restored, err := syncUC.RestoreTools(ctx)
+	if err != nil {
+		logger.Warn("Failed to restore tools from repository.", slog.Any("error", err))
+	}
+	initialSync := func() {
+		logger.Info("Performing initial schema synchronization...")
+		if checkInitialSync(logger, syncUC.SyncAllConfiguredSources(context.Background()), cfg.FailOnSyncError) != nil {
+			os.Exit(1)
+		}
+		syncUC.LogSummary()
+	}
+	// Failing fast needs the sync's outcome before serving, even with restored tools.
+	if restored > 0 && !cfg.FailOnSyncError {
+		go initialSync()
 	} else {
-		logger.Info("Initial schema sync completed successfully.")
+		initialSync()
 	}
 
 	// === Transport Mode Selection ===
@@ -225,7 +343,15 @@ func main() {
 
 		// === Admin HTTP Server Setup ===
 		adminMux := http.NewServeMux()
-		adminHandlers := mcphttp.NewHandlers(syncUC, logger)
+		adminHandlers := mcphttp.NewHandlers(syncUC, logger,
+			mcphttp.WithServeTools(usecase.NewServeToolsUseCase(toolRepo, logger)),
+			mcphttp.WithReload(func() ([]usecase.SchemaSourceConfig, error) {
+				reloaded, err := configs.Load()
+				if err != nil {
+					return nil, err
+				}
+				return toSourceConfigs(reloaded.SchemaSources), nil
+			}))
 		adminHandlers.RegisterAdminRoutes(adminMux) // Register only admin routes
 		adminServer := &http.Server{
 			Addr:    ":8081", // Run admin on a different port
@@ -262,6 +388,11 @@ func main() {
 			logger.Error("Admin HTTP server graceful shutdown failed.", slog.Any("error", err))
 		}
 
+		// Let tool calls in flight finish before their SSE sessions are closed.
+		if err := syncUC.Drain(shutdownCtx); err != nil {
+			logger.Warn("Shutdown timeout expired before all tool calls finished.", slog.Any("error", err))
+		}
+
 		// Shutdown SSE server - Check directly for Shutdown method
 		if err := sseServer.Shutdown(shutdownCtx); err != nil {
 			// Check if the error indicates the method doesn't exist, or if it's a real shutdown error
@@ -278,6 +409,236 @@ func main() {
 	}
 }
 
+// toolDump is the JSON representation of a generated tool written by --dump-tools.
+type toolDump struct {
+	Name         string                  `json:"name"`
+	Title        string                  `json:"title,omitempty"`
+	Description  string                  `json:"description"`
+	Source       string                  `json:"source"`
+	InputSchema  domain.JSONSchemaProps  `json:"inputSchema"`
+	OutputSchema *domain.JSONSchemaProps `json:"outputSchema,omitempty"`
+	Invocation   toolDumpInvocation      `json:"invocation"`
+}
+
+// toolDumpInvocation summarizes how a dumped tool is invoked.
+type toolDumpInvocation struct {
+	Type string `json:"type"`
+	Host string `json:"host"`
+}
+
+// newMCPServer creates the MCP server, sending instructions, if any, to clients on initialize.
+func newMCPServer(instructions string) *mcpGoServer.MCPServer {
+	var opts []mcpGoServer.ServerOption
+	if instructions != "" {
+		opts = append(opts, mcpGoServer.WithInstructions(instructions))
+	}
+	return mcpGoServer.NewMCPServer(
+		"mcpizer", // Server name
+		"0.1.0",   // Server version - TODO: Get from build info?
+		opts...,
+	)
+}
+
+// serverInstructions returns the configured server instructions, reading them from
+// MCPIZER_SERVER_INSTRUCTIONS_FILE if that is set instead of the text.
+func serverInstructions(cfg *configs.Config) (string, error) {
+	if cfg.ServerInstructionsFile == "" {
+		return strings.TrimSpace(cfg.ServerInstructions), nil
+	}
+	if cfg.ServerInstructions != "" {
+		return "", errors.New("set only one of MCPIZER_SERVER_INSTRUCTIONS and MCPIZER_SERVER_INSTRUCTIONS_FILE")
+	}
+	data, err := os.ReadFile(cfg.ServerInstructionsFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read server instructions file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// toSourceConfigs converts the configured schema sources to use case source configs.
+func toSourceConfigs(sources []configs.SchemaSource) []usecase.SchemaSourceConfig {
+	sourceConfigs := make([]usecase.SchemaSourceConfig, len(sources))
+	for i, source := range sources {
+		sourceConfigs[i] = usecase.SchemaSourceConfig{
+			URL:     source.URL,
+			Headers: source.Headers,
+			Server:  source.Server,
+			Type:    source.Type,
+			Mode:    source.Mode,
+
+			ConnectProtocolVersion: source.ConnectProtocolVersion,
+			AcceptEncoding:         source.AcceptEncoding,
+
+			ContentType:         source.ContentType,
+			RequestContentTypes: source.RequestContentTypes,
+			ResponseStatuses:    source.ResponseStatuses,
+			AcceptLanguage:      source.AcceptLanguage,
+			ResponseHeaders:     source.ResponseHeaders,
+
+			StaticQueryParams: source.StaticParams.Query,
+			StaticHeaders:     source.StaticParams.Headers,
+			StaticBodyParams:  source.StaticParams.Body,
+
+			TLSCAFile:             source.TLS.CAFile,
+			TLSInsecureSkipVerify: source.TLS.InsecureSkipVerify,
+
+			AuthTokenFile:       source.Auth.TokenFile,
+			AuthHeader:          source.Auth.Header,
+			AuthScheme:          source.Auth.Scheme,
+			AuthRefreshInterval: source.Auth.RefreshInterval,
+
+			BasePath:               source.BasePath,
+			PathRewrite:            source.PathRewrite.Regexp,
+			PathRewriteReplacement: source.PathRewrite.Replacement,
+
+			DiscoverAll: source.DiscoverAll,
+
+			MaxTools: source.MaxTools,
+
+			LoadBalancing: source.LoadBalancing,
+
+			MaxInFlight:    source.MaxInFlight,
+			InFlightPolicy: source.InFlightPolicy,
+
+			Disabled: source.Disabled,
+		}
+	}
+	return sourceConfigs
+}
+
+// checkInitialSync logs the outcome of the initial schema sync. With failOnError set, a
+// failed sync is returned so that startup stops; otherwise the server starts without
+// the tools of the failed sources.
+func checkInitialSync(logger *slog.Logger, err error, failOnError bool) error {
+	switch {
+	case err == nil:
+		logger.Info("Initial schema sync completed successfully.")
+		return nil
+	case failOnError:
+		logger.Error("Initial schema sync failed. Exiting as MCPIZER_FAIL_ON_SYNC_ERROR is set.", slog.Any("error", err))
+		return err
+	default:
+		logger.Error("Initial schema sync failed. Server startup continuing, but tools may be missing.", slog.Any("error", err))
+		return nil
+	}
+}
+
+// writeToolDump writes the generated tools to path as an indented JSON array.
+func writeToolDump(path string, generated []usecase.GeneratedTool) error {
+	dump := make([]toolDump, 0, len(generated))
+	for _, g := range generated {
+		host := g.Details.Host
+		if g.Details.Server != "" {
+			host = g.Details.Server
+		}
+		dump = append(dump, toolDump{
+			Name:         g.Tool.Name,
+			Title:        g.Tool.Title,
+			Description:  g.Tool.Description,
+			Source:       g.Source,
+			InputSchema:  g.Tool.InputSchema,
+			OutputSchema: g.Tool.OutputSchema,
+			Invocation:   toolDumpInvocation{Type: g.Details.Type, Host: host},
+		})
+	}
+
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tools: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// writeValidationReport writes one line per schema source to w, followed by its
+// skipped operations and why they were skipped. It reports whether every source
+// generated all of its operations.
+func writeValidationReport(w io.Writer, reports []usecase.SourceReport) bool {
+	ok := true
+	for _, r := range reports {
+		switch {
+		case r.Err != nil:
+			ok = false
+			fmt.Fprintf(w, "FAIL %s: %v\n", r.Source, r.Err)
+		case len(r.Skipped) > 0:
+			ok = false
+			fmt.Fprintf(w, "WARN %s: %d tools, %d operations skipped\n", r.Source, r.Tools, len(r.Skipped))
+		default:
+			fmt.Fprintf(w, "OK   %s: %d tools\n", r.Source, r.Tools)
+		}
+		for _, op := range r.Skipped {
+			fmt.Fprintf(w, "       %s: %s\n", op, op.Reason)
+		}
+	}
+	return ok
+}
+
+// newHTTPClient returns the HTTP client shared by the schema fetchers and invokers,
+// with the configured overall, dial, TLS handshake, and response header timeouts
+// and connection pool limits.
+func newHTTPClient(cfg *configs.Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   cfg.HTTPDialTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = cfg.HTTPTLSHandshakeTimeout
+	transport.ResponseHeaderTimeout = cfg.HTTPResponseHeaderTimeout
+	transport.MaxIdleConns = cfg.HTTPMaxIdleConns
+	transport.MaxIdleConnsPerHost = cfg.HTTPMaxIdleConnsPerHost
+	transport.IdleConnTimeout = cfg.HTTPIdleConnTimeout
+	return &http.Client{
+		Timeout:   cfg.HTTPClientTimeout,
+		Transport: transport,
+	}
+}
+
+// grpcMessageSizeOptions returns the dial options applying the configured gRPC message
+// size limits to every call; a zero limit keeps the gRPC default.
+func grpcMessageSizeOptions(maxSend, maxRecv int) []grpc.DialOption {
+	var callOpts []grpc.CallOption
+	if maxSend > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(maxSend))
+	}
+	if maxRecv > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(maxRecv))
+	}
+	if len(callOpts) == 0 {
+		return nil
+	}
+	return []grpc.DialOption{grpc.WithDefaultCallOptions(callOpts...)}
+}
+
+// newFetchers returns the schema fetchers keyed by the schema type they serve.
+func newFetchers(httpClient *http.Client, grpcReadiness grpcadapter.Readiness, grpcRetry grpcadapter.ReflectionRetry, logger *slog.Logger, grpcDialOpts ...grpc.DialOption) map[domain.SchemaType]usecase.SchemaFetcher {
+	grpcFetcher := grpcadapter.NewSchemaFetcherWithReadiness(logger, grpcReadiness, grpcDialOpts...)
+	grpcFetcher.SetReflectionRetry(grpcRetry)
+	return map[domain.SchemaType]usecase.SchemaFetcher{
+		domain.SchemaTypeOpenAPI: openapi.NewSchemaFetcher(httpClient, logger),
+		domain.SchemaTypeGRPC:    grpcFetcher,
+		domain.SchemaTypeGitHub:  github.NewFetcher(logger),
+		domain.SchemaTypeProto:   protoadapter.NewSchemaFetcher(httpClient, logger),
+		domain.SchemaTypeConnect: connectadapter.NewSchemaFetcher(logger),
+	}
+}
+
+// generatorOptions configure the tool generators created by newGenerators.
+type generatorOptions struct {
+	openAPI []openapi.GeneratorOption     // e.g. the default host and body parameter name
+	grpc    []grpcadapter.GeneratorOption // e.g. the package components of tool names
+}
+
+// newGenerators returns the tool generators keyed by the schema type they handle.
+func newGenerators(logger *slog.Logger, opts generatorOptions) map[domain.SchemaType]usecase.ToolGenerator {
+	protoGenerator := protoadapter.NewGenerator(logger)
+	return map[domain.SchemaType]usecase.ToolGenerator{
+		domain.SchemaTypeOpenAPI:      openapi.NewToolGenerator(logger, opts.openAPI...),
+		domain.SchemaTypeGRPC:         grpcadapter.NewToolGenerator(logger, opts.grpc...),
+		domain.SchemaTypeProto:        protoGenerator,
+		domain.SchemaTypeConnect:      connectadapter.NewGenerator(logger),
+		domain.SchemaTypeConnectProto: protoGenerator, // Reuse proto generator for Connect+proto
+	}
+}
+
 // initOtelProvider initializes the OpenTelemetry SDK and sets up the OTLP trace exporter.
 // It returns a shutdown function to be called on application exit.
 func initOtelProvider(cfg *configs.Config) (func(context.Context) error, error) {
@@ -296,9 +657,14 @@ func initOtelProvider(cfg *configs.Config) (func(context.Context) error, error)
 		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 		slog.Warn("Using insecure connection for OTLP exporter.") // Log warning for insecure.
 	} else {
-		// TODO: Add logic to load system CAs or custom TLS config for secure connection.
-		slog.Info("Using secure connection for OTLP exporter (assuming system CAs). Adjust if needed.")
-		// grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, ""))) // Example
+		creds, err := otlpTransportCredentials(cfg.OtelExporterOtlpCertificate, cfg.OtelExporterOtlpClientCertificate, cfg.OtelExporterOtlpClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure TLS for OTLP exporter: %w", err)
+		}
+		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(creds))
+		slog.Info("Using secure connection for OTLP exporter.",
+			slog.String("ca_file", cfg.OtelExporterOtlpCertificate),
+			slog.Bool("mtls", cfg.OtelExporterOtlpClientCertificate != ""))
 	}
 	// TODO: Add other grpc.DialOption if needed (e.g., WithBlock).
 
@@ -314,13 +680,7 @@ func initOtelProvider(cfg *configs.Config) (func(context.Context) error, error)
 	}
 
 	// Define application resource attributes.
-	r, err := resource.Merge(
-		resource.Default(),
-		resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceNameKey.String("mcpizer"),
-		),
-	)
+	r, err := newOtelResource(ctx, cfg)
 	if err != nil {
 		_ = traceExporter.Shutdown(ctx)
 		_ = conn.Close()
@@ -347,4 +707,67 @@ func initOtelProvider(cfg *configs.Config) (func(context.Context) error, error)
 	}, nil
 }
 
+// newOtelResource describes this process for exported telemetry. Configured service
+// version/namespace are applied first so OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME
+// (e.g., service.instance.id, deployment.environment) can override them.
+func newOtelResource(ctx context.Context, cfg *configs.Config) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{semconv.ServiceNameKey.String("mcpizer")}
+	if cfg.OtelServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersionKey.String(cfg.OtelServiceVersion))
+	}
+	if cfg.OtelServiceNamespace != "" {
+		attrs = append(attrs, semconv.ServiceNamespaceKey.String(cfg.OtelServiceNamespace))
+	}
+
+	configured, err := resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(attrs...),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return resource.Merge(resource.Default(), configured)
+}
+
+// otlpTransportCredentials builds TLS credentials for the OTLP exporter connection.
+// With no CA file the system roots are used. A client certificate and key enable mTLS
+// and must be configured together.
+func otlpTransportCredentials(caFile, certFile, keyFile string) (credentials.TransportCredentials, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("both client certificate and client key must be set for mTLS")
+	}
+
+	if certFile == "" {
+		if caFile == "" {
+			return credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}), nil
+		}
+		creds, err := credentials.NewClientTLSFromFile(caFile, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load CA file %s: %w", caFile, err)
+		}
+		return creds, nil
+	}
+
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %w", caFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no valid certificates found in CA file %s", caFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	clientCert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	tlsCfg.Certificates = []tls.Certificate{clientCert}
+
+	return credentials.NewTLS(tlsCfg), nil
+}
+
 // DummyInvoker removed as we now have a real (connect) invoker
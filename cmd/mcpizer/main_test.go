@@ -0,0 +1,523 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpGoServer "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/i2y/mcpizer/configs"
+	connectadapter "github.com/i2y/mcpizer/internal/adapter/outbound/connect"
+	grpcadapter "github.com/i2y/mcpizer/internal/adapter/outbound/grpc"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/grpcinvoker"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/invoker"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/tlsclient"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+// writeTestCertificate writes a self-signed certificate and its key as PEM files into dir.
+func writeTestCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "mcpizer-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+func TestOtlpTransportCredentials(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir)
+
+	t.Run("system roots", func(t *testing.T) {
+		creds, err := otlpTransportCredentials("", "", "")
+		require.NoError(t, err)
+		assert.Equal(t, "tls", creds.Info().SecurityProtocol)
+	})
+
+	t.Run("custom CA file", func(t *testing.T) {
+		creds, err := otlpTransportCredentials(certFile, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, "tls", creds.Info().SecurityProtocol)
+	})
+
+	t.Run("custom CA with client certificate", func(t *testing.T) {
+		creds, err := otlpTransportCredentials(certFile, certFile, keyFile)
+		require.NoError(t, err)
+		assert.Equal(t, "tls", creds.Info().SecurityProtocol)
+	})
+
+	t.Run("missing CA file", func(t *testing.T) {
+		_, err := otlpTransportCredentials(filepath.Join(dir, "missing.pem"), "", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("client certificate without key", func(t *testing.T) {
+		_, err := otlpTransportCredentials(certFile, certFile, "")
+		assert.ErrorContains(t, err, "both client certificate and client key")
+	})
+}
+
+func TestNewOtelResource(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=staging,service.instance.id=instance-1")
+
+	cfg := &configs.Config{
+		OtelServiceVersion:   "1.2.3",
+		OtelServiceNamespace: "tools",
+	}
+	res, err := newOtelResource(context.Background(), cfg)
+	require.NoError(t, err)
+
+	attrs := make(map[string]string)
+	for _, kv := range res.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	assert.Equal(t, "mcpizer", attrs["service.name"])
+	assert.Equal(t, "1.2.3", attrs["service.version"])
+	assert.Equal(t, "tools", attrs["service.namespace"])
+	assert.Equal(t, "staging", attrs["deployment.environment"])
+	assert.Equal(t, "instance-1", attrs["service.instance.id"])
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	cfg := &configs.Config{
+		HTTPClientTimeout:         5 * time.Second,
+		HTTPDialTimeout:           2 * time.Second,
+		HTTPTLSHandshakeTimeout:   3 * time.Second,
+		HTTPResponseHeaderTimeout: 50 * time.Millisecond,
+	}
+	client := newHTTPClient(cfg)
+	assert.Equal(t, 5*time.Second, client.Timeout)
+
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Second, transport.TLSHandshakeTimeout)
+	assert.Equal(t, 50*time.Millisecond, transport.ResponseHeaderTimeout)
+	assert.NotNil(t, transport.DialContext)
+	assert.NotNil(t, transport.Proxy, "proxy settings from the environment are kept")
+
+	// A server that never sends headers fails at the response header timeout, not the overall timeout.
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	start := time.Now()
+	_, err := client.Get(srv.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout awaiting response headers")
+	assert.Less(t, time.Since(start), cfg.HTTPClientTimeout)
+}
+
+func TestNewHTTPClient_ConnectionPool(t *testing.T) {
+	client := newHTTPClient(&configs.Config{
+		HTTPMaxIdleConns:        500,
+		HTTPMaxIdleConnsPerHost: 50,
+		HTTPIdleConnTimeout:     2 * time.Minute,
+	})
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 500, transport.MaxIdleConns)
+	assert.Equal(t, 50, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 2*time.Minute, transport.IdleConnTimeout)
+
+	// The shared default transport is left untouched.
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+	assert.Equal(t, 100, defaultTransport.MaxIdleConns)
+	assert.Equal(t, 90*time.Second, defaultTransport.IdleConnTimeout)
+}
+
+func TestH2C_ConnectInvoker(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"proto": r.Proto})
+	}))
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true) // h2c only, no HTTP/1.1
+	srv.Start()
+	defer srv.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tests := []struct {
+		name      string
+		client    *http.Client
+		wantProto string
+	}{
+		{name: "h2c", client: tlsclient.H2C(&http.Client{Timeout: 5 * time.Second}), wantProto: "HTTP/2.0"},
+		{name: "default transport", client: &http.Client{Timeout: 5 * time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := connectadapter.NewInvokerWithClient(tt.client, logger)
+			result, err := inv.InvokeHTTP(context.Background(), srv.URL, "/greeter.v1.Greeter/SayHello", map[string]interface{}{})
+			if tt.wantProto == "" {
+				require.Error(t, err, "an HTTP/1.1 request must not reach an h2c-only server")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{"proto": tt.wantProto}, result)
+		})
+	}
+}
+
+// writeGreeterProto writes a minimal greeter service definition and returns its path.
+func writeGreeterProto(t *testing.T) string {
+	t.Helper()
+	protoFile := filepath.Join(t.TempDir(), "greeter.proto")
+	require.NoError(t, os.WriteFile(protoFile, []byte(`syntax = "proto3";
+package greeter.v1;
+
+message HelloRequest { string name = 1; }
+message HelloReply { string message = 1; }
+
+service Greeter {
+  rpc SayHello(HelloRequest) returns (HelloReply);
+}
+`), 0600))
+	return protoFile
+}
+
+// recordingMCPServer collects the tools registered by a sync.
+type recordingMCPServer struct {
+	tools map[string]mcp.Tool
+}
+
+func (s *recordingMCPServer) AddTool(tool mcp.Tool, _ mcpGoServer.ToolHandlerFunc) {
+	s.tools[tool.Name] = tool
+}
+
+func (s *recordingMCPServer) DeleteTools(names ...string) {
+	for _, name := range names {
+		delete(s.tools, name)
+	}
+}
+
+func TestNewFetchersAndGenerators_ProtoSourceEndToEnd(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	fetchers := newFetchers(http.DefaultClient, grpcadapter.Readiness{}, grpcadapter.ReflectionRetry{}, logger)
+	generators := newGenerators(logger, generatorOptions{})
+	require.NoError(t, usecase.CheckRegistrations(fetchers, generators))
+
+	protoFile := writeGreeterProto(t)
+
+	server := &recordingMCPServer{tools: map[string]mcp.Tool{}}
+	syncUC := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: "file://" + protoFile, Server: "grpc://localhost:50051"}},
+		fetchers,
+		generators,
+		server,
+		invoker.NewRouter(nil, nil, nil, logger),
+		logger,
+	)
+
+	require.NoError(t, syncUC.SyncAllConfiguredSources(context.Background()))
+	require.Contains(t, server.tools, "Greeter_SayHello")
+	assert.Contains(t, server.tools["Greeter_SayHello"].InputSchema.Properties, "name")
+}
+
+func TestSyncSchemaUseCase_DiscoverAllVersionedSpecs(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	spec := func(version string) string {
+		return `{"openapi": "3.0.0", "info": {"title": "Pets", "version": "` + version + `"},
+"servers": [{"url": "http://pets.example.com/` + version + `"}],
+"paths": {"/pets": {"get": {"operationId": "listPets", "responses": {"200": {"description": "ok"}}}}}}`
+	}
+	mux := http.NewServeMux()
+	for _, version := range []string{"v1", "v2"} {
+		body := spec(version)
+		mux.HandleFunc("/"+version+"/openapi.json", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, body)
+		})
+	}
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	server := &recordingMCPServer{tools: map[string]mcp.Tool{}}
+	syncUC := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: srv.URL, DiscoverAll: true}},
+		newFetchers(srv.Client(), grpcadapter.Readiness{}, grpcadapter.ReflectionRetry{}, logger),
+		newGenerators(logger, generatorOptions{}),
+		server,
+		invoker.NewRouter(nil, nil, nil, logger),
+		logger,
+	)
+
+	require.NoError(t, syncUC.SyncAllConfiguredSources(context.Background()))
+	var names []string
+	for name := range server.tools {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"v1_pets_listpets", "v2_pets_listpets"}, names)
+}
+
+func TestWriteToolDump(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	source := "file://" + writeGreeterProto(t)
+	syncUC := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: source, Server: "grpc://localhost:50051"}},
+		newFetchers(http.DefaultClient, grpcadapter.Readiness{}, grpcadapter.ReflectionRetry{}, logger),
+		newGenerators(logger, generatorOptions{}),
+		&recordingMCPServer{tools: map[string]mcp.Tool{}},
+		invoker.NewRouter(nil, nil, nil, logger),
+		logger,
+	)
+
+	generated, err := syncUC.GenerateAll(context.Background())
+	require.NoError(t, err)
+
+	dumpFile := filepath.Join(t.TempDir(), "tools.json")
+	require.NoError(t, writeToolDump(dumpFile, generated))
+
+	data, err := os.ReadFile(dumpFile)
+	require.NoError(t, err)
+	var dump []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &dump))
+	require.Len(t, dump, 1)
+
+	entry := dump[0]
+	assert.Equal(t, "Greeter_SayHello", entry["name"])
+	assert.Equal(t, source, entry["source"])
+	assert.Equal(t, map[string]interface{}{"type": "grpc", "host": "grpc://localhost:50051"}, entry["invocation"])
+	assert.Equal(t, map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+	}, entry["inputSchema"])
+	assert.Contains(t, entry, "outputSchema")
+}
+
+func TestWriteValidationReport(t *testing.T) {
+	const spec = `openapi: 3.0.0
+info:
+  title: Notes
+  version: 1.0.0
+servers:
+  - url: https://notes.example.com
+paths:
+  /notes:
+    get:
+      responses:
+        "200":
+          description: ok
+    put:
+      parameters:
+        - {name: body, in: query, schema: {type: string}}
+      requestBody:
+        content:
+          application/json:
+            schema: {type: array, items: {type: string}}
+      responses:
+        "204":
+          description: replaced
+`
+	dir := t.TempDir()
+	specFile := filepath.Join(dir, "notes.yaml")
+	require.NoError(t, os.WriteFile(specFile, []byte(spec), 0o600))
+	missingFile := filepath.Join(dir, "missing.yaml")
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	syncUC := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: specFile}, {URL: missingFile}},
+		newFetchers(http.DefaultClient, grpcadapter.Readiness{}, grpcadapter.ReflectionRetry{}, logger),
+		newGenerators(logger, generatorOptions{}),
+		&recordingMCPServer{tools: map[string]mcp.Tool{}},
+		invoker.NewRouter(nil, nil, nil, logger),
+		logger,
+	)
+
+	var out strings.Builder
+	assert.False(t, writeValidationReport(&out, syncUC.Validate(context.Background())))
+	lines := strings.Split(out.String(), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "WARN "+specFile+": 1 tools, 1 operations skipped", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "       PUT /notes (line 13): cannot represent non-object request body"), lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "FAIL "+missingFile+": "), lines[2])
+}
+
+func TestCheckInitialSync(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	newSyncUC := func(source string) *usecase.SyncSchemaUseCase {
+		return usecase.NewSyncSchemaUseCase(
+			[]usecase.SchemaSourceConfig{{URL: source, Server: "grpc://localhost:50051"}},
+			newFetchers(http.DefaultClient, grpcadapter.Readiness{}, grpcadapter.ReflectionRetry{}, logger),
+			newGenerators(logger, generatorOptions{}),
+			&recordingMCPServer{tools: map[string]mcp.Tool{}},
+			invoker.NewRouter(nil, nil, nil, logger),
+			logger,
+		)
+	}
+	missing := "file://" + filepath.Join(t.TempDir(), "missing.proto")
+
+	tests := []struct {
+		name        string
+		source      string
+		failOnError bool
+		wantErr     bool
+	}{
+		{name: "successful sync", source: "file://" + writeGreeterProto(t), failOnError: true},
+		{name: "failed sync continues by default", source: missing},
+		{name: "failed sync fails fast", source: missing, failOnError: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncErr := newSyncUC(tt.source).SyncAllConfiguredSources(context.Background())
+			err := checkInitialSync(logger, syncErr, tt.failOnError)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, "missing.proto")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGRPCMessageSizeOptions(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	target := "grpc://" + lis.Addr().String()
+	largeRequest := map[string]interface{}{"service": strings.Repeat("x", 2048)}
+
+	tests := []struct {
+		name         string
+		maxSend      int
+		maxRecv      int
+		wantFetchErr bool
+		wantCallErr  string
+	}{
+		{name: "defaults", wantCallErr: "NotFound"},
+		{name: "send limit below request size", maxSend: 1024, wantCallErr: "larger than max"},
+		{name: "send limit above request size", maxSend: 4096, wantCallErr: "NotFound"},
+		{name: "receive limit below reflection response size", maxRecv: 16, wantFetchErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := grpcMessageSizeOptions(tt.maxSend, tt.maxRecv)
+			if tt.maxSend == 0 && tt.maxRecv == 0 {
+				assert.Empty(t, opts)
+			}
+
+			fetcher := grpcadapter.NewSchemaFetcher(logger, opts...)
+			_, err := fetcher.Fetch(context.Background(), target)
+			if tt.wantFetchErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			inv := grpcinvoker.NewInvoker(logger, grpcinvoker.WithDialOptions(opts...))
+			_, err = inv.InvokeGRPC(context.Background(), target, "grpc.health.v1.Health", "Check", largeRequest)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantCallErr)
+		})
+	}
+}
+
+func TestNewMCPServer_Instructions(t *testing.T) {
+	instructionsFile := filepath.Join(t.TempDir(), "instructions.md")
+	require.NoError(t, os.WriteFile(instructionsFile, []byte("Look up the customer before creating orders.\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		cfg     configs.Config
+		want    string
+		wantErr bool
+	}{
+		{name: "none"},
+		{
+			name: "text",
+			cfg:  configs.Config{ServerInstructions: "Prefer the billing_* tools for invoices."},
+			want: "Prefer the billing_* tools for invoices.",
+		},
+		{
+			name: "file",
+			cfg:  configs.Config{ServerInstructionsFile: instructionsFile},
+			want: "Look up the customer before creating orders.",
+		},
+		{
+			name:    "missing file",
+			cfg:     configs.Config{ServerInstructionsFile: filepath.Join(t.TempDir(), "missing.md")},
+			wantErr: true,
+		},
+		{
+			name:    "text and file",
+			cfg:     configs.Config{ServerInstructions: "text", ServerInstructionsFile: instructionsFile},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instructions, err := serverInstructions(&tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			srv := newMCPServer(instructions)
+			resp := srv.HandleMessage(context.Background(), json.RawMessage(
+				`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}`))
+			rpcResp, ok := resp.(mcp.JSONRPCResponse)
+			require.True(t, ok, "unexpected response %#v", resp)
+			result, ok := rpcResp.Result.(mcp.InitializeResult)
+			require.True(t, ok, "unexpected result %#v", rpcResp.Result)
+			assert.Equal(t, tt.want, result.Instructions)
+		})
+	}
+}
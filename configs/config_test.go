@@ -0,0 +1,213 @@
+package configs_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/configs"
+)
+
+func TestLoad_EnvSchemaSources(t *testing.T) {
+	fileConfig := `
+schema_sources:
+  - https://petstore.example.com/openapi.json
+  - url: grpc://users.example.com:50051
+    headers:
+      Authorization: Bearer token
+`
+
+	tests := []struct {
+		name       string
+		configFile string // written to a temp file when set; otherwise the file does not exist
+		envSources string
+		want       []configs.SchemaSource
+	}{
+		{
+			name:       "merged with file sources",
+			configFile: fileConfig,
+			envSources: "https://orders.example.com/openapi.json, grpc://users.example.com:50051",
+			want: []configs.SchemaSource{
+				{URL: "https://petstore.example.com/openapi.json"},
+				{URL: "grpc://users.example.com:50051", Headers: map[string]string{"Authorization": "Bearer token"}},
+				{URL: "https://orders.example.com/openapi.json"},
+			},
+		},
+		{
+			name:       "without a config file",
+			envSources: "https://orders.example.com/openapi.json,grpc://users.example.com:50051",
+			want: []configs.SchemaSource{
+				{URL: "https://orders.example.com/openapi.json"},
+				{URL: "grpc://users.example.com:50051"},
+			},
+		},
+		{
+			name:       "file sources only",
+			configFile: fileConfig,
+			want: []configs.SchemaSource{
+				{URL: "https://petstore.example.com/openapi.json"},
+				{URL: "grpc://users.example.com:50051", Headers: map[string]string{"Authorization": "Bearer token"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "mcpizer.yaml")
+			if tt.configFile != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.configFile), 0o600))
+			}
+			t.Setenv("MCPIZER_CONFIG_FILE", path)
+			t.Setenv("MCPIZER_SCHEMA_SOURCES", tt.envSources)
+
+			cfg, err := configs.Load()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.SchemaSources)
+		})
+	}
+}
+
+func TestLoad_MissingConfigFile(t *testing.T) {
+	t.Setenv("MCPIZER_CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+	t.Setenv("MCPIZER_SCHEMA_SOURCES", "")
+
+	_, err := configs.Load()
+	assert.ErrorContains(t, err, "failed to read config file")
+}
+
+func TestLoad_DisabledSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcpizer.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+schema_sources:
+  - url: https://orders.example.com/openapi.json
+  - url: https://flaky.example.com/openapi.json
+    disabled: true
+`), 0o600))
+	t.Setenv("MCPIZER_CONFIG_FILE", path)
+	t.Setenv("MCPIZER_SCHEMA_SOURCES", "")
+
+	cfg, err := configs.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []configs.SchemaSource{
+		{URL: "https://orders.example.com/openapi.json"},
+		{URL: "https://flaky.example.com/openapi.json", Disabled: true},
+	}, cfg.SchemaSources)
+}
+
+func TestLoad_PathRewrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		rewrite string
+		want    configs.SourcePathRewrite
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			rewrite: "{pattern: ^/api, replacement: /v2}",
+			want:    configs.SourcePathRewrite{Pattern: "^/api", Replacement: "/v2", Regexp: regexp.MustCompile("^/api")},
+		},
+		{name: "missing pattern", rewrite: "{replacement: /v2}", wantErr: "path_rewrite needs a pattern"},
+		{name: "invalid pattern", rewrite: "{pattern: \"(\"}", wantErr: "invalid path_rewrite pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "mcpizer.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(`
+schema_sources:
+  - url: https://gateway.example.com/openapi.json
+    path_rewrite: `+tt.rewrite+`
+`), 0o600))
+			t.Setenv("MCPIZER_CONFIG_FILE", path)
+			t.Setenv("MCPIZER_SCHEMA_SOURCES", "")
+
+			cfg, err := configs.Load()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, cfg.SchemaSources, 1)
+			assert.Equal(t, tt.want, cfg.SchemaSources[0].PathRewrite)
+		})
+	}
+}
+
+func TestLoad_ResponseStatuses(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses string
+		want     []string
+		wantErr  string
+	}{
+		{name: "codes and default", statuses: `[202, "2xx", Default]`, want: []string{"202", "2XX", "default"}},
+		{name: "invalid code", statuses: `[accepted]`, wantErr: "response_statuses entry accepted"},
+		{name: "out of range", statuses: `[600]`, wantErr: "response_statuses entry 600"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "mcpizer.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(`
+schema_sources:
+  - url: https://exports.example.com/openapi.json
+    response_statuses: `+tt.statuses+`
+`), 0o600))
+			t.Setenv("MCPIZER_CONFIG_FILE", path)
+			t.Setenv("MCPIZER_SCHEMA_SOURCES", "")
+
+			cfg, err := configs.Load()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, cfg.SchemaSources, 1)
+			assert.Equal(t, tt.want, cfg.SchemaSources[0].ResponseStatuses)
+		})
+	}
+}
+
+func TestLoad_SourceLimits(t *testing.T) {
+	tests := []struct {
+		name            string
+		limits          string
+		wantMaxTools    int
+		wantMaxInFlight int
+		wantErr         string
+	}{
+		{name: "limits", limits: "max_tools: 50\n    max_in_flight: 2", wantMaxTools: 50, wantMaxInFlight: 2},
+		{name: "negative max_tools", limits: "max_tools: -1", wantErr: "max_tools must not be negative"},
+		{name: "negative max_in_flight", limits: "max_in_flight: -1", wantErr: "max_in_flight must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "mcpizer.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(`
+schema_sources:
+  - url: https://exports.example.com/openapi.json
+    `+tt.limits+`
+`), 0o600))
+			t.Setenv("MCPIZER_CONFIG_FILE", path)
+			t.Setenv("MCPIZER_SCHEMA_SOURCES", "")
+
+			cfg, err := configs.Load()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, cfg.SchemaSources, 1)
+			assert.Equal(t, tt.wantMaxTools, cfg.SchemaSources[0].MaxTools)
+			assert.Equal(t, tt.wantMaxInFlight, cfg.SchemaSources[0].MaxInFlight)
+		})
+	}
+}
@@ -2,9 +2,11 @@ package mcphttp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sort"
 
 	"github.com/i2y/mcpizer/internal/usecase" // Only need SyncSchemaUseCase
 )
@@ -12,18 +14,43 @@ import (
 // Handlers struct holds dependencies for the HTTP handlers.
 type Handlers struct {
 	syncSchemaUseCase *usecase.SyncSchemaUseCase
+	serveToolsUseCase *usecase.ServeToolsUseCase
+	loadSources       func() ([]usecase.SchemaSourceConfig, error)
 	logger            *slog.Logger
 }
 
+// HandlerOption configures optional Handlers dependencies.
+type HandlerOption func(*Handlers)
+
+// WithServeTools enables GET /admin/tools, listing the tools stored in the repository.
+func WithServeTools(serveUC *usecase.ServeToolsUseCase) HandlerOption {
+	return func(h *Handlers) {
+		h.serveToolsUseCase = serveUC
+	}
+}
+
+// WithReload enables POST /admin/reload, which replaces the configured sources
+// with the ones returned by load, e.g. re-read from the config file.
+func WithReload(load func() ([]usecase.SchemaSourceConfig, error)) HandlerOption {
+	return func(h *Handlers) {
+		h.loadSources = load
+	}
+}
+
 // NewHandlers creates a new Handlers struct.
 func NewHandlers(
 	syncUC *usecase.SyncSchemaUseCase,
 	logger *slog.Logger,
+	opts ...HandlerOption,
 ) *Handlers {
-	return &Handlers{
+	h := &Handlers{
 		syncSchemaUseCase: syncUC,
 		logger:            logger.With("component", "mcphttp_handler"),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // RegisterAdminRoutes sets up the HTTP routes for admin endpoints.
@@ -31,9 +58,43 @@ func NewHandlers(
 func (h *Handlers) RegisterAdminRoutes(mux *http.ServeMux) {
 	// Admin/Management Endpoints
 	mux.HandleFunc("POST /admin/sync", h.handleSyncSchema)
+	mux.HandleFunc("POST /admin/sources", h.handleSyncSources)
+	mux.HandleFunc("DELETE /admin/sources", h.handleRemoveSource)
+	mux.HandleFunc("GET /admin/summary", h.handleSummary)
+	if h.serveToolsUseCase != nil {
+		mux.HandleFunc("GET /admin/tools", h.handleListTools)
+	}
+	if h.loadSources != nil {
+		mux.HandleFunc("POST /admin/reload", h.handleReload)
+	}
 }
 
-// SyncRequest defines the expected JSON body for the /admin/sync endpoint.
+// handleListTools implements GET /admin/tools, returning the stored tools sorted by name.
+func (h *Handlers) handleListTools(w http.ResponseWriter, r *http.Request) {
+	tools, err := h.serveToolsUseCase.Execute(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list tools", slog.Any("error", err))
+		http.Error(w, fmt.Sprintf("Failed to list tools: %v", err), http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tools); err != nil {
+		h.logger.Warn("Failed to write tool list", slog.Any("error", err))
+	}
+}
+
+// handleSummary implements GET /admin/summary, returning the number of registered
+// tools per source and in total.
+func (h *Handlers) handleSummary(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.syncSchemaUseCase.Summary()); err != nil {
+		h.logger.Warn("Failed to write tool summary", slog.Any("error", err))
+	}
+}
+
+// SyncRequest defines the expected JSON body for the /admin/sync and DELETE /admin/sources endpoints.
 type SyncRequest struct {
 	Source string `json:"source"`
 }
@@ -73,6 +134,171 @@ func (h *Handlers) handleSyncSchema(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("Sync request accepted", slog.String("source", req.Source))
 }
 
+// SourceRequest is one source in the JSON array accepted by POST /admin/sources.
+// Its fields mirror the schema_sources entries of the config file.
+type SourceRequest struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Server  string            `json:"server,omitempty"`
+	Type    string            `json:"type,omitempty"`
+	Mode    string            `json:"mode,omitempty"`
+}
+
+// SourceResult reports the outcome of syncing one source in POST /admin/sources.
+type SourceResult struct {
+	Source string `json:"source"`
+	Status string `json:"status"` // "synced" or "failed"
+	Error  string `json:"error,omitempty"`
+}
+
+// handleSyncSources implements POST /admin/sources, syncing every source in a JSON
+// array and adding the ones that succeed to the configured set. It responds with
+// one result per source: 200 if all synced, 207 if any failed.
+func (h *Handlers) handleSyncSources(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var reqs []SourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		h.logger.Warn("Failed to decode sources request body", slog.Any("error", err))
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(reqs) == 0 {
+		http.Error(w, "Request body must be a non-empty array of sources", http.StatusBadRequest)
+		return
+	}
+
+	sources := make([]usecase.SchemaSourceConfig, len(reqs))
+	for i, req := range reqs {
+		if req.URL == "" {
+			http.Error(w, fmt.Sprintf("Missing 'url' field in source %d", i), http.StatusBadRequest)
+			return
+		}
+		sources[i] = usecase.SchemaSourceConfig{
+			URL:     req.URL,
+			Headers: req.Headers,
+			Server:  req.Server,
+			Type:    req.Type,
+			Mode:    req.Mode,
+		}
+	}
+
+	h.logger.Info("Received sources sync request", slog.Int("source_count", len(sources)))
+	status := http.StatusOK
+	results := make([]SourceResult, 0, len(sources))
+	for _, res := range h.syncSchemaUseCase.SyncSources(r.Context(), sources) {
+		result := SourceResult{Source: res.Source, Status: "synced"}
+		if res.Err != nil {
+			result.Status = "failed"
+			result.Error = res.Err.Error()
+			status = http.StatusMultiStatus
+		}
+		results = append(results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		h.logger.Warn("Failed to write sources sync results", slog.Any("error", err))
+	}
+}
+
+// RemoveSourceResponse is the JSON body returned by DELETE /admin/sources.
+type RemoveSourceResponse struct {
+	Source       string   `json:"source"`
+	RemovedTools []string `json:"removed_tools"`
+}
+
+// handleRemoveSource implements DELETE /admin/sources, dropping the source named in
+// the body ({"source": "<url>"}) from the configured set along with all its tools.
+func (h *Handlers) handleRemoveSource(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var req SyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Failed to decode remove source request body", slog.Any("error", err))
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Source == "" {
+		http.Error(w, "Missing 'source' field in request body", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Info("Received remove source request", slog.String("source", req.Source))
+	removed, err := h.syncSchemaUseCase.RemoveSource(r.Context(), req.Source)
+	switch {
+	case errors.Is(err, usecase.ErrSourceNotFound):
+		http.Error(w, fmt.Sprintf("Source not found: %s", req.Source), http.StatusNotFound)
+		return
+	case err != nil:
+		h.logger.Error("Failed to remove source", slog.String("source", req.Source), slog.Any("error", err))
+		http.Error(w, fmt.Sprintf("Failed to remove source: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(RemoveSourceResponse{Source: req.Source, RemovedTools: nonNil(removed)}); err != nil {
+		h.logger.Warn("Failed to write remove source result", slog.Any("error", err))
+	}
+}
+
+// ReloadResponse is the JSON body returned by POST /admin/reload.
+type ReloadResponse struct {
+	Added   []string       `json:"added"`
+	Updated []string       `json:"updated"`
+	Removed []string       `json:"removed"`
+	Failed  []SourceResult `json:"failed,omitempty"`
+}
+
+// handleReload implements POST /admin/reload, reloading the source list and syncing
+// the difference to the running set. It responds 409 while another reload runs and
+// 207 if some added or updated sources failed to sync.
+func (h *Handlers) handleReload(w http.ResponseWriter, r *http.Request) {
+	sources, err := h.loadSources()
+	if err != nil {
+		h.logger.Error("Failed to load sources for reload", slog.Any("error", err))
+		http.Error(w, fmt.Sprintf("Failed to load config: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Received reload request", slog.Int("source_count", len(sources)))
+	result, err := h.syncSchemaUseCase.ReloadSources(r.Context(), sources)
+	if errors.Is(err, usecase.ErrReloadInProgress) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	status := http.StatusOK
+	resp := ReloadResponse{
+		Added:   nonNil(result.Added),
+		Updated: nonNil(result.Updated),
+		Removed: nonNil(result.Removed),
+	}
+	for _, res := range result.Failed {
+		resp.Failed = append(resp.Failed, SourceResult{Source: res.Source, Status: "failed", Error: res.Err.Error()})
+		status = http.StatusMultiStatus
+	}
+	if err != nil && len(result.Failed) == 0 {
+		h.logger.Error("Reload completed with errors", slog.Any("error", err))
+		http.Error(w, fmt.Sprintf("Failed to reload: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Warn("Failed to write reload result", slog.Any("error", err))
+	}
+}
+
+// nonNil returns s, or an empty slice if s is nil, so that it encodes as [] rather than null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
 // handleMCP, handleMCPPost, handleMCPGet, acceptsSSE, sendSSEEvent removed as main MCP handling
 // will be done by the mcp-go SSE server directly in main.go.
-// handleListTools also removed.
@@ -0,0 +1,310 @@
+package mcphttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/adapter/inbound/mcphttp"
+	"github.com/i2y/mcpizer/internal/domain"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+// stubFetcher returns an OpenAPI schema for every source except the ones in failing,
+// recording the fetched sources.
+type stubFetcher struct {
+	failing map[string]bool
+	fetched []string
+}
+
+func (f *stubFetcher) Fetch(_ context.Context, source string) (domain.APISchema, error) {
+	f.fetched = append(f.fetched, source)
+	if f.failing[source] {
+		return domain.APISchema{}, errors.New("connection refused")
+	}
+	return domain.APISchema{Source: source, Type: domain.SchemaTypeOpenAPI}, nil
+}
+
+func (f *stubFetcher) FetchWithConfig(ctx context.Context, config usecase.SchemaSourceConfig) (domain.APISchema, error) {
+	return f.Fetch(ctx, config.URL)
+}
+
+// stubGenerator generates a list and a get tool per schema, named after the schema's host.
+type stubGenerator struct{}
+
+func (stubGenerator) Generate(schema domain.APISchema) ([]domain.Tool, []usecase.InvocationDetails, error) {
+	host := strings.Split(strings.TrimPrefix(schema.Source, "http://"), ".")[0]
+	return []domain.Tool{
+			{Name: host + "_list", Description: "List " + host},
+			{Name: host + "_get", Description: "Get one of " + host},
+		},
+		[]usecase.InvocationDetails{
+			{Type: "http", Host: host, HTTPMethod: http.MethodGet, HTTPPath: "/"},
+			{Type: "http", Host: host, HTTPMethod: http.MethodGet, HTTPPath: "/{id}"},
+		},
+		nil
+}
+
+// recordingServer records the names of the registered tools.
+type recordingServer struct {
+	mu    sync.Mutex
+	tools map[string]bool
+}
+
+func (s *recordingServer) AddTool(tool mcp.Tool, _ server.ToolHandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools[tool.Name] = true
+}
+
+func (s *recordingServer) DeleteTools(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range names {
+		delete(s.tools, name)
+	}
+}
+
+type noopInvoker struct{}
+
+func (noopInvoker) Invoke(context.Context, usecase.InvocationDetails, map[string]interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+func newTestMux(t *testing.T, fetcher *stubFetcher, opts ...mcphttp.HandlerOption) (*http.ServeMux, *recordingServer, *usecase.SyncSchemaUseCase) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := &recordingServer{tools: make(map[string]bool)}
+	syncUC := usecase.NewSyncSchemaUseCase(
+		nil,
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: stubGenerator{}},
+		srv,
+		noopInvoker{},
+		logger,
+	)
+	mux := http.NewServeMux()
+	mcphttp.NewHandlers(syncUC, logger, opts...).RegisterAdminRoutes(mux)
+	return mux, srv, syncUC
+}
+
+func TestHandlers_SyncSources(t *testing.T) {
+	tests := []struct {
+		name       string
+		failing    map[string]bool
+		wantStatus int
+		wantTools  map[string]bool
+		wantResult []mcphttp.SourceResult
+		wantKept   []string
+	}{
+		{
+			name:       "all sources synced",
+			wantStatus: http.StatusOK,
+			wantTools:  map[string]bool{"pets_list": true, "pets_get": true, "users_list": true, "users_get": true},
+			wantResult: []mcphttp.SourceResult{
+				{Source: "http://pets.example.com/openapi.json", Status: "synced"},
+				{Source: "http://users.example.com/openapi.json", Status: "synced"},
+			},
+			wantKept: []string{"http://pets.example.com/openapi.json", "http://users.example.com/openapi.json"},
+		},
+		{
+			name:       "one source fails",
+			failing:    map[string]bool{"http://pets.example.com/openapi.json": true},
+			wantStatus: http.StatusMultiStatus,
+			wantTools:  map[string]bool{"users_list": true, "users_get": true},
+			wantResult: []mcphttp.SourceResult{
+				{Source: "http://pets.example.com/openapi.json", Status: "failed", Error: "failed to fetch schema: connection refused"},
+				{Source: "http://users.example.com/openapi.json", Status: "synced"},
+			},
+			wantKept: []string{"http://users.example.com/openapi.json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := &stubFetcher{failing: tt.failing}
+			mux, srv, syncUC := newTestMux(t, fetcher)
+
+			body := `[
+				{"url": "http://pets.example.com/openapi.json"},
+				{"url": "http://users.example.com/openapi.json", "headers": {"Authorization": "Bearer token"}}
+			]`
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/sources", strings.NewReader(body)))
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			var results []mcphttp.SourceResult
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &results))
+			assert.Equal(t, tt.wantResult, results)
+			assert.Equal(t, tt.wantTools, srv.tools)
+
+			// Synced sources join the configured set and are kept up to date by later syncs.
+			fetcher.fetched = nil
+			require.NoError(t, syncUC.SyncAllConfiguredSources(context.Background()))
+			assert.Equal(t, tt.wantKept, fetcher.fetched)
+		})
+	}
+}
+
+func TestHandlers_SyncSources_BadRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "not an array", body: `{"source": "http://pets.example.com/openapi.json"}`},
+		{name: "empty array", body: `[]`},
+		{name: "missing url", body: `[{"url": "http://pets.example.com/openapi.json"}, {"headers": {"X-Key": "v"}}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux, srv, _ := newTestMux(t, &stubFetcher{})
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/sources", strings.NewReader(tt.body)))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Empty(t, srv.tools)
+		})
+	}
+}
+
+func TestHandlers_Reload(t *testing.T) {
+	pets := usecase.SchemaSourceConfig{URL: "http://pets.example.com/openapi.json"}
+	users := usecase.SchemaSourceConfig{URL: "http://users.example.com/openapi.json"}
+	orders := usecase.SchemaSourceConfig{URL: "http://orders.example.com/openapi.json"}
+
+	configured := []usecase.SchemaSourceConfig{pets, users}
+	mux, srv, syncUC := newTestMux(t, &stubFetcher{}, mcphttp.WithReload(func() ([]usecase.SchemaSourceConfig, error) {
+		return configured, nil
+	}))
+	_, err := syncUC.ReloadSources(context.Background(), configured)
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"pets_list": true, "pets_get": true, "users_list": true, "users_get": true}, srv.tools)
+
+	// The config file now adds orders, drops users, and changes the headers of pets.
+	pets.Headers = map[string]string{"Authorization": "Bearer token"}
+	configured = []usecase.SchemaSourceConfig{pets, orders}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/reload", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp mcphttp.ReloadResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, mcphttp.ReloadResponse{
+		Added:   []string{orders.URL},
+		Updated: []string{pets.URL},
+		Removed: []string{users.URL},
+	}, resp)
+	assert.Equal(t, map[string]bool{"pets_list": true, "pets_get": true, "orders_list": true, "orders_get": true}, srv.tools)
+}
+
+// blockingFetcher blocks every fetch until release is closed.
+type blockingFetcher struct {
+	stubFetcher
+	started chan struct{}
+	release chan struct{}
+}
+
+func (f *blockingFetcher) FetchWithConfig(ctx context.Context, config usecase.SchemaSourceConfig) (domain.APISchema, error) {
+	f.started <- struct{}{}
+	<-f.release
+	return f.stubFetcher.FetchWithConfig(ctx, config)
+}
+
+func TestHandlers_Reload_Concurrent(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	fetcher := &blockingFetcher{started: make(chan struct{}), release: make(chan struct{})}
+	syncUC := usecase.NewSyncSchemaUseCase(
+		nil,
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: stubGenerator{}},
+		&recordingServer{tools: make(map[string]bool)},
+		noopInvoker{},
+		logger,
+	)
+	mux := http.NewServeMux()
+	mcphttp.NewHandlers(syncUC, logger, mcphttp.WithReload(func() ([]usecase.SchemaSourceConfig, error) {
+		return []usecase.SchemaSourceConfig{{URL: "http://pets.example.com/openapi.json"}}, nil
+	})).RegisterAdminRoutes(mux)
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mux.ServeHTTP(first, httptest.NewRequest(http.MethodPost, "/admin/reload", nil))
+	}()
+	<-fetcher.started
+
+	second := httptest.NewRecorder()
+	mux.ServeHTTP(second, httptest.NewRequest(http.MethodPost, "/admin/reload", nil))
+	assert.Equal(t, http.StatusConflict, second.Code)
+
+	close(fetcher.release)
+	<-done
+	assert.Equal(t, http.StatusOK, first.Code, first.Body.String())
+}
+
+func TestHandlers_Summary(t *testing.T) {
+	mux, _, syncUC := newTestMux(t, &stubFetcher{})
+	for _, res := range syncUC.SyncSources(context.Background(), []usecase.SchemaSourceConfig{
+		{URL: "http://users.example.com/openapi.json"},
+		{URL: "http://pets.example.com/openapi.json"},
+	}) {
+		require.NoError(t, res.Err)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/summary", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var summary usecase.ToolSummary
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+	assert.Equal(t, usecase.ToolSummary{
+		Sources: []usecase.SourceSummary{
+			{Source: "http://pets.example.com/openapi.json", Tools: 2, Sample: []string{"pets_get", "pets_list"}},
+			{Source: "http://users.example.com/openapi.json", Tools: 2, Sample: []string{"users_get", "users_list"}},
+		},
+		Total: 4,
+	}, summary)
+}
+
+func TestHandlers_RemoveSource(t *testing.T) {
+	mux, srv, syncUC := newTestMux(t, &stubFetcher{})
+	pets := usecase.SchemaSourceConfig{URL: "http://pets.example.com/openapi.json"}
+	users := usecase.SchemaSourceConfig{URL: "http://users.example.com/openapi.json"}
+	for _, res := range syncUC.SyncSources(context.Background(), []usecase.SchemaSourceConfig{pets, users}) {
+		require.NoError(t, res.Err)
+	}
+	require.Len(t, srv.tools, 4)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/sources", strings.NewReader(`{"source": "http://pets.example.com/openapi.json"}`)))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp mcphttp.RemoveSourceResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, mcphttp.RemoveSourceResponse{Source: pets.URL, RemovedTools: []string{"pets_get", "pets_list"}}, resp)
+	assert.Equal(t, map[string]bool{"users_list": true, "users_get": true}, srv.tools)
+
+	// The source is no longer configured, so a full sync does not bring its tools back.
+	require.NoError(t, syncUC.SyncAllConfiguredSources(context.Background()))
+	assert.Equal(t, map[string]bool{"users_list": true, "users_get": true}, srv.tools)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/sources", strings.NewReader(`{"source": "http://pets.example.com/openapi.json"}`)))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
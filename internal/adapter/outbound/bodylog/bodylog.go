@@ -0,0 +1,91 @@
+package bodylog
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMaxLength is used when Config.MaxLength is not positive.
+const DefaultMaxLength = 1024
+
+// redacted replaces the values of sensitive fields in logged bodies.
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are matched against normalized field names (lowercase, no '_' or '-').
+var sensitiveKeys = []string{"password", "passwd", "secret", "token", "apikey", "authorization", "credential", "privatekey", "cookie"}
+
+// Config controls debug logging of outbound request and upstream response bodies.
+// The zero value disables body logging.
+type Config struct {
+	// Enabled turns on body logging at debug level.
+	Enabled bool
+	// MaxLength truncates logged bodies to this many bytes, followed by an ellipsis.
+	MaxLength int
+}
+
+// Log writes body to log at debug level when body logging is enabled.
+func (c Config) Log(ctx context.Context, log *slog.Logger, msg string, body []byte) {
+	if !c.Enabled || !log.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	log.DebugContext(ctx, msg, slog.String("body", c.Format(body)), slog.Int("body_size", len(body)))
+}
+
+// Format redacts sensitive JSON fields in body and truncates the result to MaxLength.
+func (c Config) Format(body []byte) string {
+	text := string(body)
+	var decoded interface{}
+	if json.Unmarshal(body, &decoded) == nil {
+		if redactedBody, err := json.Marshal(redact(decoded)); err == nil {
+			text = string(redactedBody)
+		}
+	}
+
+	maxLength := c.MaxLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxLength
+	}
+	if len(text) <= maxLength {
+		return text
+	}
+	cut := maxLength
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "..."
+}
+
+// redact replaces values of sensitive keys throughout a decoded JSON value.
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitive(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redact(field)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redact(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isSensitive(key string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(normalized, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package bodylog_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/bodylog"
+)
+
+func TestConfig_Format(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  bodylog.Config
+		body string
+		want string
+	}{
+		{
+			name: "short body unchanged",
+			cfg:  bodylog.Config{Enabled: true, MaxLength: 100},
+			body: `plain text`,
+			want: `plain text`,
+		},
+		{
+			name: "long body truncated with ellipsis",
+			cfg:  bodylog.Config{Enabled: true, MaxLength: 5},
+			body: `abcdefghij`,
+			want: `abcde...`,
+		},
+		{
+			name: "sensitive JSON fields redacted",
+			cfg:  bodylog.Config{Enabled: true, MaxLength: 200},
+			body: `{"user":"alice","Password":"hunter2","nested":{"api_key":"k"},"items":[{"access-token":"t"}]}`,
+			want: `{"Password":"[REDACTED]","items":[{"access-token":"[REDACTED]"}],"nested":{"api_key":"[REDACTED]"},"user":"alice"}`,
+		},
+		{
+			name: "truncation does not split multi-byte runes",
+			cfg:  bodylog.Config{Enabled: true, MaxLength: 4},
+			body: `aあい`,
+			want: `aあ...`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cfg.Format([]byte(tt.body)))
+		})
+	}
+}
+
+func TestConfig_Log(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	body := []byte(strings.Repeat("x", 20))
+
+	bodylog.Config{}.Log(context.Background(), logger, "Upstream response body", body)
+	assert.Empty(t, buf.String(), "body logging should be off by default")
+
+	bodylog.Config{Enabled: true, MaxLength: 8}.Log(context.Background(), logger, "Upstream response body", body)
+	assert.Contains(t, buf.String(), "body=xxxxxxxx...")
+	assert.Contains(t, buf.String(), "body_size=20")
+}
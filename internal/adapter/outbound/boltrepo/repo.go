@@ -0,0 +1,177 @@
+package boltrepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/i2y/mcpizer/internal/domain"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+var (
+	toolsBucket   = []byte("tools")
+	detailsBucket = []byte("invocation_details")
+)
+
+// BoltToolRepository is a ToolRepository persisted in a BoltDB file, so that
+// tools survive a restart. Tools and invocation details are stored as JSON,
+// keyed by tool name.
+//
+// InvocationDetails.FileDescriptor is stored in its JSON form and is read back
+// as generic JSON, not as the original descriptor type.
+type BoltToolRepository struct {
+	db     *bolt.DB
+	logger *slog.Logger
+}
+
+// NewBoltToolRepository opens (or creates) the BoltDB file at path.
+// Close must be called to release the file lock.
+func NewBoltToolRepository(path string, logger *slog.Logger) (*BoltToolRepository, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tool repository %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{toolsBucket, detailsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize tool repository %s: %w", path, err)
+	}
+	return &BoltToolRepository{
+		db:     db,
+		logger: logger.With("component", "bolt_repo", "path", path),
+	}, nil
+}
+
+// Close closes the underlying database file.
+func (r *BoltToolRepository) Close() error {
+	return r.db.Close()
+}
+
+// Save stores the given tools and their corresponding invocation details in one transaction.
+// It assumes tools and details slices correspond by index and have the same length.
+func (r *BoltToolRepository) Save(ctx context.Context, tools []domain.Tool, details []usecase.InvocationDetails) error {
+	if len(tools) != len(details) {
+		msg := fmt.Sprintf("mismatch between number of tools (%d) and invocation details (%d)", len(tools), len(details))
+		r.logger.Error("Failed to save tools and details", slog.String("reason", msg))
+		return fmt.Errorf("save failed: %s", msg)
+	}
+
+	count := 0
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		toolsB, detailsB := tx.Bucket(toolsBucket), tx.Bucket(detailsBucket)
+		for i, tool := range tools {
+			if tool.Name == "" {
+				r.logger.Warn("Skipping tool with empty name during save", slog.Int("index", i))
+				continue
+			}
+			toolData, err := json.Marshal(tool)
+			if err != nil {
+				return fmt.Errorf("failed to encode tool %s: %w", tool.Name, err)
+			}
+			detailsData, err := json.Marshal(details[i])
+			if err != nil {
+				return fmt.Errorf("failed to encode invocation details of %s: %w", tool.Name, err)
+			}
+			if err := toolsB.Put([]byte(tool.Name), toolData); err != nil {
+				return err
+			}
+			if err := detailsB.Put([]byte(tool.Name), detailsData); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to save tools and details", slog.Any("error", err))
+		return fmt.Errorf("save failed: %w", err)
+	}
+	r.logger.Info("Saved tools and invocation details", slog.Int("count", count))
+	return nil
+}
+
+// List returns all stored tools, ordered by name.
+func (r *BoltToolRepository) List(ctx context.Context) ([]domain.Tool, error) {
+	list := []domain.Tool{}
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(toolsBucket).ForEach(func(name, data []byte) error {
+			var tool domain.Tool
+			if err := json.Unmarshal(data, &tool); err != nil {
+				return fmt.Errorf("failed to decode tool %s: %w", name, err)
+			}
+			list = append(list, tool)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.logger.Debug("Listed tools from repository", slog.Int("count", len(list)))
+	return list, nil
+}
+
+// FindToolByName retrieves a tool definition by its name.
+func (r *BoltToolRepository) FindToolByName(ctx context.Context, name string) (*domain.Tool, error) {
+	var tool domain.Tool
+	if err := r.get(toolsBucket, name, &tool); err != nil {
+		return nil, err
+	}
+	return &tool, nil
+}
+
+// FindInvocationDetailsByName retrieves invocation details by tool name.
+func (r *BoltToolRepository) FindInvocationDetailsByName(ctx context.Context, name string) (*usecase.InvocationDetails, error) {
+	var details usecase.InvocationDetails
+	if err := r.get(detailsBucket, name, &details); err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+// Delete removes tools and their invocation details by name.
+func (r *BoltToolRepository) Delete(ctx context.Context, names ...string) error {
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		toolsB, detailsB := tx.Bucket(toolsBucket), tx.Bucket(detailsBucket)
+		for _, name := range names {
+			if err := toolsB.Delete([]byte(name)); err != nil {
+				return err
+			}
+			if err := detailsB.Delete([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("delete failed: %w", err)
+	}
+	r.logger.Debug("Deleted tools from repository", slog.Int("count", len(names)))
+	return nil
+}
+
+// get decodes the value stored under name in bucket into v, or returns usecase.ErrToolNotFound.
+func (r *BoltToolRepository) get(bucket []byte, name string, v interface{}) error {
+	return r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get([]byte(name))
+		if data == nil {
+			r.logger.Warn("Tool not found", slog.String("tool_name", name), slog.String("bucket", string(bucket)))
+			return usecase.ErrToolNotFound
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("failed to decode %s entry for tool %s: %w", bucket, name, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,204 @@
+package boltrepo_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/boltrepo"
+	"github.com/i2y/mcpizer/internal/domain"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+func openTestRepo(t *testing.T, path string) *boltrepo.BoltToolRepository {
+	t.Helper()
+	repo, err := boltrepo.NewBoltToolRepository(path, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = repo.Close() })
+	return repo
+}
+
+func newTestRepo(t *testing.T) *boltrepo.BoltToolRepository {
+	return openTestRepo(t, filepath.Join(t.TempDir(), "tools.db"))
+}
+
+func TestBoltToolRepository_SaveAndList(t *testing.T) {
+	ctx := context.Background()
+
+	tool1 := domain.Tool{Name: "tool1", Description: "T1"}
+	details1 := usecase.InvocationDetails{Type: "http", Host: "host1"}
+	tool2 := domain.Tool{Name: "tool2", Description: "T2"}
+	details2 := usecase.InvocationDetails{Type: "http", Host: "host2"}
+
+	tests := []struct {
+		name        string
+		inTools     []domain.Tool
+		inDetails   []usecase.InvocationDetails
+		wantSaveErr bool
+		wantList    []domain.Tool
+	}{
+		{
+			name:      "Save single tool",
+			inTools:   []domain.Tool{tool1},
+			inDetails: []usecase.InvocationDetails{details1},
+			wantList:  []domain.Tool{tool1},
+		},
+		{
+			name:      "Save multiple tools",
+			inTools:   []domain.Tool{tool1, tool2},
+			inDetails: []usecase.InvocationDetails{details1, details2},
+			wantList:  []domain.Tool{tool1, tool2},
+		},
+		{
+			name:      "Save empty list",
+			inTools:   []domain.Tool{},
+			inDetails: []usecase.InvocationDetails{},
+			wantList:  []domain.Tool{},
+		},
+		{
+			name:      "Save with empty tool name (skipped)",
+			inTools:   []domain.Tool{{Name: "", Description: "Empty"}, tool1},
+			inDetails: []usecase.InvocationDetails{{Type: "http"}, details1},
+			wantList:  []domain.Tool{tool1},
+		},
+		{
+			name:        "Error on mismatch length",
+			inTools:     []domain.Tool{tool1},
+			inDetails:   []usecase.InvocationDetails{details1, details2},
+			wantSaveErr: true,
+			wantList:    []domain.Tool{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepo(t)
+
+			err := repo.Save(ctx, tt.inTools, tt.inDetails)
+			if tt.wantSaveErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			listedTools, err := repo.List(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantList, listedTools, "List is ordered by name")
+		})
+	}
+}
+
+func TestBoltToolRepository_FindByName(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	tool1 := domain.Tool{
+		Name:        "tool1",
+		Description: "T1",
+		InputSchema: domain.JSONSchemaProps{
+			Type:       "object",
+			Properties: map[string]domain.JSONSchemaProps{"id": {Type: "string"}},
+			Required:   []string{"id"},
+		},
+	}
+	details1 := usecase.InvocationDetails{Type: "http", Host: "host1", HTTPMethod: "GET", HTTPPath: "/items/{id}", PathParams: []string{"id"}}
+	require.NoError(t, repo.Save(ctx, []domain.Tool{tool1}, []usecase.InvocationDetails{details1}))
+
+	tests := []struct {
+		name        string
+		inName      string
+		wantTool    *domain.Tool
+		wantDetails *usecase.InvocationDetails
+	}{
+		{name: "Find existing tool", inName: "tool1", wantTool: &tool1, wantDetails: &details1},
+		{name: "Find non-existent tool", inName: "tool2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualTool, err := repo.FindToolByName(ctx, tt.inName)
+			actualDetails, detailsErr := repo.FindInvocationDetailsByName(ctx, tt.inName)
+			if tt.wantTool == nil {
+				assert.ErrorIs(t, err, usecase.ErrToolNotFound)
+				assert.Nil(t, actualTool)
+				assert.ErrorIs(t, detailsErr, usecase.ErrToolNotFound)
+				assert.Nil(t, actualDetails)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTool, actualTool)
+			require.NoError(t, detailsErr)
+			assert.Equal(t, tt.wantDetails, actualDetails)
+		})
+	}
+}
+
+func TestBoltToolRepository_SaveOverwrite(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	toolV1 := domain.Tool{Name: "overwrite", Description: "V1"}
+	detailsV1 := usecase.InvocationDetails{Type: "http", Host: "v1"}
+	toolV2 := domain.Tool{Name: "overwrite", Description: "V2"}
+	detailsV2 := usecase.InvocationDetails{Type: "http", Host: "v2"}
+
+	require.NoError(t, repo.Save(ctx, []domain.Tool{toolV1}, []usecase.InvocationDetails{detailsV1}))
+	require.NoError(t, repo.Save(ctx, []domain.Tool{toolV2}, []usecase.InvocationDetails{detailsV2}))
+
+	foundTool, err := repo.FindToolByName(ctx, "overwrite")
+	require.NoError(t, err)
+	assert.Equal(t, &toolV2, foundTool)
+	foundDetails, err := repo.FindInvocationDetailsByName(ctx, "overwrite")
+	require.NoError(t, err)
+	assert.Equal(t, &detailsV2, foundDetails)
+
+	list, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Tool{toolV2}, list)
+}
+
+func TestBoltToolRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	tools := []domain.Tool{{Name: "keep"}, {Name: "drop"}}
+	details := []usecase.InvocationDetails{{Type: "http", Host: "keep"}, {Type: "http", Host: "drop"}}
+	require.NoError(t, repo.Save(ctx, tools, details))
+
+	require.NoError(t, repo.Delete(ctx, "drop", "unknown"))
+
+	_, err := repo.FindToolByName(ctx, "drop")
+	assert.ErrorIs(t, err, usecase.ErrToolNotFound)
+	_, err = repo.FindInvocationDetailsByName(ctx, "drop")
+	assert.ErrorIs(t, err, usecase.ErrToolNotFound)
+
+	list, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Tool{{Name: "keep"}}, list)
+}
+
+func TestBoltToolRepository_PersistsAcrossReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tools.db")
+
+	tool := domain.Tool{Name: "tool1", Description: "T1"}
+	details := usecase.InvocationDetails{Type: "grpc", Host: "grpc://localhost:50051", GRPCService: "greeter.Greeter", GRPCMethod: "SayHello"}
+
+	repo, err := boltrepo.NewBoltToolRepository(path, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, []domain.Tool{tool}, []usecase.InvocationDetails{details}))
+	require.NoError(t, repo.Close())
+
+	reopened := openTestRepo(t, path)
+	list, err := reopened.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Tool{tool}, list)
+	foundDetails, err := reopened.FindInvocationDetailsByName(ctx, "tool1")
+	require.NoError(t, err)
+	assert.Equal(t, &details, foundDetails)
+}
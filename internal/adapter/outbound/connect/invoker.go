@@ -2,37 +2,96 @@ package connect
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	neturl "net/url"
 	"strings"
 	"time"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/bodylog"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/jsondecode"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/urlpath"
+	"github.com/i2y/mcpizer/internal/usecase"
 )
 
 // Invoker implements HTTP-based invocation for Connect-RPC services
 type Invoker struct {
 	logger     *slog.Logger
 	httpClient *http.Client
+	bodyLog    bodylog.Config
+	useNumber  bool
+}
+
+// Option configures optional Invoker behavior.
+type Option func(*Invoker)
+
+// WithBodyLogging enables debug logging of request and response bodies.
+func WithBodyLogging(cfg bodylog.Config) Option {
+	return func(i *Invoker) {
+		i.bodyLog = cfg
+	}
+}
+
+// WithUseNumber decodes JSON response numbers as json.Number instead of float64.
+func WithUseNumber(enabled bool) Option {
+	return func(i *Invoker) {
+		i.useNumber = enabled
+	}
 }
 
 // NewInvoker creates a new Connect-RPC HTTP invoker
-func NewInvoker(logger *slog.Logger) *Invoker {
-	return &Invoker{
+func NewInvoker(logger *slog.Logger, opts ...Option) *Invoker {
+	inv := &Invoker{
 		logger: logger.With("component", "connect_invoker"),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
+	for _, opt := range opts {
+		opt(inv)
+	}
+	return inv
 }
 
+// NewInvokerWithClient creates a Connect-RPC HTTP invoker that sends requests with client.
+func NewInvokerWithClient(client *http.Client, logger *slog.Logger, opts ...Option) *Invoker {
+	inv := NewInvoker(logger, opts...)
+	if client != nil {
+		inv.httpClient = client
+	}
+	return inv
+}
+
+// HTTPOptions customizes the headers sent on a Connect-RPC HTTP call.
+type HTTPOptions struct {
+	// ProtocolVersion overrides the Connect-Protocol-Version header.
+	// Empty uses the default ("1"); "none" omits the header entirely.
+	ProtocolVersion string
+	// AcceptEncoding is sent as the Accept-Encoding header when set (e.g., "gzip").
+	AcceptEncoding string
+	// AcceptLanguage is sent as the Accept-Language header when set (e.g., "ja-JP").
+	AcceptLanguage string
+}
+
+// defaultConnectProtocolVersion is the Connect protocol version sent when no override is configured.
+const defaultConnectProtocolVersion = "1"
+
 // InvokeHTTP invokes a Connect-RPC method using HTTP/JSON
 func (i *Invoker) InvokeHTTP(ctx context.Context, server, fullMethod string, params map[string]interface{}) (interface{}, error) {
+	return i.InvokeHTTPWithOptions(ctx, server, fullMethod, params, HTTPOptions{})
+}
+
+// InvokeHTTPWithOptions invokes a Connect-RPC method using HTTP/JSON with custom header options.
+func (i *Invoker) InvokeHTTPWithOptions(ctx context.Context, server, fullMethod string, params map[string]interface{}, opts HTTPOptions) (interface{}, error) {
 	log := i.logger.With(
 		slog.String("server", server),
 		slog.String("method", fullMethod),
+		usecase.RequestIDLogAttr(ctx),
 	)
 	log.Info("Invoking Connect-RPC method via HTTP")
 
@@ -41,12 +100,15 @@ func (i *Invoker) InvokeHTTP(ctx context.Context, server, fullMethod string, par
 		server = "https://" + server
 	}
 
-	// Remove trailing slash from server
-	server = strings.TrimSuffix(server, "/")
-
 	// Construct the full URL
 	// Connect-RPC uses the pattern: https://server/package.Service/Method
-	url := fmt.Sprintf("%s%s", server, fullMethod)
+	serverURL, err := neturl.Parse(server)
+	if err != nil {
+		log.Error("Failed to parse server URL", slog.Any("error", err))
+		return nil, fmt.Errorf("invalid server URL %s: %w", server, err)
+	}
+	serverURL.Path = urlpath.Join(serverURL.Path, fullMethod)
+	url := serverURL.String()
 
 	// Marshal request body
 	reqBody, err := json.Marshal(params)
@@ -54,6 +116,7 @@ func (i *Invoker) InvokeHTTP(ctx context.Context, server, fullMethod string, par
 		log.Error("Failed to marshal request", slog.Any("error", err))
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
+	i.bodyLog.Log(ctx, log, "Outbound request body", reqBody)
 
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
@@ -66,7 +129,25 @@ func (i *Invoker) InvokeHTTP(ctx context.Context, server, fullMethod string, par
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 	// Connect protocol version header (optional but recommended)
-	req.Header.Set("Connect-Protocol-Version", "1")
+	switch opts.ProtocolVersion {
+	case "":
+		req.Header.Set("Connect-Protocol-Version", defaultConnectProtocolVersion)
+	case "none":
+		log.Debug("Connect-Protocol-Version header disabled")
+	default:
+		req.Header.Set("Connect-Protocol-Version", opts.ProtocolVersion)
+	}
+	// Setting Accept-Encoding explicitly disables net/http's transparent gzip handling,
+	// so gzip responses are decoded below.
+	if opts.AcceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", opts.AcceptEncoding)
+	}
+	if opts.AcceptLanguage != "" {
+		req.Header.Set("Accept-Language", opts.AcceptLanguage)
+	}
+	if requestID, ok := usecase.RequestIDFromContext(ctx); ok && requestID.Header != "" {
+		req.Header.Set(requestID.Header, requestID.ID)
+	}
 
 	// Send request
 	resp, err := i.httpClient.Do(req)
@@ -76,45 +157,93 @@ func (i *Invoker) InvokeHTTP(ctx context.Context, server, fullMethod string, par
 	}
 	defer resp.Body.Close()
 
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
+	// Read response body, decompressing if we negotiated gzip ourselves
+	var bodyReader io.Reader = resp.Body
+	if opts.AcceptEncoding != "" && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			log.Error("Failed to create gzip reader", slog.Any("error", err))
+			return nil, fmt.Errorf("failed to decompress response: %w", err)
+		}
+		defer gz.Close()
+		bodyReader = gz
+	}
+	respBody, err := io.ReadAll(bodyReader)
 	if err != nil {
 		log.Error("Failed to read response", slog.Any("error", err))
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
+	i.bodyLog.Log(ctx, log, "Upstream response body", respBody)
 
 	// Check for Connect-RPC errors
-	// Connect-RPC returns 200 OK even for errors, with error details in the response
+	// Unary Connect errors are sent with a non-2xx status and a {"code", "message"} body.
 	if resp.StatusCode != http.StatusOK {
 		log.Error("HTTP error",
 			slog.Int("status", resp.StatusCode),
 			slog.String("body", string(respBody)),
 		)
-		return nil, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, string(respBody))
+		var envelope map[string]interface{}
+		if json.Unmarshal(respBody, &envelope) == nil {
+			if code, message, ok := parseErrorEnvelope(envelope); ok {
+				return nil, usecase.NewInvocationError(usecase.CategoryForCode(code),
+					fmt.Errorf("HTTP error %d: Connect-RPC error %s: %s", resp.StatusCode, code, message))
+			}
+		}
+		return nil, usecase.NewInvocationError(usecase.CategoryForHTTPStatus(resp.StatusCode),
+			fmt.Errorf("HTTP error %d: %s", resp.StatusCode, string(respBody)))
 	}
 
 	// Parse response
 	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
+	if err := jsondecode.Unmarshal(respBody, &result, i.useNumber); err != nil {
 		log.Error("Failed to unmarshal response", slog.Any("error", err))
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	// Check for Connect-RPC error in response
-	if errObj, ok := result["error"].(map[string]interface{}); ok {
-		code := errObj["code"]
-		message := errObj["message"]
+	// Some servers wrap errors in a 200 response as {"error": {"code", "message"}}.
+	// Only treat it as an error when the wrapper is the sole top-level field, so
+	// legitimate results that happen to contain an "error" field pass through.
+	if code, message, ok := parseWrappedErrorEnvelope(result); ok {
 		log.Error("Connect-RPC error",
-			slog.Any("code", code),
-			slog.Any("message", message),
+			slog.String("code", code),
+			slog.String("message", message),
 		)
-		return nil, fmt.Errorf("Connect-RPC error %v: %v", code, message)
+		return nil, usecase.NewInvocationError(usecase.CategoryForCode(code),
+			fmt.Errorf("Connect-RPC error %s: %s", code, message))
 	}
 
 	log.Info("Successfully invoked Connect-RPC method", slog.Any("result", result))
 	return result, nil
 }
 
+// parseErrorEnvelope reports whether obj has the shape of a Connect error:
+// a string "code" and "message", optionally with "details", and nothing else.
+func parseErrorEnvelope(obj map[string]interface{}) (code, message string, ok bool) {
+	code, codeOK := obj["code"].(string)
+	message, messageOK := obj["message"].(string)
+	if !codeOK || !messageOK || code == "" {
+		return "", "", false
+	}
+	for key := range obj {
+		if key != "code" && key != "message" && key != "details" {
+			return "", "", false
+		}
+	}
+	return code, message, true
+}
+
+// parseWrappedErrorEnvelope reports whether result is exactly {"error": <Connect error>}.
+func parseWrappedErrorEnvelope(result map[string]interface{}) (code, message string, ok bool) {
+	if len(result) != 1 {
+		return "", "", false
+	}
+	errObj, isObj := result["error"].(map[string]interface{})
+	if !isObj {
+		return "", "", false
+	}
+	return parseErrorEnvelope(errObj)
+}
+
 // InvokeStreaming handles streaming Connect-RPC calls (future implementation)
 // Connect-RPC streaming uses different content types:
 // - application/connect+json for JSON streaming
@@ -1,8 +1,10 @@
 package connect
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -110,3 +112,150 @@ func TestInvoker_InvokeHTTP(t *testing.T) {
 		assert.Nil(t, result)
 	})
 }
+
+func TestInvoker_InvokeHTTPWithOptions(t *testing.T) {
+	logger := slog.Default()
+
+	t.Run("protocol version override and gzip", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "2", r.Header.Get("Connect-Protocol-Version"))
+			assert.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			json.NewEncoder(gz).Encode(map[string]interface{}{"sentence": "compressed"})
+			gz.Close()
+		}))
+		defer server.Close()
+
+		invoker := NewInvoker(logger)
+		opts := HTTPOptions{ProtocolVersion: "2", AcceptEncoding: "gzip"}
+		result, err := invoker.InvokeHTTPWithOptions(context.Background(), server.URL, "/test.v1.Service/Method", map[string]interface{}{}, opts)
+
+		require.NoError(t, err)
+		resultMap, ok := result.(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "compressed", resultMap["sentence"])
+	})
+
+	t.Run("protocol version header disabled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, present := r.Header["Connect-Protocol-Version"]
+			assert.False(t, present)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		invoker := NewInvoker(logger)
+		opts := HTTPOptions{ProtocolVersion: "none"}
+		_, err := invoker.InvokeHTTPWithOptions(context.Background(), server.URL, "/test.v1.Service/Method", map[string]interface{}{}, opts)
+		require.NoError(t, err)
+	})
+}
+
+func TestInvoker_InvokeHTTP_ErrorEnvelopeDetection(t *testing.T) {
+	logger := slog.Default()
+
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantErr    string
+		wantResult map[string]interface{}
+	}{
+		{
+			name:   "success response with error field",
+			status: http.StatusOK,
+			body:   `{"jobId": "42", "error": {"code": "timeout", "message": "previous run timed out"}}`,
+			wantResult: map[string]interface{}{
+				"jobId": "42",
+				"error": map[string]interface{}{"code": "timeout", "message": "previous run timed out"},
+			},
+		},
+		{
+			name:   "success response with non-envelope error field",
+			status: http.StatusOK,
+			body:   `{"error": "none"}`,
+			wantResult: map[string]interface{}{
+				"error": "none",
+			},
+		},
+		{
+			name:    "real Connect error with error status",
+			status:  http.StatusNotFound,
+			body:    `{"code": "not_found", "message": "no such user"}`,
+			wantErr: "Connect-RPC error not_found: no such user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			invoker := NewInvoker(logger)
+			result, err := invoker.InvokeHTTP(context.Background(), server.URL, "/test.v1.Service/Method", map[string]interface{}{})
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantResult, result)
+		})
+	}
+}
+
+func TestInvoker_InvokeHTTP_UseNumber(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"userId":9223372036854775807}`))
+	}))
+	defer server.Close()
+
+	invoker := NewInvoker(slog.Default(), WithUseNumber(true))
+	result, err := invoker.InvokeHTTP(context.Background(), server.URL, "/users.v1.UserService/GetUser", nil)
+	require.NoError(t, err)
+
+	encoded, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.Equal(t, `{"userId":9223372036854775807}`, string(encoded))
+}
+
+func TestInvoker_InvokeHTTP_URLJoining(t *testing.T) {
+	tests := []struct {
+		name   string
+		suffix string
+		want   string
+	}{
+		{name: "no path", suffix: "", want: "/test.v1.Service/Method"},
+		{name: "trailing slash", suffix: "/", want: "/test.v1.Service/Method"},
+		{name: "path prefix with trailing slash", suffix: "/rpc/", want: "/rpc/test.v1.Service/Method"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			invoker := NewInvoker(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			_, err := invoker.InvokeHTTP(context.Background(), server.URL+tt.suffix, "/test.v1.Service/Method", nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, gotPath)
+		})
+	}
+}
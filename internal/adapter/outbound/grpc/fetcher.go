@@ -18,8 +18,10 @@ import (
 // SchemaFetcher implements the usecase.SchemaFetcher interface for gRPC reflection.
 type SchemaFetcher struct {
 	// Default dialing options can be customized.
-	dialOpts []grpc.DialOption
-	logger   *slog.Logger
+	dialOpts  []grpc.DialOption
+	logger    *slog.Logger
+	readiness Readiness
+	retry     ReflectionRetry
 }
 
 // NewSchemaFetcher creates a new gRPC SchemaFetcher.
@@ -32,6 +34,7 @@ func NewSchemaFetcher(logger *slog.Logger, opts ...grpc.DialOption) *SchemaFetch
 	return &SchemaFetcher{
 		dialOpts: append(defaultOpts, opts...),
 		logger:   logger.With("component", "grpc_fetcher"),
+		retry:    DefaultReflectionRetry,
 	}
 }
 
@@ -107,7 +110,7 @@ func (f *SchemaFetcher) FetchLegacy(ctx context.Context, src string) (domain.API
 	for _, service := range serviceResp.Service {
 		if service != nil {
 			// Skip reflection service itself
-			if service.Name != "grpc.reflection.v1alpha.ServerReflection" {
+			if !reflectionServiceNames[service.Name] {
 				serviceNames = append(serviceNames, service.Name)
 			}
 		}
@@ -141,6 +144,15 @@ func (f *SchemaFetcher) FetchWithConfig(ctx context.Context, config usecase.Sche
 
 	// gRPC reflection doesn't typically require authentication headers
 	// If authentication is needed, it should be configured via DialOptions
-	// For now, we just delegate to the regular Fetch method
-	return f.Fetch(ctx, config.URL)
+	return f.fetchWithMethods(ctx, config.URL, f.dialOptions(config))
+}
+
+// dialOptions returns the fetcher's dial options plus the source's own, such as its
+// load balancing policy for targets like "dns:///host:port".
+func (f *SchemaFetcher) dialOptions(config usecase.SchemaSourceConfig) []grpc.DialOption {
+	lbOpts := LoadBalancingDialOptions(config.LoadBalancing)
+	if len(lbOpts) == 0 {
+		return f.dialOpts
+	}
+	return append(append([]grpc.DialOption(nil), f.dialOpts...), lbOpts...)
 }
@@ -11,7 +11,6 @@ import (
 	"github.com/i2y/mcpizer/internal/usecase"
 
 	"google.golang.org/grpc"
-	reflectpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
@@ -31,89 +30,70 @@ type MethodInfo struct {
 	ServerStreaming  bool
 	InputDescriptor  *descriptorpb.DescriptorProto
 	OutputDescriptor *descriptorpb.DescriptorProto
+
+	// Leading comments of the method and its messages, when the reflected
+	// descriptors carry source code info.
+	Description       string
+	InputDescription  string
+	OutputDescription string
 }
 
 // FetchWithMethods connects to a gRPC endpoint, uses the reflection service to list services and their methods,
 // and stores the service descriptors as ParsedData.
 func (f *SchemaFetcher) FetchWithMethods(ctx context.Context, src string) (domain.APISchema, error) {
+	return f.fetchWithMethods(ctx, src, f.dialOpts)
+}
+
+// fetchWithMethods implements FetchWithMethods, dialing with dialOpts.
+func (f *SchemaFetcher) fetchWithMethods(ctx context.Context, src string, dialOpts []grpc.DialOption) (domain.APISchema, error) {
 	log := f.logger.With(slog.String("source", src))
 	log.Info("Fetching gRPC schema with methods via reflection")
 
-	// Parse the source - remove grpc:// prefix if present
+	// Parse the source - remove grpc:// prefix if present. http(s):// is stripped too
+	// so sources forced to type grpc via config can use their public URL.
 	target := src
-	if strings.HasPrefix(src, "grpc://") {
-		target = strings.TrimPrefix(src, "grpc://")
+	for _, scheme := range []string{"grpc://", "https://", "http://"} {
+		target = strings.TrimPrefix(target, scheme)
 	}
 
 	// Add a timeout to the context for dialing
 	dialCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	conn, err := grpc.DialContext(dialCtx, target, f.dialOpts...)
+	conn, err := grpc.DialContext(dialCtx, target, dialOpts...)
 	if err != nil {
 		log.Error("Failed to connect to gRPC target", slog.Any("error", err))
 		return domain.APISchema{}, fmt.Errorf("failed to connect to gRPC target %s: %w", target, err)
 	}
 	defer conn.Close()
 
-	// Create reflection client
-	refClient := reflectpb.NewServerReflectionClient(conn)
+	if err := f.waitForReady(ctx, conn, target); err != nil {
+		log.Error("gRPC target did not become ready", slog.Any("error", err))
+		return domain.APISchema{}, err
+	}
 
-	// Create a reflection stream
+	// List services over a reflection stream (v1, or v1alpha for older servers)
 	streamCtx, streamCancel := context.WithTimeout(ctx, 30*time.Second)
 	defer streamCancel()
-	stream, err := refClient.ServerReflectionInfo(streamCtx, grpc.WaitForReady(true))
-	if err != nil {
-		log.Error("Failed to create reflection stream", slog.Any("error", err))
-		return domain.APISchema{}, fmt.Errorf("failed to create reflection stream for %s: %w", target, err)
-	}
-
-	// Send ListServices request
 	log.Debug("Sending ListServices request")
-	if err := stream.Send(&reflectpb.ServerReflectionRequest{
-		MessageRequest: &reflectpb.ServerReflectionRequest_ListServices{
-			ListServices: "*",
-		},
-	}); err != nil {
-		log.Error("Failed to send ListServices request", slog.Any("error", err))
-		return domain.APISchema{}, fmt.Errorf("failed to send ListServices request to %s: %w", target, err)
-	}
-
-	// Receive ListServices response
-	resp, err := stream.Recv()
+	client := &reflectionClient{ctx: streamCtx, conn: conn, retry: f.retry, log: log}
+	serviceResp, err := client.listServices()
 	if err != nil {
-		log.Error("Failed to receive ListServices response", slog.Any("error", err))
-		return domain.APISchema{}, fmt.Errorf("failed to receive ListServices response from %s: %w", target, err)
-	}
-
-	serviceResp := resp.GetListServicesResponse()
-	if serviceResp == nil {
-		log.Error("Invalid ListServices response received", slog.Any("response", resp))
-		return domain.APISchema{}, fmt.Errorf("invalid ListServices response received from %s: %v", target, resp)
+		log.Error("Failed to list services via reflection", slog.Any("error", err))
+		return domain.APISchema{}, fmt.Errorf("failed to list services of %s via reflection: %w", target, err)
 	}
 	log.Debug("Received ListServices response")
 
 	// Collect service descriptors
 	var serviceInfos []ServiceInfo
 	for _, service := range serviceResp.Service {
-		if service != nil && service.Name != "grpc.reflection.v1alpha.ServerReflection" {
+		if service != nil && !reflectionServiceNames[service.Name] {
 			// Get file descriptor for each service
 			log.Debug("Fetching file descriptor for service", slog.String("service", service.Name))
 
-			if err := stream.Send(&reflectpb.ServerReflectionRequest{
-				MessageRequest: &reflectpb.ServerReflectionRequest_FileContainingSymbol{
-					FileContainingSymbol: service.Name,
-				},
-			}); err != nil {
-				log.Error("Failed to send FileContainingSymbol request",
-					slog.String("service", service.Name),
-					slog.Any("error", err))
-				continue
-			}
-
-			resp, err := stream.Recv()
+			resp, err := client.fileContainingSymbol(service.Name)
 			if err != nil {
-				log.Error("Failed to receive FileContainingSymbol response",
+				log.Error("Failed to fetch file descriptor for service",
 					slog.String("service", service.Name),
 					slog.Any("error", err))
 				continue
@@ -157,8 +137,9 @@ func (f *SchemaFetcher) parseServiceInfo(serviceName string, fileDescriptorProto
 	var serviceInfo ServiceInfo
 	serviceInfo.Name = serviceName
 
-	// Keep track of all message types for method resolution
+	// Keep track of all message types (and their comments) for method resolution
 	messageTypes := make(map[string]*descriptorpb.DescriptorProto)
+	messageComments := make(map[string]string)
 
 	for _, fdBytes := range fileDescriptorProtos {
 		var fd descriptorpb.FileDescriptorProto
@@ -167,24 +148,28 @@ func (f *SchemaFetcher) parseServiceInfo(serviceName string, fileDescriptorProto
 			continue
 		}
 
+		comments := leadingComments(&fd)
+
 		// Collect all message types
-		for _, msgType := range fd.MessageType {
+		for i, msgType := range fd.MessageType {
 			fullName := fd.GetPackage() + "." + msgType.GetName()
 			messageTypes[fullName] = msgType
+			messageComments[fullName] = comments[sourcePath(fileMessageTypeField, i)]
 		}
 
 		// Find the service
-		for _, service := range fd.Service {
+		for si, service := range fd.Service {
 			fullServiceName := fd.GetPackage() + "." + service.GetName()
 			if fullServiceName == serviceName {
 				// Extract method information
-				for _, method := range service.Method {
+				for mi, method := range service.Method {
 					methodInfo := MethodInfo{
 						Name:            method.GetName(),
 						InputType:       method.GetInputType(),
 						OutputType:      method.GetOutputType(),
 						ClientStreaming: method.GetClientStreaming(),
 						ServerStreaming: method.GetServerStreaming(),
+						Description:     comments[sourcePath(fileServiceField, si, serviceMethodField, mi)],
 					}
 
 					// Try to find input/output descriptors
@@ -193,9 +178,11 @@ func (f *SchemaFetcher) parseServiceInfo(serviceName string, fileDescriptorProto
 
 					if inputDesc, ok := messageTypes[inputTypeName]; ok {
 						methodInfo.InputDescriptor = inputDesc
+						methodInfo.InputDescription = messageComments[inputTypeName]
 					}
 					if outputDesc, ok := messageTypes[outputTypeName]; ok {
 						methodInfo.OutputDescriptor = outputDesc
+						methodInfo.OutputDescription = messageComments[outputTypeName]
 					}
 
 					serviceInfo.Methods = append(serviceInfo.Methods, methodInfo)
@@ -208,6 +195,36 @@ func (f *SchemaFetcher) parseServiceInfo(serviceName string, fileDescriptorProto
 	return serviceInfo, fmt.Errorf("service %s not found in file descriptors", serviceName)
 }
 
+// Field numbers of FileDescriptorProto and ServiceDescriptorProto in SourceCodeInfo
+// location paths: message_type, service, and method.
+const (
+	fileMessageTypeField = 4
+	fileServiceField     = 6
+	serviceMethodField   = 2
+)
+
+// leadingComments maps the SourceCodeInfo location paths of fd's elements, keyed by
+// sourcePath, to their leading comments. Servers only include source code info in
+// reflected descriptors when their generated code kept it.
+func leadingComments(fd *descriptorpb.FileDescriptorProto) map[string]string {
+	comments := make(map[string]string)
+	for _, location := range fd.GetSourceCodeInfo().GetLocation() {
+		if comment := strings.TrimSpace(location.GetLeadingComments()); comment != "" {
+			comments[fmt.Sprint(location.GetPath())] = comment
+		}
+	}
+	return comments
+}
+
+// sourcePath returns the leadingComments key of a location path.
+func sourcePath(path ...int) string {
+	path32 := make([]int32, len(path))
+	for i, p := range path {
+		path32[i] = int32(p)
+	}
+	return fmt.Sprint(path32)
+}
+
 // FetchWithConfigAndMethods is the enhanced version of FetchWithConfig
 func (f *SchemaFetcher) FetchWithConfigAndMethods(ctx context.Context, config usecase.SchemaSourceConfig) (domain.APISchema, error) {
 	log := f.logger.With(slog.String("source", config.URL))
@@ -217,6 +234,5 @@ func (f *SchemaFetcher) FetchWithConfigAndMethods(ctx context.Context, config us
 	}
 
 	// gRPC reflection doesn't typically require authentication headers
-	// For now, we just delegate to the regular FetchWithMethods method
-	return f.FetchWithMethods(ctx, config.URL)
+	return f.fetchWithMethods(ctx, config.URL, f.dialOptions(config))
 }
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"strings"
 
+	"github.com/i2y/mcpizer/internal/adapter/outbound/protoschema"
 	"github.com/i2y/mcpizer/internal/domain"
 	"github.com/i2y/mcpizer/internal/usecase"
 	"google.golang.org/protobuf/types/descriptorpb"
@@ -16,13 +17,34 @@ import (
 // detailed protobuf descriptor parsing and conversion to JSON Schema.
 type ToolGenerator struct {
 	logger *slog.Logger
+
+	// packageComponents is how many trailing package components precede the
+	// service name in tool names.
+	packageComponents int
+}
+
+// GeneratorOption configures optional ToolGenerator behavior.
+type GeneratorOption func(*ToolGenerator)
+
+// WithPackageComponents includes the last n components of a service's package in
+// its tool names, e.g. "v1_userservice_getuser" for foo.v1.UserService with n=1,
+// so that services of the same name in different packages do not collide. Zero
+// uses the service name alone.
+func WithPackageComponents(n int) GeneratorOption {
+	return func(g *ToolGenerator) {
+		g.packageComponents = n
+	}
 }
 
 // NewToolGenerator creates a new gRPC ToolGenerator.
-func NewToolGenerator(logger *slog.Logger) *ToolGenerator {
-	return &ToolGenerator{
+func NewToolGenerator(logger *slog.Logger, opts ...GeneratorOption) *ToolGenerator {
+	g := &ToolGenerator{
 		logger: logger.With("component", "grpc_generator"),
 	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
 }
 
 // Generate creates MCP Tools and InvocationDetails from gRPC service information.
@@ -71,30 +93,8 @@ func (g *ToolGenerator) generateFromServiceInfos(source string, serviceInfos []S
 				continue
 			}
 
-			// Generate tool name - keep it simple and short
-			// Use only the last part of the service name
 			parts := strings.Split(serviceInfo.Name, ".")
-			servicePart := parts[len(parts)-1]
-			if len(servicePart) > 20 {
-				servicePart = servicePart[:20]
-			}
-
-			methodPart := method.Name
-			if len(methodPart) > 20 {
-				methodPart = methodPart[:20]
-			}
-
-			// Create tool name - use underscore separator for Claude Desktop compatibility
-			toolName := fmt.Sprintf("%s_%s", strings.ToLower(servicePart), strings.ToLower(methodPart))
-
-			// Final safety check - ensure it's under 50 chars (well below 64 limit)
-			if len(toolName) > 50 {
-				h := fnv.New32a()
-				h.Write([]byte(serviceInfo.Name + "." + method.Name))
-				hash := fmt.Sprintf("%x", h.Sum32()&0xFFFF)
-				// Keep first 40 chars and add 5-char hash
-				toolName = toolName[:40] + "_" + hash
-			}
+			toolName := g.toolName(serviceInfo.Name, method.Name)
 
 			log.Debug("Generated tool name",
 				slog.String("service", serviceInfo.Name),
@@ -104,12 +104,21 @@ func (g *ToolGenerator) generateFromServiceInfos(source string, serviceInfos []S
 
 			// Create JSON Schema from protobuf descriptors
 			inputSchema := convertProtoToJSONSchema(method.InputDescriptor, method.InputType)
+			inputSchema.Description = method.InputDescription
 			outputSchema := convertProtoToJSONSchema(method.OutputDescriptor, method.OutputType)
+			outputSchema.Description = method.OutputDescription
 			outputSchemaPtr := &outputSchema
 
+			// Prefer the method's proto comment over a generic description
+			description := method.Description
+			if description == "" {
+				description = fmt.Sprintf("Calls %s.%s gRPC method", serviceInfo.Name, method.Name)
+			}
+
 			tool := domain.Tool{
 				Name:         toolName,
-				Description:  fmt.Sprintf("Calls %s.%s gRPC method", serviceInfo.Name, method.Name),
+				Title:        fmt.Sprintf("%s.%s", parts[len(parts)-1], method.Name),
+				Description:  description,
 				InputSchema:  inputSchema,
 				OutputSchema: outputSchemaPtr,
 			}
@@ -135,6 +144,43 @@ func (g *ToolGenerator) generateFromServiceInfos(source string, serviceInfos []S
 	return tools, detailsList, nil
 }
 
+// toolName returns the tool name of a method: the lowercased service name (and the
+// configured number of package components) and method name, each part cut to 20
+// characters, hashed down when longer than 50 characters in total.
+func (g *ToolGenerator) toolName(service, method string) string {
+	// Keep it simple and short: use the last part of the service name
+	parts := strings.Split(service, ".")
+	servicePart := parts[len(parts)-1]
+	if len(servicePart) > 20 {
+		servicePart = servicePart[:20]
+	}
+
+	methodPart := method
+	if len(methodPart) > 20 {
+		methodPart = methodPart[:20]
+	}
+
+	// Prepend the trailing package components, if configured
+	nameParts := []string{servicePart, methodPart}
+	if n := min(g.packageComponents, len(parts)-1); n > 0 {
+		packageParts := parts[len(parts)-1-n : len(parts)-1]
+		nameParts = append(append([]string(nil), packageParts...), nameParts...)
+	}
+
+	// Use underscore separator for Claude Desktop compatibility
+	toolName := strings.ToLower(strings.Join(nameParts, "_"))
+
+	// Final safety check - ensure it's under 50 chars (well below 64 limit)
+	if len(toolName) > 50 {
+		h := fnv.New32a()
+		h.Write([]byte(service + "." + method))
+		hash := fmt.Sprintf("%x", h.Sum32()&0xFFFF)
+		// Keep first 40 chars and add 5-char hash
+		toolName = toolName[:40] + "_" + hash
+	}
+	return toolName
+}
+
 // generateFromServiceNamesLegacy is the old implementation for backward compatibility
 func (g *ToolGenerator) generateFromServiceNamesLegacy(source string, serviceNames []string) ([]domain.Tool, []usecase.InvocationDetails, error) {
 	var tools []domain.Tool
@@ -197,13 +243,16 @@ func convertProtoToJSONSchema(descriptor *descriptorpb.DescriptorProto, typeName
 	var required []string
 
 	for _, field := range descriptor.Field {
-		fieldName := field.GetName()
+		fieldName := protoschema.JSONName(field)
 		fieldSchema := protoFieldToJSONSchema(field)
 
 		properties[fieldName] = fieldSchema
 
-		// In proto3, all fields are optional by default
-		// Only add to required if it has specific annotations (future enhancement)
+		// In proto3, all fields are optional by default; only fields annotated
+		// with (google.api.field_behavior) = REQUIRED are marked required.
+		if protoschema.HasRequiredBehavior(field.GetOptions()) {
+			required = append(required, fieldName)
+		}
 	}
 
 	return domain.JSONSchemaProps{
@@ -221,12 +270,23 @@ func protoFieldToJSONSchema(field *descriptorpb.FieldDescriptorProto) domain.JSO
 	// Handle repeated fields
 	if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
 		schema.Type = "array"
-		itemSchema := protoTypeToJSONSchema(field.GetType())
+		itemSchema := protoElementToJSONSchema(field)
 		schema.Items = &itemSchema
 		return schema
 	}
 
 	// Handle singular fields
+	return protoElementToJSONSchema(field)
+}
+
+// protoElementToJSONSchema maps the element type of a field, resolving
+// well-known message types by name.
+func protoElementToJSONSchema(field *descriptorpb.FieldDescriptorProto) domain.JSONSchemaProps {
+	if field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+		if schema, ok := protoschema.WellKnownTypeSchema(field.GetTypeName()); ok {
+			return schema
+		}
+	}
 	return protoTypeToJSONSchema(field.GetType())
 }
 
@@ -0,0 +1,73 @@
+package grpc_test
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	grpcadapter "github.com/i2y/mcpizer/internal/adapter/outbound/grpc"
+	"github.com/i2y/mcpizer/internal/domain"
+)
+
+func TestToolGenerator_Generate_PackageComponents(t *testing.T) {
+	schema := domain.APISchema{
+		Source: "grpc://localhost:50051",
+		Type:   domain.SchemaTypeGRPC,
+		ParsedData: []grpcadapter.ServiceInfo{
+			{Name: "foo.v1.UserService", Methods: []grpcadapter.MethodInfo{{Name: "GetUser"}}},
+			{Name: "bar.v1.UserService", Methods: []grpcadapter.MethodInfo{{Name: "GetUser"}}},
+			{Name: "Standalone", Methods: []grpcadapter.MethodInfo{{Name: "Ping"}}},
+			{
+				Name:    "com.example.verylongorganization.platform.v1.AccountManagementService",
+				Methods: []grpcadapter.MethodInfo{{Name: "ListAccountMemberships"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		opts      []grpcadapter.GeneratorOption
+		wantNames []string
+	}{
+		{
+			name:      "service name only",
+			wantNames: []string{"userservice_getuser", "userservice_getuser", "standalone_ping", "accountmanagementser_listaccountmembershi"},
+		},
+		{
+			name:      "one package component",
+			opts:      []grpcadapter.GeneratorOption{grpcadapter.WithPackageComponents(1)},
+			wantNames: []string{"v1_userservice_getuser", "v1_userservice_getuser", "standalone_ping", "v1_accountmanagementser_listaccountmembershi"},
+		},
+		{
+			name:      "two package components",
+			opts:      []grpcadapter.GeneratorOption{grpcadapter.WithPackageComponents(2)},
+			wantNames: []string{"foo_v1_userservice_getuser", "bar_v1_userservice_getuser", "standalone_ping"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := grpcadapter.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)), tt.opts...)
+			tools, details, err := generator.Generate(schema)
+			require.NoError(t, err)
+			require.Len(t, tools, 4)
+			require.Len(t, details, 4)
+
+			for i, want := range tt.wantNames {
+				assert.Equal(t, want, tools[i].Name)
+			}
+			for _, tool := range tools {
+				assert.LessOrEqual(t, len(tool.Name), 50)
+			}
+		})
+	}
+
+	// Long names are shortened with a hash of the full method name, keeping them distinct.
+	generator := grpcadapter.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)), grpcadapter.WithPackageComponents(2))
+	tools, _, err := generator.Generate(schema)
+	require.NoError(t, err)
+	assert.Regexp(t, `^platform_v1_accountmanagementser_listacc_[0-9a-f]+$`, tools[3].Name)
+}
@@ -0,0 +1,151 @@
+package grpc_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	grpcadapter "github.com/i2y/mcpizer/internal/adapter/outbound/grpc"
+)
+
+// connectProxy is a stub HTTP CONNECT proxy that resolves every target host to
+// 127.0.0.1, recording the CONNECT requests it receives.
+type connectProxy struct {
+	auth string // required Proxy-Authorization value; empty accepts every request
+
+	mu      sync.Mutex
+	targets []string
+	auths   []string
+}
+
+func (p *connectProxy) serve(t *testing.T) net.Listener {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go p.handle(conn)
+		}
+	}()
+	return lis
+}
+
+func (p *connectProxy) handle(conn net.Conn) {
+	defer conn.Close()
+	req, err := http.ReadRequest(bufio.NewReader(conn))
+	if err != nil || req.Method != http.MethodConnect {
+		return
+	}
+	p.mu.Lock()
+	p.targets = append(p.targets, req.Host)
+	p.auths = append(p.auths, req.Header.Get("Proxy-Authorization"))
+	p.mu.Unlock()
+
+	if p.auth != "" && req.Header.Get("Proxy-Authorization") != p.auth {
+		_, _ = io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+		return
+	}
+	_, port, _ := net.SplitHostPort(req.Host)
+	upstream, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", port))
+	if err != nil {
+		_, _ = io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+		return
+	}
+	defer upstream.Close()
+	_, _ = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+	go func() { _, _ = io.Copy(upstream, conn) }()
+	_, _ = io.Copy(conn, upstream)
+}
+
+func (p *connectProxy) requests() ([]string, []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.targets...), append([]string(nil), p.auths...)
+}
+
+func TestProxyDialOptions(t *testing.T) {
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	_, port, err := net.SplitHostPort(lis.Addr().String())
+	require.NoError(t, err)
+	// Only the proxy can resolve this host.
+	target := "grpc.corp.invalid:" + port
+
+	basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("alice:secret"))
+	tests := []struct {
+		name     string
+		auth     string
+		userinfo string
+		wantErr  bool
+		wantAuth string
+	}{
+		{name: "tunnel"},
+		{name: "proxy credentials", auth: basicAuth, userinfo: "alice:secret@", wantAuth: basicAuth},
+		{name: "rejected credentials", auth: basicAuth, userinfo: "alice:wrong@", wantErr: true,
+			wantAuth: "Basic " + base64.StdEncoding.EncodeToString([]byte("alice:wrong"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := &connectProxy{auth: tt.auth}
+			proxyLis := proxy.serve(t)
+			opts, err := grpcadapter.ProxyDialOptions("http://" + tt.userinfo + proxyLis.Addr().String())
+			require.NoError(t, err)
+
+			fetcher := grpcadapter.NewSchemaFetcher(slog.New(slog.NewTextHandler(io.Discard, nil)), opts...)
+			fetcher.SetReflectionRetry(grpcadapter.ReflectionRetry{Attempts: 1})
+			// A refused tunnel is retried until the deadline, so keep it short.
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			schema, err := fetcher.Fetch(ctx, "grpc://"+target)
+
+			targets, auths := proxy.requests()
+			require.NotEmpty(t, targets)
+			assert.Equal(t, target, targets[0])
+			assert.Equal(t, tt.wantAuth, auths[0])
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			services, ok := schema.ParsedData.([]grpcadapter.ServiceInfo)
+			require.True(t, ok)
+			require.Len(t, services, 1)
+			assert.Equal(t, "grpc.health.v1.Health", services[0].Name)
+		})
+	}
+}
+
+func TestProxyDialOptions_InvalidURL(t *testing.T) {
+	opts, err := grpcadapter.ProxyDialOptions("")
+	require.NoError(t, err)
+	assert.Empty(t, opts)
+
+	for _, proxyURL := range []string{"socks5://proxy.corp:1080", "proxy.corp:3128", "http://"} {
+		_, err := grpcadapter.ProxyDialOptions(proxyURL)
+		assert.Error(t, err, proxyURL)
+	}
+}
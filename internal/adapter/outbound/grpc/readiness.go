@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// defaultReadinessInterval is used when Readiness.Interval is not positive.
+const defaultReadinessInterval = time.Second
+
+// Readiness configures how long to wait for a gRPC source to become ready
+// before reflecting it. The zero value disables the wait.
+type Readiness struct {
+	// Timeout bounds the total wait; zero or negative disables readiness gating.
+	Timeout time.Duration
+	// Interval is the delay between probes.
+	Interval time.Duration
+}
+
+// NewSchemaFetcherWithReadiness creates a gRPC SchemaFetcher that waits for
+// each source to report SERVING via the gRPC health protocol before reflecting it.
+func NewSchemaFetcherWithReadiness(logger *slog.Logger, readiness Readiness, opts ...grpc.DialOption) *SchemaFetcher {
+	f := NewSchemaFetcher(logger, opts...)
+	f.readiness = readiness
+	return f
+}
+
+// waitForReady probes the gRPC health service until the server reports SERVING
+// or the readiness timeout elapses. Servers that do not implement the health
+// service are considered ready once they answer, since reflection is the next call.
+func (f *SchemaFetcher) waitForReady(ctx context.Context, conn *grpc.ClientConn, target string) error {
+	if f.readiness.Timeout <= 0 {
+		return nil
+	}
+	interval := f.readiness.Interval
+	if interval <= 0 {
+		interval = defaultReadinessInterval
+	}
+	log := f.logger.With(slog.String("target", target))
+
+	waitCtx, cancel := context.WithTimeout(ctx, f.readiness.Timeout)
+	defer cancel()
+
+	client := healthpb.NewHealthClient(conn)
+	for attempt := 1; ; attempt++ {
+		probeErr := probeHealth(waitCtx, client, interval)
+		if probeErr == nil {
+			if attempt > 1 {
+				log.Info("gRPC source became ready", slog.Int("attempts", attempt))
+			}
+			return nil
+		}
+		log.Info("gRPC source not ready yet, retrying",
+			slog.Int("attempt", attempt),
+			slog.Duration("retry_in", interval),
+			slog.Any("error", probeErr))
+
+		select {
+		case <-waitCtx.Done():
+			return fmt.Errorf("gRPC target %s not ready after %s: %w", target, f.readiness.Timeout, probeErr)
+		case <-time.After(interval):
+		}
+		// Reconnect immediately instead of waiting out gRPC's own connection backoff.
+		conn.ResetConnectBackoff()
+	}
+}
+
+// probeHealth performs a single health check, bounded by timeout.
+func probeHealth(ctx context.Context, client healthpb.HealthClient, timeout time.Duration) error {
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := client.Check(probeCtx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return nil
+		}
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("health status %s", resp.GetStatus())
+	}
+	return nil
+}
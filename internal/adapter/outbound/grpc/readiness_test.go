@@ -0,0 +1,77 @@
+package grpc_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	grpcadapter "github.com/i2y/mcpizer/internal/adapter/outbound/grpc"
+)
+
+// reserveAddr returns a local address that nothing is listening on yet.
+func reserveAddr(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+	return addr
+}
+
+func TestSchemaFetcher_Fetch_WaitsForDelayedServer(t *testing.T) {
+	addr := reserveAddr(t)
+
+	started := make(chan struct{})
+	go func() {
+		defer close(started)
+		time.Sleep(300 * time.Millisecond)
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Errorf("listen on %s: %v", addr, err)
+			return
+		}
+		server := grpc.NewServer()
+		healthpb.RegisterHealthServer(server, health.NewServer())
+		reflection.Register(server)
+		t.Cleanup(server.Stop)
+		go server.Serve(lis)
+	}()
+
+	fetcher := grpcadapter.NewSchemaFetcherWithReadiness(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		grpcadapter.Readiness{Timeout: 10 * time.Second, Interval: 100 * time.Millisecond},
+	)
+
+	schema, err := fetcher.Fetch(context.Background(), "grpc://"+addr)
+	<-started
+	require.NoError(t, err)
+	services, ok := schema.ParsedData.([]grpcadapter.ServiceInfo)
+	require.True(t, ok)
+	var names []string
+	for _, service := range services {
+		names = append(names, service.Name)
+	}
+	assert.Contains(t, names, "grpc.health.v1.Health")
+}
+
+func TestSchemaFetcher_Fetch_ReadinessTimeout(t *testing.T) {
+	addr := reserveAddr(t)
+
+	fetcher := grpcadapter.NewSchemaFetcherWithReadiness(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		grpcadapter.Readiness{Timeout: 300 * time.Millisecond, Interval: 50 * time.Millisecond},
+	)
+
+	_, err := fetcher.Fetch(context.Background(), "grpc://"+addr)
+	assert.ErrorContains(t, err, "not ready after 300ms")
+}
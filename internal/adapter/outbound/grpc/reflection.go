@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// reflectionServiceNames are the reflection services themselves, which are not
+// turned into tools.
+var reflectionServiceNames = map[string]bool{
+	"grpc.reflection.v1.ServerReflection":      true,
+	"grpc.reflection.v1alpha.ServerReflection": true,
+}
+
+// reflectionStream is a server reflection stream speaking the v1 message types,
+// whichever reflection service version the server implements.
+type reflectionStream interface {
+	Send(*reflectionv1.ServerReflectionRequest) error
+	Recv() (*reflectionv1.ServerReflectionResponse, error)
+}
+
+// listServices opens a reflection stream and lists the server's services. It uses
+// the v1 reflection service and falls back to v1alpha for servers that only
+// implement the older one. The returned stream can be used for further requests.
+func listServices(ctx context.Context, conn grpc.ClientConnInterface) (reflectionStream, *reflectionv1.ListServiceResponse, error) {
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create reflection stream: %w", err)
+	}
+	services, err := sendListServices(stream)
+	if status.Code(err) != codes.Unimplemented {
+		return stream, services, err
+	}
+
+	alphaStream, err := reflectionv1alpha.NewServerReflectionClient(conn).ServerReflectionInfo(ctx, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create v1alpha reflection stream: %w", err)
+	}
+	v1alpha := &v1alphaStream{stream: alphaStream}
+	services, err = sendListServices(v1alpha)
+	return v1alpha, services, err
+}
+
+// sendListServices sends a ListServices request on stream and returns the response.
+func sendListServices(stream reflectionStream) (*reflectionv1.ListServiceResponse, error) {
+	if err := stream.Send(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{ListServices: "*"},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to send ListServices request: %w", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive ListServices response: %w", err)
+	}
+	services := resp.GetListServicesResponse()
+	if services == nil {
+		return nil, fmt.Errorf("invalid ListServices response: %v", resp)
+	}
+	return services, nil
+}
+
+// v1alphaStream adapts a v1alpha reflection stream to the v1 message types. The
+// two versions share their wire format, so messages are converted by re-encoding.
+type v1alphaStream struct {
+	stream reflectionv1alpha.ServerReflection_ServerReflectionInfoClient
+}
+
+func (s *v1alphaStream) Send(req *reflectionv1.ServerReflectionRequest) error {
+	var alphaReq reflectionv1alpha.ServerReflectionRequest
+	if err := convertMessage(req, &alphaReq); err != nil {
+		return err
+	}
+	return s.stream.Send(&alphaReq)
+}
+
+func (s *v1alphaStream) Recv() (*reflectionv1.ServerReflectionResponse, error) {
+	alphaResp, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	var resp reflectionv1.ServerReflectionResponse
+	if err := convertMessage(alphaResp, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// convertMessage copies src into the wire-compatible message dst.
+func convertMessage(src, dst proto.Message) error {
+	data, err := proto.Marshal(src)
+	if err != nil {
+		return fmt.Errorf("failed to encode reflection message: %w", err)
+	}
+	if err := proto.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("failed to decode reflection message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package grpc_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	grpcadapter "github.com/i2y/mcpizer/internal/adapter/outbound/grpc"
+)
+
+func TestSchemaFetcher_Fetch_ReflectionVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(reflection.GRPCServer)
+	}{
+		{name: "v1 only", register: reflection.RegisterV1},
+		{
+			name: "v1alpha only",
+			register: func(server reflection.GRPCServer) {
+				reflectionv1alpha.RegisterServerReflectionServer(server, reflection.NewServer(reflection.ServerOptions{Services: server}))
+			},
+		},
+		{name: "both", register: reflection.Register},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			server := grpc.NewServer()
+			healthpb.RegisterHealthServer(server, health.NewServer())
+			tt.register(server)
+			go server.Serve(lis)
+			t.Cleanup(server.Stop)
+
+			fetcher := grpcadapter.NewSchemaFetcher(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			schema, err := fetcher.Fetch(context.Background(), "grpc://"+lis.Addr().String())
+			require.NoError(t, err)
+
+			services, ok := schema.ParsedData.([]grpcadapter.ServiceInfo)
+			require.True(t, ok)
+			require.Len(t, services, 1, "reflection services are not listed")
+			assert.Equal(t, "grpc.health.v1.Health", services[0].Name)
+			assert.NotEmpty(t, services[0].Methods)
+		})
+	}
+}
+
+func TestSchemaFetcher_Fetch_ReflectedComments(t *testing.T) {
+	comment := func(text string, path ...int32) *descriptorpb.SourceCodeInfo_Location {
+		return &descriptorpb.SourceCodeInfo_Location{Path: path, Span: []int32{0, 0, 0}, LeadingComments: proto.String(text)}
+	}
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("notes/v1/notes.proto"),
+		Package: proto.String("notes.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("GetNoteRequest")},
+			{Name: proto.String("Note")},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Notes"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("GetNote"), InputType: proto.String(".notes.v1.GetNoteRequest"), OutputType: proto.String(".notes.v1.Note")},
+				{Name: proto.String("Ping"), InputType: proto.String(".notes.v1.GetNoteRequest"), OutputType: proto.String(".notes.v1.Note")},
+			},
+		}},
+		SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+			comment(" Identifies the note to fetch.\n", 4, 0),
+			comment(" A note with a title and a body.\n", 4, 1),
+			comment(" GetNote returns a single note by ID.\n", 6, 0, 2, 0),
+		}},
+	}, nil)
+	require.NoError(t, err)
+	files := new(protoregistry.Files)
+	require.NoError(t, files.RegisterFile(fd))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "notes.v1.Notes",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "GetNote", Handler: func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) { return nil, nil }},
+			{MethodName: "Ping", Handler: func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) { return nil, nil }},
+		},
+	}, nil)
+	reflectionv1.RegisterServerReflectionServer(server,
+		reflection.NewServerV1(reflection.ServerOptions{Services: server, DescriptorResolver: files}))
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	schema, err := grpcadapter.NewSchemaFetcher(logger).Fetch(context.Background(), "grpc://"+lis.Addr().String())
+	require.NoError(t, err)
+	tools, _, err := grpcadapter.NewToolGenerator(logger).Generate(schema)
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+
+	byTitle := make(map[string]int)
+	for i, tool := range tools {
+		byTitle[tool.Title] = i
+	}
+	getNote := tools[byTitle["Notes.GetNote"]]
+	assert.Equal(t, "GetNote returns a single note by ID.", getNote.Description)
+	assert.Equal(t, "Identifies the note to fetch.", getNote.InputSchema.Description)
+	require.NotNil(t, getNote.OutputSchema)
+	assert.Equal(t, "A note with a title and a body.", getNote.OutputSchema.Description)
+
+	// Methods without comments keep the generic description.
+	assert.Equal(t, "Calls notes.v1.Notes.Ping gRPC method", tools[byTitle["Notes.Ping"]].Description)
+}
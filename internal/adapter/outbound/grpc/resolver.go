@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+)
+
+// Load balancing policies a source can select for targets that resolve to several
+// addresses, e.g. "dns:///api.internal:50051".
+const (
+	LoadBalancingPickFirst  = "pick_first"
+	LoadBalancingRoundRobin = "round_robin"
+)
+
+// LoadBalancingServiceConfig returns the default service config JSON selecting policy.
+func LoadBalancingServiceConfig(policy string) string {
+	return fmt.Sprintf(`{"loadBalancingConfig":[{%q:{}}]}`, policy)
+}
+
+// LoadBalancingDialOptions returns the dial options selecting policy, or none when
+// policy is empty, which keeps gRPC's default (pick_first) and any service config
+// published by the resolver.
+func LoadBalancingDialOptions(policy string) []grpc.DialOption {
+	if policy == "" {
+		return nil
+	}
+	return []grpc.DialOption{grpc.WithDefaultServiceConfig(LoadBalancingServiceConfig(policy))}
+}
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+func TestSchemaFetcher_DialOptions_LoadBalancing(t *testing.T) {
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	fetcher := NewSchemaFetcher(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	source := usecase.SchemaSourceConfig{
+		URL:           "grpc://dns:///" + lis.Addr().String(),
+		LoadBalancing: LoadBalancingRoundRobin,
+	}
+
+	opts := fetcher.dialOptions(source)
+	require.Len(t, opts, len(fetcher.dialOpts)+1)
+	assert.Equal(t, `{"loadBalancingConfig":[{"round_robin":{}}]}`, LoadBalancingServiceConfig(LoadBalancingRoundRobin))
+
+	// The dns:/// target resolves through the DNS resolver and is balanced round robin.
+	schema, err := fetcher.FetchWithConfig(context.Background(), source)
+	require.NoError(t, err)
+	assert.Equal(t, source.URL, schema.Source)
+
+	// Without a policy the fetcher's own options are used unchanged.
+	assert.Equal(t, fetcher.dialOpts, fetcher.dialOptions(usecase.SchemaSourceConfig{URL: source.URL}))
+
+	// An unknown policy reaches the dial and is rejected there.
+	_, err = fetcher.FetchWithConfig(context.Background(), usecase.SchemaSourceConfig{URL: source.URL, LoadBalancing: "no_such_policy"})
+	require.Error(t, err)
+}
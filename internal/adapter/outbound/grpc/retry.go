@@ -0,0 +1,124 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+// maxReflectionBackoff caps the delay between reflection attempts.
+const maxReflectionBackoff = 5 * time.Second
+
+// ReflectionRetry configures retries of reflection calls that fail intermittently,
+// e.g. over a flaky network.
+type ReflectionRetry struct {
+	// Attempts is how often a reflection call is tried in total; 1 or less disables retries.
+	Attempts int
+	// Backoff is the delay before the first retry. It doubles with every further
+	// retry, up to 5 seconds.
+	Backoff time.Duration
+}
+
+// DefaultReflectionRetry is the retry policy of fetchers created by NewSchemaFetcher.
+var DefaultReflectionRetry = ReflectionRetry{Attempts: 3, Backoff: 250 * time.Millisecond}
+
+// SetReflectionRetry replaces the fetcher's retry policy for reflection calls.
+func (f *SchemaFetcher) SetReflectionRetry(retry ReflectionRetry) {
+	f.retry = retry
+}
+
+// do calls fn until it succeeds, fails with an error that retrying cannot fix, the
+// attempts are used up, or ctx is done.
+func (r ReflectionRetry) do(ctx context.Context, log *slog.Logger, call string, fn func() error) error {
+	backoff := r.Backoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.Attempts || !retryableReflectionError(err) {
+			return err
+		}
+		log.Warn("Reflection call failed, retrying",
+			slog.String("call", call),
+			slog.Int("attempt", attempt),
+			slog.Duration("retry_in", backoff),
+			slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxReflectionBackoff)
+	}
+}
+
+// retryableReflectionError reports whether a failed reflection call may succeed
+// when repeated. Errors telling that the server lacks reflection, rejects the
+// caller, or does not know the request are final.
+func retryableReflectionError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unimplemented, codes.Unauthenticated, codes.PermissionDenied,
+		codes.InvalidArgument, codes.NotFound, codes.Canceled:
+		return false
+	}
+	return true
+}
+
+// reflectionClient lists services and resolves their files over a reflection
+// stream, reopening the stream to retry failed calls.
+type reflectionClient struct {
+	ctx    context.Context
+	conn   grpc.ClientConnInterface
+	retry  ReflectionRetry
+	log    *slog.Logger
+	stream reflectionStream
+}
+
+// listServices opens a reflection stream and lists the server's services.
+func (c *reflectionClient) listServices() (*reflectionv1.ListServiceResponse, error) {
+	var services *reflectionv1.ListServiceResponse
+	err := c.retry.do(c.ctx, c.log, "ListServices", func() error {
+		stream, resp, err := listServices(c.ctx, c.conn)
+		if err != nil {
+			return err
+		}
+		c.stream, services = stream, resp
+		return nil
+	})
+	return services, err
+}
+
+// fileContainingSymbol returns the response to a FileContainingSymbol request for
+// symbol. A failed call breaks the stream, so it is retried on a new one.
+func (c *reflectionClient) fileContainingSymbol(symbol string) (*reflectionv1.ServerReflectionResponse, error) {
+	var resp *reflectionv1.ServerReflectionResponse
+	err := c.retry.do(c.ctx, c.log, "FileContainingSymbol", func() error {
+		if c.stream == nil {
+			stream, _, err := listServices(c.ctx, c.conn)
+			if err != nil {
+				return err
+			}
+			c.stream = stream
+		}
+		if err := c.stream.Send(&reflectionv1.ServerReflectionRequest{
+			MessageRequest: &reflectionv1.ServerReflectionRequest_FileContainingSymbol{
+				FileContainingSymbol: symbol,
+			},
+		}); err != nil {
+			c.stream = nil
+			return fmt.Errorf("failed to send FileContainingSymbol request: %w", err)
+		}
+		var err error
+		if resp, err = c.stream.Recv(); err != nil {
+			c.stream = nil
+			return fmt.Errorf("failed to receive FileContainingSymbol response: %w", err)
+		}
+		return nil
+	})
+	return resp, err
+}
@@ -0,0 +1,149 @@
+package grpc_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+
+	grpcadapter "github.com/i2y/mcpizer/internal/adapter/outbound/grpc"
+)
+
+// flakyReflectionServer fails the first reflection stream, either right away or
+// at its first request of the given kind, and serves later streams normally.
+type flakyReflectionServer struct {
+	reflectionv1.ServerReflectionServer
+	code   codes.Code
+	failOn func(*reflectionv1.ServerReflectionRequest) bool // nil fails on stream setup
+
+	mu      sync.Mutex
+	streams int
+}
+
+func (s *flakyReflectionServer) ServerReflectionInfo(stream reflectionv1.ServerReflection_ServerReflectionInfoServer) error {
+	s.mu.Lock()
+	s.streams++
+	first := s.streams == 1
+	s.mu.Unlock()
+	if !first {
+		return s.ServerReflectionServer.ServerReflectionInfo(stream)
+	}
+	if s.failOn == nil {
+		return status.Error(s.code, "reflection hiccup")
+	}
+	return s.ServerReflectionServer.ServerReflectionInfo(&failingStream{
+		ServerReflection_ServerReflectionInfoServer: stream,
+		failOn: s.failOn,
+		code:   s.code,
+	})
+}
+
+func (s *flakyReflectionServer) streamCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streams
+}
+
+// failingStream ends the stream with an error at the first matching request.
+type failingStream struct {
+	reflectionv1.ServerReflection_ServerReflectionInfoServer
+	failOn func(*reflectionv1.ServerReflectionRequest) bool
+	code   codes.Code
+}
+
+func (s *failingStream) Recv() (*reflectionv1.ServerReflectionRequest, error) {
+	req, err := s.ServerReflection_ServerReflectionInfoServer.Recv()
+	if err == nil && s.failOn(req) {
+		return nil, status.Error(s.code, "reflection hiccup")
+	}
+	return req, err
+}
+
+func TestSchemaFetcher_Fetch_RetriesReflection(t *testing.T) {
+	isFileRequest := func(req *reflectionv1.ServerReflectionRequest) bool {
+		return req.GetFileContainingSymbol() != ""
+	}
+
+	tests := []struct {
+		name        string
+		code        codes.Code
+		failOn      func(*reflectionv1.ServerReflectionRequest) bool
+		retry       grpcadapter.ReflectionRetry
+		wantErr     bool
+		wantStreams int
+	}{
+		{
+			name:        "stream setup fails once",
+			code:        codes.Unavailable,
+			retry:       grpcadapter.ReflectionRetry{Attempts: 3, Backoff: 10 * time.Millisecond},
+			wantStreams: 2,
+		},
+		{
+			name:        "file request fails once",
+			code:        codes.Internal,
+			failOn:      isFileRequest,
+			retry:       grpcadapter.ReflectionRetry{Attempts: 3, Backoff: 10 * time.Millisecond},
+			wantStreams: 2,
+		},
+		{
+			name:        "retries disabled",
+			code:        codes.Unavailable,
+			retry:       grpcadapter.ReflectionRetry{Attempts: 1},
+			wantErr:     true,
+			wantStreams: 1,
+		},
+		{
+			name:        "permanent error",
+			code:        codes.PermissionDenied,
+			retry:       grpcadapter.ReflectionRetry{Attempts: 3, Backoff: 10 * time.Millisecond},
+			wantErr:     true,
+			wantStreams: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := grpc.NewServer()
+			healthpb.RegisterHealthServer(server, health.NewServer())
+			flaky := &flakyReflectionServer{
+				ServerReflectionServer: reflection.NewServerV1(reflection.ServerOptions{Services: server}),
+				code:                   tt.code,
+				failOn:                 tt.failOn,
+			}
+			reflectionv1.RegisterServerReflectionServer(server, flaky)
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			go server.Serve(lis)
+			t.Cleanup(server.Stop)
+
+			fetcher := grpcadapter.NewSchemaFetcher(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			fetcher.SetReflectionRetry(tt.retry)
+			schema, err := fetcher.Fetch(context.Background(), "grpc://"+lis.Addr().String())
+			assert.Equal(t, tt.wantStreams, flaky.streamCount())
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, tt.code, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			services, ok := schema.ParsedData.([]grpcadapter.ServiceInfo)
+			require.True(t, ok)
+			require.Len(t, services, 1)
+			assert.Equal(t, "grpc.health.v1.Health", services[0].Name)
+			assert.NotEmpty(t, services[0].Methods)
+		})
+	}
+}
@@ -12,59 +12,142 @@ import (
 	"github.com/fullstorydev/grpcurl"
 	"github.com/jhump/protoreflect/grpcreflect"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
-	reflectpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	// Registers the standard google.rpc error detail types so they render as JSON.
+	_ "google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/bodylog"
+	grpcadapter "github.com/i2y/mcpizer/internal/adapter/outbound/grpc"
+	"github.com/i2y/mcpizer/internal/usecase"
 )
 
 // Invoker provides dynamic gRPC method invocation capabilities
 type Invoker struct {
 	logger      *slog.Logger
 	dialOptions []grpc.DialOption
+	bodyLog     bodylog.Config
+	callTimeout time.Duration
+}
+
+// Option configures optional Invoker behavior.
+type Option func(*Invoker)
+
+// WithBodyLogging enables debug logging of request and response messages (as JSON).
+func WithBodyLogging(cfg bodylog.Config) Option {
+	return func(i *Invoker) {
+		i.bodyLog = cfg
+	}
+}
+
+// WithDialOptions adds dial options to the connections made for each call, e.g.
+// grpc.WithDefaultCallOptions with larger message size limits.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(i *Invoker) {
+		i.dialOptions = append(i.dialOptions, opts...)
+	}
+}
+
+// WithCallTimeout bounds each RPC, separately from the time spent connecting, so
+// that a hung method fails with DeadlineExceeded. Zero leaves calls bounded only
+// by their context.
+func WithCallTimeout(d time.Duration) Option {
+	return func(i *Invoker) {
+		i.callTimeout = d
+	}
 }
 
 // NewInvoker creates a new gRPC invoker
-func NewInvoker(logger *slog.Logger) *Invoker {
-	return &Invoker{
+func NewInvoker(logger *slog.Logger, opts ...Option) *Invoker {
+	inv := &Invoker{
 		logger: logger.With("component", "grpc_invoker"),
 		dialOptions: []grpc.DialOption{
 			grpc.WithTransportCredentials(insecure.NewCredentials()),
 		},
 	}
+	for _, opt := range opts {
+		opt(inv)
+	}
+	return inv
 }
 
-// InvokeGRPC dynamically invokes a gRPC method
+// InvokeGRPC dynamically invokes a gRPC method, resolving its descriptors via server reflection.
 func (i *Invoker) InvokeGRPC(ctx context.Context, target, service, method string, params map[string]interface{}) (interface{}, error) {
+	return i.invoke(ctx, target, service, method, CallOptions{}, params)
+}
+
+// CallOptions are the per-source settings of a call.
+type CallOptions struct {
+	// Files holds the method's file and all of its dependencies, e.g. from a .proto
+	// file or descriptor set, so that the server needs no reflection service.
+	// Nil resolves the descriptors via server reflection.
+	Files *descriptorpb.FileDescriptorSet
+	// LoadBalancing selects the load balancing policy (e.g., "round_robin") for
+	// targets that resolve to several addresses, such as "dns:///host:port".
+	LoadBalancing string
+}
+
+// InvokeGRPCWithDescriptors invokes a gRPC method whose descriptors are already known,
+// e.g. from a .proto file or descriptor set, so the server needs no reflection service.
+// files must contain the method's file and all of its dependencies.
+func (i *Invoker) InvokeGRPCWithDescriptors(ctx context.Context, target, service, method string, files *descriptorpb.FileDescriptorSet, params map[string]interface{}) (interface{}, error) {
+	return i.InvokeGRPCWithOptions(ctx, target, service, method, CallOptions{Files: files}, params)
+}
+
+// InvokeGRPCWithOptions invokes a gRPC method with the given per-source settings.
+func (i *Invoker) InvokeGRPCWithOptions(ctx context.Context, target, service, method string, opts CallOptions, params map[string]interface{}) (interface{}, error) {
+	return i.invoke(ctx, target, service, method, opts, params)
+}
+
+// invoke calls the method, with descriptors from opts.Files, or from server reflection when it is nil.
+func (i *Invoker) invoke(ctx context.Context, target, service, method string, opts CallOptions, params map[string]interface{}) (interface{}, error) {
 	log := i.logger.With(
 		slog.String("target", target),
 		slog.String("service", service),
 		slog.String("method", method),
+		usecase.RequestIDLogAttr(ctx),
 	)
 	log.Info("Invoking gRPC method")
 
-	// Remove grpc:// prefix if present
-	if strings.HasPrefix(target, "grpc://") {
-		target = strings.TrimPrefix(target, "grpc://")
+	// Remove grpc:// (or http(s):// for sources forced to type grpc) prefix if present
+	for _, scheme := range []string{"grpc://", "https://", "http://"} {
+		target = strings.TrimPrefix(target, scheme)
 	}
 
 	// Connect to the gRPC server
 	dialCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	conn, err := grpc.DialContext(dialCtx, target, i.dialOptions...)
+	dialOptions := i.dialOptions
+	if lbOpts := grpcadapter.LoadBalancingDialOptions(opts.LoadBalancing); len(lbOpts) > 0 {
+		dialOptions = append(append([]grpc.DialOption(nil), i.dialOptions...), lbOpts...)
+	}
+	conn, err := grpc.DialContext(dialCtx, target, dialOptions...)
 	if err != nil {
 		log.Error("Failed to connect to gRPC server", slog.Any("error", err))
 		return nil, fmt.Errorf("failed to connect to gRPC server: %w", err)
 	}
 	defer conn.Close()
 
-	// Create reflection client to get method descriptors
-	refClient := grpcreflect.NewClient(ctx, reflectpb.NewServerReflectionClient(conn))
-	defer refClient.Reset()
-
-	// Create descriptor source from server reflection
-	descSource := grpcurl.DescriptorSourceFromServer(ctx, refClient)
+	var descSource grpcurl.DescriptorSource
+	if opts.Files != nil {
+		descSource, err = grpcurl.DescriptorSourceFromFileDescriptorSet(opts.Files)
+		if err != nil {
+			log.Error("Failed to load provided descriptors", slog.Any("error", err))
+			return nil, fmt.Errorf("failed to load descriptors for %s: %w", service, err)
+		}
+	} else {
+		// Create reflection client to get method descriptors; it uses the v1 reflection
+		// service and falls back to v1alpha for servers that only implement that one.
+		refClient := grpcreflect.NewClientAuto(ctx, conn)
+		defer refClient.Reset()
+		descSource = grpcurl.DescriptorSourceFromServer(ctx, refClient)
+	}
 
 	// Convert params to JSON for grpcurl
 	reqJSON, err := json.Marshal(params)
@@ -72,6 +155,7 @@ func (i *Invoker) InvokeGRPC(ctx context.Context, target, service, method string
 		log.Error("Failed to marshal request params", slog.Any("error", err))
 		return nil, fmt.Errorf("failed to marshal request params: %w", err)
 	}
+	i.bodyLog.Log(ctx, log, "Outbound request message", reqJSON)
 
 	// Create request parser
 	reqParser, formatter, err := grpcurl.RequestParserAndFormatter(
@@ -97,16 +181,33 @@ func (i *Invoker) InvokeGRPC(ctx context.Context, target, service, method string
 	// Construct the full method name
 	fullMethod := fmt.Sprintf("%s/%s", service, method)
 
+	// Send the request ID as metadata (grpcurl replaces any outgoing metadata of ctx)
+	var headers []string
+	if requestID, ok := usecase.RequestIDFromContext(ctx); ok && requestID.Header != "" {
+		headers = append(headers, strings.ToLower(requestID.Header)+": "+requestID.ID)
+	}
+
 	// Invoke the RPC
+	callCtx := ctx
+	if i.callTimeout > 0 {
+		var cancelCall context.CancelFunc
+		callCtx, cancelCall = context.WithTimeout(ctx, i.callTimeout)
+		defer cancelCall()
+	}
 	err = grpcurl.InvokeRPC(
-		ctx,
+		callCtx,
 		descSource,
 		conn,
 		fullMethod,
-		nil, // headers
+		headers,
 		eventHandler,
 		reqParser.Next,
 	)
+	// grpcurl reports the RPC's own status (e.g. ResourceExhausted for a message over
+	// the size limit) on the event handler rather than as an error.
+	if err == nil && eventHandler.Status != nil && eventHandler.Status.Code() != codes.OK {
+		err = eventHandler.Status.Err()
+	}
 
 	if err != nil {
 		// Check if it's a gRPC status error
@@ -115,7 +216,12 @@ func (i *Invoker) InvokeGRPC(ctx context.Context, target, service, method string
 				slog.String("code", st.Code().String()),
 				slog.String("message", st.Message()),
 			)
-			return nil, fmt.Errorf("gRPC call failed: %s - %s", st.Code(), st.Message())
+			callErr := fmt.Errorf("gRPC call failed: %s - %s", st.Code(), st.Message())
+			if details := statusDetailsJSON(st); details != "" {
+				// Details such as BadRequest field violations tell the caller what to fix.
+				callErr = fmt.Errorf("gRPC call failed: %s - %s; details: %s", st.Code(), st.Message(), details)
+			}
+			return nil, usecase.NewInvocationError(usecase.CategoryForCode(st.Code().String()), callErr)
 		}
 		log.Error("Failed to invoke RPC", slog.Any("error", err))
 		return nil, fmt.Errorf("failed to invoke RPC: %w", err)
@@ -123,6 +229,7 @@ func (i *Invoker) InvokeGRPC(ctx context.Context, target, service, method string
 
 	// Parse the response from the buffer
 	respJSON := respBuf.String()
+	i.bodyLog.Log(ctx, log, "Upstream response message", respBuf.Bytes())
 	if respJSON == "" {
 		log.Warn("Empty response from gRPC call")
 		return nil, nil
@@ -138,6 +245,63 @@ func (i *Invoker) InvokeGRPC(ctx context.Context, target, service, method string
 	return result, nil
 }
 
+// DescriptorSet converts InvocationDetails.FileDescriptor to a FileDescriptorSet.
+// It accepts a set or a single self-contained file, and their generic JSON form,
+// as read back from a persistent tool repository.
+func DescriptorSet(v interface{}) (*descriptorpb.FileDescriptorSet, error) {
+	switch fd := v.(type) {
+	case *descriptorpb.FileDescriptorSet:
+		return fd, nil
+	case *descriptorpb.FileDescriptorProto:
+		return &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{fd}}, nil
+	case nil:
+		return nil, fmt.Errorf("no file descriptor")
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode file descriptor: %w", err)
+	}
+	unmarshal := protojson.UnmarshalOptions{DiscardUnknown: true}
+	var set descriptorpb.FileDescriptorSet
+	if err := unmarshal.Unmarshal(data, &set); err == nil && len(set.GetFile()) > 0 {
+		return &set, nil
+	}
+	var file descriptorpb.FileDescriptorProto
+	if err := unmarshal.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode file descriptor: %w", err)
+	}
+	return &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{&file}}, nil
+}
+
+// statusDetailsJSON renders the details of a gRPC status as a JSON array in the
+// proto3 JSON form, each with its "@type". Details whose type is not known to
+// this binary are reported by type only. It returns "" when there are no details.
+func statusDetailsJSON(st *status.Status) string {
+	anyDetails := st.Proto().GetDetails()
+	if len(anyDetails) == 0 {
+		return ""
+	}
+	rendered := make([]json.RawMessage, 0, len(anyDetails))
+	for _, detail := range anyDetails {
+		raw, err := protojson.Marshal(detail)
+		if err != nil {
+			raw, _ = json.Marshal(map[string]string{"@type": detail.GetTypeUrl()})
+		}
+		// protojson output varies in whitespace; compact it for stable messages.
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, raw); err == nil {
+			raw = compacted.Bytes()
+		}
+		rendered = append(rendered, raw)
+	}
+	out, err := json.Marshal(rendered)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
 // Helper function to build metadata from headers map
 func buildMetadata(headers map[string]string) metadata.MD {
 	md := metadata.New(nil)
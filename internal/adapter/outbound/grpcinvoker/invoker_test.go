@@ -0,0 +1,256 @@
+package grpcinvoker_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	reflectionv1pb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	grpcadapter "github.com/i2y/mcpizer/internal/adapter/outbound/grpc"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/grpcinvoker"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/invoker"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+// startUserService serves test.v1.UserService/GetUser, whose messages have fields
+// whose JSON names differ from their proto names, with reflection enabled. A request
+// without a user ID fails with InvalidArgument and BadRequest details.
+func startUserService(t *testing.T) string {
+	t.Helper()
+	target, _ := serveUserService(t, true)
+	return target
+}
+
+// serveUserService starts the service of startUserService, optionally without
+// reflection, and returns its target and file descriptor.
+func serveUserService(t *testing.T, withReflection bool) (string, *descriptorpb.FileDescriptorProto) {
+	t.Helper()
+
+	// json_name is set as protoc sets it in the descriptors it generates.
+	stringField := func(name, jsonName string, number int32) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(jsonName),
+			Number:   proto.Int32(number),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+	}
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("test/v1/user.proto"),
+		Package: proto.String("test.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("GetUserRequest"), Field: []*descriptorpb.FieldDescriptorProto{stringField("user_id", "userId", 1)}},
+			{Name: proto.String("User"), Field: []*descriptorpb.FieldDescriptorProto{stringField("user_id", "userId", 1), stringField("display_name", "displayName", 2)}},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("UserService"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("GetUser"),
+				InputType:  proto.String(".test.v1.GetUserRequest"),
+				OutputType: proto.String(".test.v1.User"),
+			}},
+		}},
+	}, nil)
+	require.NoError(t, err)
+
+	files := new(protoregistry.Files)
+	for _, file := range []protoreflect.FileDescriptor{
+		fd,
+		reflectionv1pb.File_grpc_reflection_v1_reflection_proto,
+		reflectionv1alphapb.File_grpc_reflection_v1alpha_reflection_proto,
+	} {
+		require.NoError(t, files.RegisterFile(file))
+	}
+
+	reqDesc := fd.Messages().ByName("GetUserRequest")
+	userDesc := fd.Messages().ByName("User")
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.v1.UserService",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "GetUser",
+			Handler: func(_ any, _ context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				req := dynamicpb.NewMessage(reqDesc)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				id := req.Get(reqDesc.Fields().ByName("user_id")).String()
+				if id == "" {
+					st, err := status.New(codes.InvalidArgument, "invalid GetUserRequest").WithDetails(&errdetails.BadRequest{
+						FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "user_id", Description: "must not be empty"}},
+					})
+					if err != nil {
+						return nil, err
+					}
+					return nil, st.Err()
+				}
+				user := dynamicpb.NewMessage(userDesc)
+				user.Set(userDesc.Fields().ByName("user_id"), protoreflect.ValueOfString(id))
+				user.Set(userDesc.Fields().ByName("display_name"), protoreflect.ValueOfString("User "+id))
+				return user, nil
+			},
+		}},
+		Metadata: fd.Path(),
+	}, struct{}{})
+	if withReflection {
+		opts := reflection.ServerOptions{Services: server, DescriptorResolver: files}
+		reflectionv1pb.RegisterServerReflectionServer(server, reflection.NewServerV1(opts))
+		reflectionv1alphapb.RegisterServerReflectionServer(server, reflection.NewServer(opts))
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return "grpc://" + lis.Addr().String(), protodesc.ToFileDescriptorProto(fd)
+}
+
+func TestGRPCReflection_JSONFieldNames(t *testing.T) {
+	target := startUserService(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	schema, err := grpcadapter.NewSchemaFetcher(logger).Fetch(context.Background(), target)
+	require.NoError(t, err)
+	tools, details, err := grpcadapter.NewToolGenerator(logger).Generate(schema)
+	require.NoError(t, err)
+
+	getUser := -1
+	for i, d := range details {
+		if d.GRPCMethod == "GetUser" {
+			getUser = i
+		}
+	}
+	require.NotEqual(t, -1, getUser, "GetUser tool not generated")
+
+	// The tool advertises the JSON name, like tools generated from .proto files.
+	props := tools[getUser].InputSchema.Properties
+	assert.Contains(t, props, "userId")
+	assert.NotContains(t, props, "user_id")
+
+	// The invoker accepts the advertised name and responds with JSON names too.
+	inv := grpcinvoker.NewInvoker(logger)
+	result, err := inv.InvokeGRPC(context.Background(), target, details[getUser].GRPCService, details[getUser].GRPCMethod,
+		map[string]interface{}{"userId": "42"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"userId": "42", "displayName": "User 42"}, result)
+}
+
+func TestGRPCInvoker_StatusDetails(t *testing.T) {
+	target := startUserService(t)
+	inv := grpcinvoker.NewInvoker(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := inv.InvokeGRPC(context.Background(), target, "test.v1.UserService", "GetUser", map[string]interface{}{})
+
+	require.Error(t, err)
+	assert.Equal(t, usecase.ErrorCategoryInvalidInput, usecase.ErrorCategoryOf(err))
+	assert.Equal(t, `gRPC call failed: InvalidArgument - invalid GetUserRequest; details: `+
+		`[{"@type":"type.googleapis.com/google.rpc.BadRequest","fieldViolations":[{"field":"user_id","description":"must not be empty"}]}]`,
+		err.Error())
+}
+
+func TestGRPCInvoker_ReflectionV1Only(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.RegisterV1(server)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	inv := grpcinvoker.NewInvoker(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	result, err := inv.InvokeGRPC(context.Background(), lis.Addr().String(), "grpc.health.v1.Health", "Check", map[string]interface{}{})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"status": "SERVING"}, result)
+}
+
+func TestGRPCInvoker_ProvidedDescriptors(t *testing.T) {
+	target, fileDesc := serveUserService(t, false)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{fileDesc}}
+
+	// Without reflection, the server cannot describe the method itself.
+	inv := grpcinvoker.NewInvoker(logger)
+	_, err := inv.InvokeGRPC(context.Background(), target, "test.v1.UserService", "GetUser", map[string]interface{}{"userId": "42"})
+	require.Error(t, err)
+
+	// A persistent tool repository hands the descriptor back as generic JSON.
+	encoded, err := json.Marshal(set)
+	require.NoError(t, err)
+	var restored interface{}
+	require.NoError(t, json.Unmarshal(encoded, &restored))
+
+	router := invoker.NewRouter(nil, inv, nil, logger)
+	for name, fd := range map[string]interface{}{
+		"descriptor set":  set,
+		"file descriptor": fileDesc,
+		"restored JSON":   restored,
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := router.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:           "grpc",
+				Host:           strings.TrimPrefix(target, "grpc://"),
+				Method:         "/test.v1.UserService/GetUser",
+				FileDescriptor: fd,
+			}, map[string]interface{}{"userId": "42"})
+			require.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{"userId": "42", "displayName": "User 42"}, result)
+		})
+	}
+}
+
+// hangingHealthServer answers health checks only once the caller gives up.
+type hangingHealthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (hangingHealthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	<-ctx.Done()
+	return nil, status.FromContextError(ctx.Err()).Err()
+}
+
+func TestGRPCInvoker_CallTimeout(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, hangingHealthServer{})
+	reflection.Register(server)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	inv := grpcinvoker.NewInvoker(slog.New(slog.NewTextHandler(io.Discard, nil)),
+		grpcinvoker.WithCallTimeout(100*time.Millisecond))
+
+	start := time.Now()
+	_, err = inv.InvokeGRPC(context.Background(), "grpc://"+lis.Addr().String(), "grpc.health.v1.Health", "Check", map[string]interface{}{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DeadlineExceeded")
+	assert.Equal(t, usecase.ErrorCategoryTimeout, usecase.ErrorCategoryOf(err))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,89 @@
+package hostpolicy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubResolver resolves every host to ips.
+type stubResolver []string
+
+func (r stubResolver) LookupIPAddr(context.Context, string) ([]net.IPAddr, error) {
+	addrs := make([]net.IPAddr, len(r))
+	for i, ip := range r {
+		addrs[i] = net.IPAddr{IP: net.ParseIP(ip)}
+	}
+	return addrs, nil
+}
+
+func TestPolicy_DialContext_TriesEachIP(t *testing.T) {
+	tests := []struct {
+		name      string
+		reachable map[string]bool
+		wantAddrs []string
+		wantErr   string
+	}{
+		{
+			name:      "first reachable",
+			reachable: map[string]bool{"10.0.0.1:443": true, "10.0.0.2:443": true},
+			wantAddrs: []string{"10.0.0.1:443"},
+		},
+		{
+			name:      "falls back to the next address",
+			reachable: map[string]bool{"10.0.0.2:443": true},
+			wantAddrs: []string{"10.0.0.1:443", "10.0.0.2:443"},
+		},
+		{
+			name:      "last error when none is reachable",
+			wantAddrs: []string{"10.0.0.1:443", "10.0.0.2:443"},
+			wantErr:   "10.0.0.2:443 unreachable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := New([]string{"10.0.0.0/8"}, nil)
+			require.NoError(t, err)
+			policy.resolver = stubResolver{"10.0.0.1", "10.0.0.2"}
+
+			var dialed []string
+			dial := policy.DialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dialed = append(dialed, addr)
+				if !tt.reachable[addr] {
+					return nil, errors.New(addr + " unreachable")
+				}
+				client, server := net.Pipe()
+				server.Close()
+				return client, nil
+			})
+
+			conn, err := dial(context.Background(), "tcp", "api.internal:443")
+			assert.Equal(t, tt.wantAddrs, dialed)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			conn.Close()
+		})
+	}
+}
+
+func TestPolicy_DialContext_VetsEveryIP(t *testing.T) {
+	policy, err := New([]string{"10.0.0.0/8"}, nil)
+	require.NoError(t, err)
+	policy.resolver = stubResolver{"10.0.0.1", "169.254.169.254"}
+
+	dial := policy.DialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		t.Fatalf("dialed %s of a blocked host", addr)
+		return nil, nil
+	})
+	_, err = dial(context.Background(), "tcp", "api.internal:443")
+	assert.ErrorIs(t, err, ErrBlocked)
+}
@@ -0,0 +1,234 @@
+package hostpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrBlocked is returned when an outbound connection targets a host disallowed by the policy.
+var ErrBlocked = errors.New("outbound host blocked by policy")
+
+// Policy restricts which hosts outbound HTTP connections may reach.
+// Rules are CIDRs ("169.254.0.0/16"), single IPs ("10.0.0.1"), or hostname
+// patterns ("api.example.com", "*.example.com").
+//
+// A target is blocked when its hostname or any resolved IP matches a deny rule.
+// When allow rules are configured, the hostname or every resolved IP must also
+// match an allow rule. An empty policy allows everything.
+type Policy struct {
+	allowNets  []*net.IPNet
+	allowHosts []string
+	denyNets   []*net.IPNet
+	denyHosts  []string
+	resolver   ipResolver
+}
+
+// ipResolver resolves hostnames; *net.Resolver implements it.
+type ipResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// New parses allow and deny rules into a Policy.
+func New(allow, deny []string) (*Policy, error) {
+	p := &Policy{resolver: net.DefaultResolver}
+	var err error
+	if p.allowNets, p.allowHosts, err = parseRules(allow); err != nil {
+		return nil, fmt.Errorf("invalid allow rule: %w", err)
+	}
+	if p.denyNets, p.denyHosts, err = parseRules(deny); err != nil {
+		return nil, fmt.Errorf("invalid deny rule: %w", err)
+	}
+	return p, nil
+}
+
+// Empty reports whether the policy has no rules and therefore allows all hosts.
+func (p *Policy) Empty() bool {
+	return p == nil || (len(p.allowNets) == 0 && len(p.allowHosts) == 0 && len(p.denyNets) == 0 && len(p.denyHosts) == 0)
+}
+
+// DialContext wraps dial so that every connection is checked against the policy.
+// The hostname is resolved here and the connection is made to the vetted IPs, so a
+// DNS answer cannot change between the check and the dial. Like net.Dialer, it
+// tries each IP in turn and returns the last error if none can be reached.
+func (p *Policy) DialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if p.Empty() {
+		return dial
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		ips, err := p.vet(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			var conn net.Conn
+			conn, err = dial(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil || ctx.Err() != nil {
+				return conn, err
+			}
+		}
+		return nil, err
+	}
+}
+
+// WrapClient returns a copy of client whose transport enforces the policy.
+// Requests sent through a proxy (e.g., from HTTPS_PROXY) are checked by their
+// target host as well, since only the proxy is dialed. The client is returned
+// unchanged when the policy is empty.
+func (p *Policy) WrapClient(client *http.Client) *http.Client {
+	if p.Empty() {
+		return client
+	}
+	base, ok := client.Transport.(*http.Transport)
+	if !ok || base == nil {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	transport := base.Clone()
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	transport.DialContext = p.DialContext(dial)
+	if proxy := transport.Proxy; proxy != nil {
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			proxyURL, err := proxy(req)
+			if err != nil || proxyURL == nil {
+				return proxyURL, err
+			}
+			if err := p.CheckHost(req.Context(), req.URL.Hostname()); err != nil {
+				return nil, err
+			}
+			return proxyURL, nil
+		}
+	}
+
+	wrapped := *client
+	wrapped.Transport = transport
+	return &wrapped
+}
+
+// CheckHost reports whether host (a hostname or IP literal) may be contacted.
+func (p *Policy) CheckHost(ctx context.Context, host string) error {
+	if p.Empty() {
+		return nil
+	}
+	_, err := p.vet(ctx, host)
+	return err
+}
+
+// vet checks host against the hostname rules, resolves it, and checks the
+// resulting IPs, returning them for dialing.
+func (p *Policy) vet(ctx context.Context, host string) ([]net.IP, error) {
+	name := strings.ToLower(strings.TrimSuffix(host, "."))
+	if matchHost(p.denyHosts, name) {
+		return nil, fmt.Errorf("%w: %s matches a deny rule", ErrBlocked, name)
+	}
+	ips, err := p.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkIPs(name, ips); err != nil {
+		return nil, err
+	}
+	return ips, nil
+}
+
+func (p *Policy) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	addrs, err := p.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses found for host %s", host)
+	}
+	ips := make([]net.IP, len(addrs))
+	for i, addr := range addrs {
+		ips[i] = addr.IP
+	}
+	return ips, nil
+}
+
+func (p *Policy) checkIPs(host string, ips []net.IP) error {
+	for _, ip := range ips {
+		if matchIP(p.denyNets, ip) {
+			return fmt.Errorf("%w: %s (%s) matches a deny rule", ErrBlocked, host, ip)
+		}
+	}
+
+	if len(p.allowNets) == 0 && len(p.allowHosts) == 0 {
+		return nil
+	}
+	if matchHost(p.allowHosts, host) {
+		return nil
+	}
+	for _, ip := range ips {
+		if !matchIP(p.allowNets, ip) {
+			return fmt.Errorf("%w: %s (%s) is not in the allowlist", ErrBlocked, host, ip)
+		}
+	}
+	return nil
+}
+
+func parseRules(rules []string) ([]*net.IPNet, []string, error) {
+	var nets []*net.IPNet
+	var hosts []string
+	for _, rule := range rules {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		if rule == "" {
+			continue
+		}
+		if strings.Contains(rule, "/") {
+			_, ipNet, err := net.ParseCIDR(rule)
+			if err != nil {
+				return nil, nil, err
+			}
+			nets = append(nets, ipNet)
+			continue
+		}
+		if ip := net.ParseIP(rule); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		hosts = append(hosts, strings.TrimSuffix(rule, "."))
+	}
+	return nets, hosts, nil
+}
+
+func matchHost(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if pattern == host {
+			return true
+		}
+	}
+	return false
+}
+
+func matchIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package hostpolicy_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/hostpolicy"
+)
+
+func TestPolicy_CheckHost(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		allow   []string
+		deny    []string
+		host    string
+		blocked bool
+	}{
+		{name: "empty policy allows link-local", host: "169.254.169.254"},
+		{name: "deny CIDR blocks link-local", deny: []string{"169.254.0.0/16"}, host: "169.254.169.254", blocked: true},
+		{name: "deny single IP", deny: []string{"10.0.0.1"}, host: "10.0.0.1", blocked: true},
+		{name: "deny CIDR leaves other hosts", deny: []string{"169.254.0.0/16"}, host: "10.0.0.1"},
+		{name: "deny hostname wildcard", deny: []string{"*.internal"}, host: "metadata.internal", blocked: true},
+		{name: "allow CIDR permits member", allow: []string{"10.0.0.0/8"}, host: "10.1.2.3"},
+		{name: "allow CIDR blocks non-member", allow: []string{"10.0.0.0/8"}, host: "169.254.169.254", blocked: true},
+		{name: "allow hostname permits exact match", allow: []string{"localhost"}, host: "LOCALHOST"},
+		{name: "deny wins over allow", allow: []string{"169.254.0.0/16"}, deny: []string{"169.254.169.254"}, host: "169.254.169.254", blocked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := hostpolicy.New(tt.allow, tt.deny)
+			require.NoError(t, err)
+
+			err = policy.CheckHost(ctx, tt.host)
+			if tt.blocked {
+				assert.ErrorIs(t, err, hostpolicy.ErrBlocked)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_New_InvalidRule(t *testing.T) {
+	_, err := hostpolicy.New(nil, []string{"169.254.0.0/99"})
+	assert.ErrorContains(t, err, "invalid deny rule")
+}
+
+func TestPolicy_WrapClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("link-local metadata address is blocked", func(t *testing.T) {
+		policy, err := hostpolicy.New(nil, []string{"169.254.0.0/16"})
+		require.NoError(t, err)
+		client := policy.WrapClient(&http.Client{})
+
+		_, err = client.Get("http://169.254.169.254/latest/meta-data/")
+		assert.ErrorIs(t, err, hostpolicy.ErrBlocked)
+	})
+
+	t.Run("allowed host still reachable", func(t *testing.T) {
+		policy, err := hostpolicy.New([]string{"127.0.0.0/8"}, []string{"169.254.0.0/16"})
+		require.NoError(t, err)
+		client := policy.WrapClient(&http.Client{})
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("target behind a proxy is checked", func(t *testing.T) {
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(proxy.Close)
+		proxyURL, err := url.Parse(proxy.URL)
+		require.NoError(t, err)
+
+		policy, err := hostpolicy.New(nil, []string{"169.254.0.0/16"})
+		require.NoError(t, err)
+		client := policy.WrapClient(&http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}})
+
+		_, err = client.Get("http://169.254.169.254/latest/meta-data/")
+		assert.ErrorIs(t, err, hostpolicy.ErrBlocked)
+
+		resp, err := client.Get("http://192.0.2.10/")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("empty policy returns client unchanged", func(t *testing.T) {
+		policy, err := hostpolicy.New(nil, nil)
+		require.NoError(t, err)
+		client := &http.Client{}
+
+		assert.Same(t, client, policy.WrapClient(client))
+	})
+}
@@ -0,0 +1,100 @@
+package httpinvoker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+// dateLayouts are the ways of writing a calendar date that are accepted for
+// "format: date" inputs. Layouts with single-digit month and day fields also accept
+// zero-padded values.
+var dateLayouts = []string{
+	"2006-1-2",
+	"2006/1/2",
+	"2006.1.2",
+	"20060102",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+}
+
+// dateTimeLayouts are the ways of writing a point in time that are accepted for
+// "format: date-time" inputs, besides RFC 3339. Values without a time zone are UTC.
+var dateTimeLayouts = []string{
+	"2006-1-2T15:04:05.999999999Z07:00",
+	"2006-1-2T15:04:05.999999999",
+	"2006-1-2 15:04:05.999999999Z07:00",
+	"2006-1-2 15:04:05.999999999",
+	"2006-1-2T15:04Z07:00",
+	"2006-1-2T15:04",
+	"2006-1-2 15:04",
+}
+
+// normalizeDates returns params with the values of dateParams rewritten in RFC 3339:
+// "2006-01-02" for dates and "2006-01-02T15:04:05Z07:00" for date-times. A date-time
+// given as a date is midnight UTC. params is left untouched. Values that are not
+// strings or cannot be parsed fail with an invalid input error.
+func normalizeDates(params map[string]interface{}, dateParams map[string]string) (map[string]interface{}, error) {
+	var normalized map[string]interface{}
+	for name, format := range dateParams {
+		value, ok := params[name]
+		if !ok || value == nil {
+			continue
+		}
+		s, ok := value.(string)
+		if !ok {
+			return nil, usecase.NewInvocationError(usecase.ErrorCategoryInvalidInput,
+				fmt.Errorf("parameter %s must be a %s string, got %v", name, format, value))
+		}
+		t, err := parseDate(strings.TrimSpace(s), format)
+		if err != nil {
+			return nil, usecase.NewInvocationError(usecase.ErrorCategoryInvalidInput,
+				fmt.Errorf("parameter %s: %q is not a valid %s (expected e.g. %s)", name, s, format, dateExample(format)))
+		}
+		if normalized == nil {
+			normalized = make(map[string]interface{}, len(params))
+			for k, v := range params {
+				normalized[k] = v
+			}
+		}
+		if format == "date" {
+			normalized[name] = t.Format(time.DateOnly)
+		} else {
+			normalized[name] = t.Format(time.RFC3339Nano)
+		}
+	}
+	if normalized == nil {
+		return params, nil
+	}
+	return normalized, nil
+}
+
+// parseDate parses s as a date or date-time, as named by format.
+func parseDate(s, format string) (time.Time, error) {
+	layouts := dateLayouts
+	if format == "date-time" {
+		layouts = append([]string{time.RFC3339Nano}, dateTimeLayouts...)
+		layouts = append(layouts, dateLayouts...)
+	} else {
+		// A date-time keeps its date as written, whatever its time zone.
+		layouts = append(append([]string{}, dateLayouts...), time.RFC3339Nano)
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized %s %q", format, s)
+}
+
+// dateExample returns an example value in the normalized form of format.
+func dateExample(format string) string {
+	if format == "date" {
+		return "2024-01-02"
+	}
+	return "2024-01-02T15:04:05Z"
+}
@@ -4,32 +4,147 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"mime"
+	"net"
 	"net/http"
 	"net/url"
-	"path"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/i2y/mcpizer/internal/adapter/outbound/bodylog"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/jsondecode"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/tlsclient"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/urlpath"
 	"github.com/i2y/mcpizer/internal/usecase"
 )
 
 // Invoker implements the usecase.ToolInvoker interface using standard net/http.
 type Invoker struct {
-	client *http.Client
-	logger *slog.Logger
+	tlsClients  *tlsclient.Cache // The configured client and its copies per source TLS settings
+	logger      *slog.Logger
+	bodyLog     bodylog.Config
+	extraParams ExtraParamsPolicy
+	useNumber   bool
+	dates       bool
+	fallback    bool // retry unreachable hosts against InvocationDetails.Hosts
+
+	callTimeout    time.Duration            // for methods without their own timeout; zero sets none
+	methodTimeouts map[string]time.Duration // keyed by upper-case HTTP method
+}
+
+// ExtraParamsPolicy decides what happens to parameters that are neither path nor query
+// parameters nor the designated BodyParam when a single-parameter body is sent.
+type ExtraParamsPolicy string
+
+const (
+	// ExtraParamsDrop logs and ignores the extra parameters (default).
+	ExtraParamsDrop ExtraParamsPolicy = "drop"
+	// ExtraParamsError fails the invocation.
+	ExtraParamsError ExtraParamsPolicy = "error"
+	// ExtraParamsMerge adds the extra parameters to the body object; fields already
+	// present in the body win. Non-object bodies fail the invocation.
+	ExtraParamsMerge ExtraParamsPolicy = "merge"
+)
+
+// Valid reports whether p is a known policy.
+func (p ExtraParamsPolicy) Valid() bool {
+	switch p {
+	case ExtraParamsDrop, ExtraParamsError, ExtraParamsMerge:
+		return true
+	}
+	return false
+}
+
+// Option configures optional Invoker behavior.
+type Option func(*Invoker)
+
+// WithBodyLogging enables debug logging of request and response bodies.
+func WithBodyLogging(cfg bodylog.Config) Option {
+	return func(i *Invoker) {
+		i.bodyLog = cfg
+	}
+}
+
+// WithExtraParamsPolicy sets how parameters left over next to a BodyParam are handled.
+func WithExtraParamsPolicy(policy ExtraParamsPolicy) Option {
+	return func(i *Invoker) {
+		i.extraParams = policy
+	}
+}
+
+// WithUseNumber decodes JSON response numbers as json.Number instead of float64.
+func WithUseNumber(enabled bool) Option {
+	return func(i *Invoker) {
+		i.useNumber = enabled
+	}
+}
+
+// WithDateNormalization rewrites date and date-time inputs, as told by
+// InvocationDetails.DateParams, in RFC 3339 before sending them, e.g. "2024-1-2" as
+// "2024-01-02". Values that cannot be read as a date fail the call.
+func WithDateNormalization(enabled bool) Option {
+	return func(i *Invoker) {
+		i.dates = enabled
+	}
+}
+
+// WithServerFallback retries a call whose host cannot be connected to against the
+// further servers of its API, in the order of InvocationDetails.Hosts. Off by default,
+// since a spec's servers may point at hosts that must not receive the request.
+func WithServerFallback(enabled bool) Option {
+	return func(i *Invoker) {
+		i.fallback = enabled
+	}
+}
+
+// WithCallTimeout bounds each call whose HTTP method has no timeout of its own.
+// Zero leaves calls bounded only by their context and the HTTP client's timeout.
+func WithCallTimeout(d time.Duration) Option {
+	return func(i *Invoker) {
+		i.callTimeout = d
+	}
+}
+
+// WithMethodTimeouts bounds the calls of each given HTTP method (e.g., GET 5s,
+// POST 30s), overriding WithCallTimeout. Method names are case-insensitive.
+func WithMethodTimeouts(timeouts map[string]time.Duration) Option {
+	return func(i *Invoker) {
+		i.methodTimeouts = make(map[string]time.Duration, len(timeouts))
+		for method, d := range timeouts {
+			i.methodTimeouts[strings.ToUpper(method)] = d
+		}
+	}
+}
+
+// timeoutFor returns the timeout of calls with the given HTTP method, or zero for none.
+func (i *Invoker) timeoutFor(method string) time.Duration {
+	if d, ok := i.methodTimeouts[strings.ToUpper(method)]; ok {
+		return d
+	}
+	return i.callTimeout
 }
 
 // New creates a new HTTP Invoker.
-func New(client *http.Client, logger *slog.Logger) *Invoker {
+func New(client *http.Client, logger *slog.Logger, opts ...Option) *Invoker {
 	if client == nil {
 		client = http.DefaultClient
 	}
-	return &Invoker{
-		client: client,
-		logger: logger.With("component", "http_invoker"),
+	inv := &Invoker{
+		tlsClients:  tlsclient.NewCache(client),
+		logger:      logger.With("component", "http_invoker"),
+		extraParams: ExtraParamsDrop,
+	}
+	for _, opt := range opts {
+		opt(inv)
 	}
+	return inv
 }
 
 // Invoke executes the upstream HTTP call based on InvocationDetails and parameters.
@@ -38,15 +153,32 @@ func (i *Invoker) Invoke(ctx context.Context, details usecase.InvocationDetails,
 		slog.String("method", details.HTTPMethod),
 		slog.String("path", details.HTTPPath),
 		slog.String("host", details.Host),
+		usecase.RequestIDLogAttr(ctx),
 	)
 
+	if timeout := i.timeoutFor(details.HTTPMethod); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+		log = log.With(slog.Duration("timeout", timeout))
+	}
+
+	if i.dates && len(details.DateParams) > 0 {
+		normalized, err := normalizeDates(params, details.DateParams)
+		if err != nil {
+			log.Warn("Invalid date parameter", slog.Any("error", err))
+			return nil, err
+		}
+		params = normalized
+	}
+
 	// --- 1. Construct URL with Path Parameters --- //
 	baseURL, err := url.Parse(details.Host)
 	if err != nil {
 		log.Error("Failed to parse host URL", slog.Any("error", err))
 		return nil, fmt.Errorf("invalid host URL %s: %w", details.Host, err)
 	}
-	fullPath := path.Join(details.BasePath, details.HTTPPath)
+	fullPath := urlpath.Join(details.BasePath, details.HTTPPath)
 
 	processedPath := fullPath
 	remainingParams := make(map[string]interface{})
@@ -79,6 +211,9 @@ func (i *Invoker) Invoke(ctx context.Context, details usecase.InvocationDetails,
 			bodyCandidateParams[k] = v
 		}
 	}
+	for k, v := range details.StaticQueryParams {
+		query.Set(k, v)
+	}
 
 	// --- 3. Construct Request Body (only for methods that allow it) --- //
 	var requestBody io.Reader
@@ -88,13 +223,49 @@ func (i *Invoker) Invoke(ctx context.Context, details usecase.InvocationDetails,
 		bodyParams := make(map[string]interface{})
 		if details.BodyParam == "" {
 			// Complex body: Use all body candidate params
-			bodyParams = bodyCandidateParams
+			bodyParams = withStaticFields(bodyCandidateParams, details.StaticBodyParams)
 		} else if bodyVal, ok := bodyCandidateParams[details.BodyParam]; ok {
 			// Simple body: A single parameter represents the body.
 			// Remove it from bodyCandidates so it's not logged as unused if it's the only one.
 			delete(bodyCandidateParams, details.BodyParam)
 
-			if details.ContentType == "application/json" {
+			if len(bodyCandidateParams) > 0 {
+				extraNames := make([]string, 0, len(bodyCandidateParams))
+				for name := range bodyCandidateParams {
+					extraNames = append(extraNames, name)
+				}
+				sort.Strings(extraNames)
+
+				switch i.extraParams {
+				case ExtraParamsError:
+					log.Warn("Parameters not mapped to path, query, or body", slog.Any("params", extraNames))
+					return nil, usecase.NewInvocationError(usecase.ErrorCategoryInvalidInput,
+						fmt.Errorf("parameters %v are not path, query, or body parameter %s", extraNames, details.BodyParam))
+				case ExtraParamsMerge:
+					bodyObj, ok := bodyVal.(map[string]interface{})
+					if !ok {
+						return nil, usecase.NewInvocationError(usecase.ErrorCategoryInvalidInput,
+							fmt.Errorf("cannot merge parameters %v into non-object body parameter %s", extraNames, details.BodyParam))
+					}
+					merged := make(map[string]interface{}, len(bodyObj)+len(extraNames))
+					for _, name := range extraNames {
+						merged[name] = bodyCandidateParams[name]
+					}
+					for k, v := range bodyObj {
+						merged[k] = v
+					}
+					bodyVal = merged
+					log.Debug("Merged extra parameters into request body", slog.Any("params", extraNames))
+				default:
+					log.Warn("Dropping parameters not mapped to path, query, or body", slog.Any("params", extraNames))
+				}
+			}
+
+			if bodyObj, ok := bodyVal.(map[string]interface{}); ok {
+				bodyVal = withStaticFields(bodyObj, details.StaticBodyParams)
+			}
+
+			if isJSONContentType(details.ContentType) {
 				jsonData, err := json.Marshal(bodyVal)
 				if err != nil {
 					log.Error("Failed to marshal simple request body parameter", slog.String("bodyParam", details.BodyParam), slog.Any("error", err))
@@ -113,14 +284,18 @@ func (i *Invoker) Invoke(ctx context.Context, details usecase.InvocationDetails,
 
 		// Marshal complex body if not handled as simple body
 		if requestBody == nil && len(bodyParams) > 0 {
-			if details.ContentType == "application/json" {
+			if isJSONContentType(details.ContentType) {
 				jsonData, err := json.Marshal(bodyParams)
 				if err != nil {
 					log.Error("Failed to marshal complex request body", slog.Any("error", err))
 					return nil, fmt.Errorf("failed to marshal request body: %w", err)
 				}
 				requestBody = bytes.NewBuffer(jsonData)
-				log.Debug("Prepared request body from multiple params", slog.Any("params", bodyParams), slog.Int("size", len(jsonData)))
+				log.Debug("Prepared request body from multiple params", slog.Int("param_count", len(bodyParams)), slog.Int("size", len(jsonData)))
+			} else if isFormContentType(details.ContentType) {
+				form := encodeForm(bodyParams)
+				requestBody = strings.NewReader(form)
+				log.Debug("Prepared form request body from multiple params", slog.Int("param_count", len(bodyParams)), slog.Int("size", len(form)))
 			} else {
 				log.Warn("Unsupported complex request body content type", slog.String("contentType", details.ContentType))
 				return nil, fmt.Errorf("cannot handle complex body for Content-Type: %s", details.ContentType)
@@ -133,6 +308,15 @@ func (i *Invoker) Invoke(ctx context.Context, details usecase.InvocationDetails,
 			slog.Any("remaining_params", bodyCandidateParams))
 	}
 
+	if i.bodyLog.Enabled && requestBody != nil {
+		bodyBytes, err := io.ReadAll(requestBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		i.bodyLog.Log(ctx, log, "Outbound request body", bodyBytes)
+		requestBody = bytes.NewReader(bodyBytes)
+	}
+
 	// --- 4. Create HTTP Request --- //
 	req, err := http.NewRequestWithContext(ctx, details.HTTPMethod, finalURL, requestBody)
 	if err != nil {
@@ -154,15 +338,39 @@ func (i *Invoker) Invoke(ctx context.Context, details usecase.InvocationDetails,
 		req.Header.Set("Content-Type", details.ContentType)
 	}
 
+	// Ask for the media types the operation declares, so content negotiation returns JSON when possible
+	if details.Accept != "" {
+		req.Header.Set("Accept", details.Accept)
+	}
+	if details.AcceptLanguage != "" {
+		req.Header.Set("Accept-Language", details.AcceptLanguage)
+	}
+
 	// Add headers from HeaderParams
 	for key, value := range details.HeaderParams {
 		req.Header.Set(key, value)
 		log.Debug("Added header", slog.String("key", key), slog.String("value", value))
 	}
 
+	if requestID, ok := usecase.RequestIDFromContext(ctx); ok && requestID.Header != "" {
+		req.Header.Set(requestID.Header, requestID.ID)
+	}
+
 	// --- 5. Execute Request --- //
+	client, err := i.tlsClients.Client(tlsclient.Options{
+		CAFile:             details.TLSCAFile,
+		InsecureSkipVerify: details.TLSInsecureSkipVerify,
+	})
+	if err != nil {
+		log.Error("Failed to configure TLS for the source", slog.Any("error", err))
+		return nil, fmt.Errorf("invalid TLS settings: %w", err)
+	}
 	log.Debug("Executing HTTP request", slog.Any("headers", req.Header))
-	resp, err := i.client.Do(req)
+	var hosts []string
+	if i.fallback {
+		hosts = details.Hosts
+	}
+	resp, err := i.doWithFallbacks(log, client, req, details.Host, hosts)
 	if err != nil {
 		log.Error("HTTP request failed", slog.Any("error", err))
 		// Could map to more specific error types if needed
@@ -179,25 +387,56 @@ func (i *Invoker) Invoke(ctx context.Context, details usecase.InvocationDetails,
 		log.Error("Failed to read response body", slog.Any("error", err))
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	i.bodyLog.Log(ctx, log, "Upstream response body", respBodyBytes)
+
+	if isNoContent(resp.StatusCode) && len(respBodyBytes) == 0 {
+		// Report the status so that clients can tell a bodiless success from an empty text body.
+		log.Debug("Received response without content")
+		return withResponseHeaders(map[string]interface{}{"status": resp.StatusCode}, resp.Header, details.ResponseHeaders), nil
+	}
 
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		// Successful response
 		var resultData interface{}
 		// Attempt to decode JSON if content type indicates it
-		if strings.Contains(resp.Header.Get("Content-Type"), "application/json") && len(respBodyBytes) > 0 {
-			err := json.Unmarshal(respBodyBytes, &resultData)
+		if isEventStream(resp.Header.Get("Content-Type")) {
+			events, err := parseSSE(respBodyBytes)
+			if err != nil {
+				log.Warn("Failed to parse event stream, returning raw body as string", slog.Any("error", err))
+				resultData = string(respBodyBytes)
+			} else {
+				log.Debug("Parsed event stream response", slog.Int("event_count", len(events)))
+				resultData = events
+			}
+		} else if contentType := resp.Header.Get("Content-Type"); isBinaryContent(contentType, respBodyBytes) {
+			mediaType, _, _ := mime.ParseMediaType(contentType)
+			if mediaType == "" {
+				mediaType = "application/octet-stream"
+			}
+			log.Debug("Returning binary response", slog.String("media_type", mediaType), slog.Int("size", len(respBodyBytes)))
+			return usecase.BinaryResult{
+				MIMEType: mediaType,
+				Data:     respBodyBytes,
+				URI:      resourceURI(req.URL),
+				Headers:  responseHeaders(resp.Header, details.ResponseHeaders),
+			}, nil
+		} else if isJSONContentType(resp.Header.Get("Content-Type")) && len(respBodyBytes) > 0 {
+			err := jsondecode.Unmarshal(respBodyBytes, &resultData, i.useNumber)
 			if err != nil {
 				log.Warn("Failed to unmarshal JSON response, returning raw body as string", slog.Any("error", err))
 				resultData = string(respBodyBytes) // Fallback to string
 			} else {
 				log.Debug("Successfully unmarshalled JSON response")
 			}
+		} else if looksLikeJSON(respBodyBytes) && jsondecode.Unmarshal(respBodyBytes, &resultData, i.useNumber) == nil {
+			// Misconfigured servers send JSON without (or with a wrong) Content-Type
+			log.Debug("Parsed JSON response body not labeled as JSON", slog.String("content_type", resp.Header.Get("Content-Type")))
 		} else {
 			// Non-JSON or empty response, return body as string
 			resultData = string(respBodyBytes)
 			log.Debug("Returning non-JSON response body as string")
 		}
-		return resultData, nil
+		return withResponseHeaders(resultData, resp.Header, details.ResponseHeaders), nil
 	} else {
 		// Non-success status code
 		log.Warn("Received non-success status code")
@@ -205,6 +444,199 @@ func (i *Invoker) Invoke(ctx context.Context, details usecase.InvocationDetails,
 		log.Warn("Returning generic HTTP error", slog.String("response_body", respBodyStr))
 
 		// Return error with status code and response body
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, respBodyStr)
+		return nil, usecase.NewInvocationError(usecase.CategoryForHTTPStatus(resp.StatusCode),
+			fmt.Errorf("HTTP %d: %s", resp.StatusCode, respBodyStr))
+	}
+}
+
+// withStaticFields returns body with the static fields the caller did not set added.
+// body is copied rather than modified when there is anything to add.
+func withStaticFields(body, static map[string]interface{}) map[string]interface{} {
+	if len(static) == 0 {
+		return body
+	}
+	merged := make(map[string]interface{}, len(body)+len(static))
+	for k, v := range static {
+		merged[k] = v
+	}
+	for k, v := range body {
+		merged[k] = v
+	}
+	return merged
+}
+
+// resourceURI returns the URI identifying a response fetched from u, without its
+// query and user info: these may carry credentials, such as API keys, and the URI
+// is passed on to the client.
+func resourceURI(u *url.URL) string {
+	stripped := *u
+	stripped.User, stripped.RawQuery, stripped.ForceQuery, stripped.Fragment = nil, "", false, ""
+	return stripped.String()
+}
+
+// doWithFallbacks sends req and, while its host cannot be connected to, retries it
+// against the next of hosts. Only connection failures move on: a host that accepted
+// the connection may have processed the request.
+func (i *Invoker) doWithFallbacks(log *slog.Logger, client *http.Client, req *http.Request, host string, hosts []string) (*http.Response, error) {
+	resp, err := client.Do(req)
+	for _, fallback := range hosts {
+		if err == nil || !isConnectError(err) || req.Context().Err() != nil {
+			break
+		}
+		if fallback == host {
+			continue
+		}
+		fallbackURL, parseErr := url.Parse(fallback)
+		if parseErr != nil {
+			log.Warn("Skipping unparsable fallback host", slog.String("fallback", fallback), slog.Any("error", parseErr))
+			continue
+		}
+		retry := req.Clone(req.Context())
+		retry.URL.Scheme, retry.URL.Host, retry.Host = fallbackURL.Scheme, fallbackURL.Host, ""
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", bodyErr)
+			}
+			retry.Body = body
+		}
+		log.Warn("Host unreachable, trying fallback host", slog.String("fallback", fallback), slog.Any("error", err))
+		resp, err = client.Do(retry)
+	}
+	return resp, err
+}
+
+// isConnectError reports whether err means no connection to the host could be
+// made, e.g. because it refused the connection or its name does not resolve.
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// withResponseHeaders returns result as {"body": result, "headers": {...}} with the
+// named headers the response has, or result itself when no headers are named.
+func withResponseHeaders(result interface{}, header http.Header, names []string) interface{} {
+	if len(names) == 0 {
+		return result
+	}
+	return map[string]interface{}{"body": result, "headers": responseHeaders(header, names)}
+}
+
+// responseHeaders returns the named headers the response has, with multiple values
+// joined by commas, or nil when no headers are named.
+func responseHeaders(header http.Header, names []string) map[string]string {
+	if len(names) == 0 {
+		return nil
+	}
+	headers := make(map[string]string)
+	for _, name := range names {
+		if values := header.Values(name); len(values) > 0 {
+			headers[name] = strings.Join(values, ", ")
+		}
+	}
+	return headers
+}
+
+// isBinaryContent reports whether a response body is binary rather than text:
+// images, audio, video, and well-known binary formats, or, for other non-text
+// media types, a body that is not UTF-8.
+func isBinaryContent(contentType string, body []byte) bool {
+	if len(body) == 0 {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil && !errors.Is(err, mime.ErrInvalidMediaParameter) {
+		return !utf8.Valid(body)
+	}
+	switch major, _, _ := strings.Cut(mediaType, "/"); major {
+	case "image", "audio", "video", "font":
+		return true
+	case "text":
+		return false
+	}
+	switch mediaType {
+	case "application/octet-stream", "application/pdf", "application/zip", "application/gzip", "application/x-protobuf":
+		return true
+	}
+	if isJSONContentType(contentType) || strings.HasSuffix(mediaType, "xml") {
+		return false
+	}
+	return !utf8.Valid(body)
+}
+
+// maxSniffedJSONSize bounds the bodies parsed as JSON without a JSON Content-Type.
+const maxSniffedJSONSize = 10 << 20
+
+// looksLikeJSON reports whether body, not labeled as JSON, is worth parsing as a JSON
+// object or array: it starts with '{' or '[' and is at most maxSniffedJSONSize bytes.
+func looksLikeJSON(body []byte) bool {
+	if len(body) > maxSniffedJSONSize {
+		return false
+	}
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// isNoContent reports whether status is a success that carries no content:
+// 204 No Content, 205 Reset Content, or 304 Not Modified.
+func isNoContent(status int) bool {
+	switch status {
+	case http.StatusNoContent, http.StatusResetContent, http.StatusNotModified:
+		return true
+	}
+	return false
+}
+
+// isJSONContentType reports whether contentType is application/json or a
+// structured "+json" type such as application/vnd.api+json.
+func isJSONContentType(contentType string) bool {
+	// A malformed parameter (e.g., "charset") still yields the media type.
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil && !errors.Is(err, mime.ErrInvalidMediaParameter) {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// isFormContentType reports whether contentType is application/x-www-form-urlencoded.
+func isFormContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil && !errors.Is(err, mime.ErrInvalidMediaParameter) {
+		return false
+	}
+	return mediaType == "application/x-www-form-urlencoded"
+}
+
+// encodeForm encodes params as a URL-encoded form. Array values repeat their key;
+// objects are sent as JSON. Null values are omitted, as a form cannot express them.
+func encodeForm(params map[string]interface{}) string {
+	form := url.Values{}
+	for name, value := range params {
+		values, ok := value.([]interface{})
+		if !ok {
+			values = []interface{}{value}
+		}
+		for _, v := range values {
+			if v == nil {
+				continue
+			}
+			form.Add(name, formValue(v))
+		}
+	}
+	return form.Encode()
+}
+
+// formValue formats a single form field value.
+func formValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}:
+		if data, err := json.Marshal(v); err == nil {
+			return string(data)
+		}
 	}
+	return fmt.Sprintf("%v", v)
 }
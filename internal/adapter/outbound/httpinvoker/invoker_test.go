@@ -1,18 +1,23 @@
 package httpinvoker_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/i2y/mcpizer/internal/adapter/outbound/bodylog"
 	"github.com/i2y/mcpizer/internal/adapter/outbound/httpinvoker"
 	"github.com/i2y/mcpizer/internal/usecase"
 )
@@ -240,3 +245,890 @@ func TestInvoker_Invoke(t *testing.T) {
 		})
 	}
 }
+
+func TestInvoker_Invoke_BodyLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"0123456789abcdefghij"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	details := usecase.InvocationDetails{
+		Type:        "http",
+		Host:        server.URL,
+		HTTPPath:    "/login",
+		HTTPMethod:  http.MethodPost,
+		ContentType: "application/json",
+	}
+	params := map[string]interface{}{"user": "alice", "password": "hunter2"}
+
+	tests := []struct {
+		name        string
+		opts        []httpinvoker.Option
+		wantLogged  []string
+		wantMissing []string
+	}{
+		{
+			name:        "off by default",
+			wantMissing: []string{"Outbound request body", "Upstream response body"},
+		},
+		{
+			name: "enabled with truncation and redaction",
+			opts: []httpinvoker.Option{httpinvoker.WithBodyLogging(bodylog.Config{Enabled: true, MaxLength: 20})},
+			wantLogged: []string{
+				`body="{\"password\":\"[REDACT..."`,
+				`body="{\"message\":\"01234567..."`,
+			},
+			wantMissing: []string{"hunter2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			invoker := httpinvoker.New(server.Client(), logger, tt.opts...)
+
+			_, err := invoker.Invoke(context.Background(), details, params)
+			require.NoError(t, err)
+
+			for _, want := range tt.wantLogged {
+				assert.Contains(t, logs.String(), want)
+			}
+			for _, missing := range tt.wantMissing {
+				assert.NotContains(t, logs.String(), missing)
+			}
+		})
+	}
+}
+
+func TestInvoker_Invoke_ExtraParamsWithBodyParam(t *testing.T) {
+	var gotBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody = nil
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	details := usecase.InvocationDetails{
+		Type:        "http",
+		Host:        server.URL,
+		HTTPPath:    "/pets/{petId}",
+		HTTPMethod:  http.MethodPut,
+		PathParams:  []string{"petId"},
+		BodyParam:   "requestBody",
+		ContentType: "application/json",
+	}
+	newParams := func() map[string]interface{} {
+		return map[string]interface{}{
+			"petId":       "42",
+			"requestBody": map[string]interface{}{"name": "Rex"},
+			"tag":         "dog",
+		}
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name     string
+		policy   httpinvoker.ExtraParamsPolicy
+		wantErr  string
+		wantBody map[string]interface{}
+	}{
+		{
+			name:     "drop by default",
+			wantBody: map[string]interface{}{"name": "Rex"},
+		},
+		{
+			name:    "error",
+			policy:  httpinvoker.ExtraParamsError,
+			wantErr: "parameters [tag] are not path, query, or body parameter requestBody",
+		},
+		{
+			name:     "merge",
+			policy:   httpinvoker.ExtraParamsMerge,
+			wantBody: map[string]interface{}{"name": "Rex", "tag": "dog"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []httpinvoker.Option
+			if tt.policy != "" {
+				opts = append(opts, httpinvoker.WithExtraParamsPolicy(tt.policy))
+			}
+			invoker := httpinvoker.New(server.Client(), logger, opts...)
+
+			_, err := invoker.Invoke(context.Background(), details, newParams())
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBody, gotBody)
+		})
+	}
+
+	t.Run("merge rejects non-object body", func(t *testing.T) {
+		invoker := httpinvoker.New(server.Client(), logger, httpinvoker.WithExtraParamsPolicy(httpinvoker.ExtraParamsMerge))
+		params := newParams()
+		params["requestBody"] = "plain"
+
+		_, err := invoker.Invoke(context.Background(), details, params)
+		assert.ErrorContains(t, err, "cannot merge parameters [tag] into non-object body parameter requestBody")
+	})
+}
+
+func TestInvoker_Invoke_ErrorCategory(t *testing.T) {
+	tests := []struct {
+		status int
+		want   usecase.ErrorCategory
+	}{
+		{status: http.StatusUnauthorized, want: usecase.ErrorCategoryUnauthorized},
+		{status: http.StatusNotFound, want: usecase.ErrorCategoryNotFound},
+		{status: http.StatusBadRequest, want: usecase.ErrorCategoryInvalidInput},
+		{status: http.StatusBadGateway, want: usecase.ErrorCategoryUpstream},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			invoker, server := newTestInvoker(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+
+			_, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:       "http",
+				Host:       server.URL,
+				HTTPMethod: http.MethodGet,
+				HTTPPath:   "/pets/1",
+			}, nil)
+			require.Error(t, err)
+			assert.Equal(t, tt.want, usecase.ErrorCategoryOf(err))
+		})
+	}
+}
+
+func TestInvoker_Invoke_UseNumber(t *testing.T) {
+	const body = `{"id":1234567890123456789,"price":9.99}`
+
+	tests := []struct {
+		name      string
+		useNumber bool
+		wantJSON  string
+	}{
+		{name: "float64 by default loses precision", wantJSON: `{"id":1234567890123456800,"price":9.99}`},
+		{name: "use number preserves large integers", useNumber: true, wantJSON: body},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(body))
+			}))
+			t.Cleanup(server.Close)
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			invoker := httpinvoker.New(server.Client(), logger, httpinvoker.WithUseNumber(tt.useNumber))
+
+			result, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:       "http",
+				Host:       server.URL,
+				HTTPMethod: http.MethodGet,
+				HTTPPath:   "/orders/1",
+			}, nil)
+			require.NoError(t, err)
+
+			encoded, err := json.Marshal(result)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantJSON, string(encoded))
+		})
+	}
+}
+
+func TestInvoker_Invoke_EventStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		flusher := w.(http.Flusher)
+		_, _ = io.WriteString(w, ": keep-alive\n\nevent: progress\ndata: {\"percent\":50}\n\n")
+		flusher.Flush()
+		_, _ = io.WriteString(w, "id: 2\ndata: line one\r\ndata: line two\r\n\r\n")
+		flusher.Flush()
+	}))
+	t.Cleanup(server.Close)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	invoker := httpinvoker.New(server.Client(), logger)
+
+	result, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+		Type:       "http",
+		Host:       server.URL,
+		HTTPMethod: http.MethodGet,
+		HTTPPath:   "/jobs/1/events",
+	}, nil)
+	require.NoError(t, err)
+
+	encoded, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"event": "progress", "data": "{\"percent\":50}"},
+		{"event": "message", "data": "line one\nline two"}
+	]`, string(encoded))
+}
+
+func TestInvoker_Invoke_Accept(t *testing.T) {
+	var gotAccept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAccept = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	invoker := httpinvoker.New(server.Client(), logger)
+
+	_, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+		Type:       "http",
+		Host:       server.URL,
+		HTTPMethod: http.MethodGet,
+		HTTPPath:   "/report",
+		Accept:     "application/json, text/csv;q=0.9",
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json, text/csv;q=0.9", gotAccept)
+}
+
+func TestInvoker_Invoke_StaticQueryParams(t *testing.T) {
+	var gotQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	invoker := httpinvoker.New(server.Client(), logger)
+
+	_, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+		Type:              "http",
+		Host:              server.URL,
+		HTTPMethod:        http.MethodGet,
+		HTTPPath:          "/reports",
+		QueryParams:       []string{"sort"},
+		StaticQueryParams: map[string]string{"format": "json"},
+	}, map[string]interface{}{"sort": "asc"})
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"format": {"json"}, "sort": {"asc"}}, gotQuery)
+}
+
+func TestInvoker_Invoke_PathJoining(t *testing.T) {
+	tests := []struct {
+		name     string
+		basePath string
+		path     string
+		want     string
+	}{
+		{name: "trailing slash kept", basePath: "/api/v1", path: "/users/", want: "/api/v1/users/"},
+		{name: "base path with trailing slash", basePath: "/api/v1/", path: "/users", want: "/api/v1/users"},
+		{name: "empty base path", basePath: "", path: "/users/", want: "/users/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			t.Cleanup(server.Close)
+
+			invoker := httpinvoker.New(server.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+			_, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:       "http",
+				Host:       server.URL,
+				BasePath:   tt.basePath,
+				HTTPMethod: http.MethodGet,
+				HTTPPath:   tt.path,
+			}, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, gotPath)
+		})
+	}
+}
+
+func TestInvoker_Invoke_VendorJSONContentType(t *testing.T) {
+	var gotContentType string
+	var gotBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		_, _ = w.Write([]byte(`{"data":{"id":"1"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	invoker := httpinvoker.New(server.Client(), logger)
+
+	result, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+		Type:        "http",
+		Host:        server.URL,
+		HTTPMethod:  http.MethodPost,
+		HTTPPath:    "/articles",
+		ContentType: "application/vnd.api+json",
+	}, map[string]interface{}{"title": "Hello"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/vnd.api+json", gotContentType)
+	assert.Equal(t, map[string]interface{}{"title": "Hello"}, gotBody)
+	assert.Equal(t, map[string]interface{}{"data": map[string]interface{}{"id": "1"}}, result)
+}
+
+func TestInvoker_Invoke_EmptyResponses(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		want        interface{}
+	}{
+		{name: "204 no content", status: http.StatusNoContent, want: map[string]interface{}{"status": http.StatusNoContent}},
+		{name: "304 not modified", status: http.StatusNotModified, want: map[string]interface{}{"status": http.StatusNotModified}},
+		{name: "200 with empty text body", status: http.StatusOK, contentType: "text/plain", want: ""},
+		{name: "200 with empty JSON body", status: http.StatusOK, contentType: "application/json", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(server.Close)
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			invoker := httpinvoker.New(server.Client(), logger)
+
+			result, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:       "http",
+				Host:       server.URL,
+				HTTPMethod: http.MethodDelete,
+				HTTPPath:   "/pets/1",
+			}, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestInvoker_Invoke_AcceptLanguage(t *testing.T) {
+	var gotLanguage string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotLanguage = r.Header.Get("Accept-Language")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	invoker := httpinvoker.New(server.Client(), logger)
+
+	_, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+		Type:           "http",
+		Host:           server.URL,
+		HTTPMethod:     http.MethodGet,
+		HTTPPath:       "/greetings",
+		AcceptLanguage: "ja-JP",
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ja-JP", gotLanguage)
+}
+
+func TestInvoker_Invoke_FallbackHosts(t *testing.T) {
+	// A port nothing listens on anymore refuses connections.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachable := "http://" + lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	var requests int
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		if r.URL.Path == "/api/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name         string
+		disabled     bool
+		hosts        []string
+		path         string
+		want         interface{}
+		wantErr      bool
+		wantRequests int
+	}{
+		{name: "first host unreachable", hosts: []string{unreachable, server.URL}, path: "/items", want: map[string]interface{}{"ok": true}, wantRequests: 1},
+		{name: "no fallback", hosts: nil, path: "/items", wantErr: true},
+		{name: "error responses are not retried", hosts: []string{server.URL, unreachable}, path: "/fail", wantErr: true, wantRequests: 1},
+		{name: "fallback disabled", disabled: true, hosts: []string{unreachable, server.URL}, path: "/items", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, gotBody = 0, ""
+			invoker := httpinvoker.New(server.Client(), logger, httpinvoker.WithServerFallback(!tt.disabled))
+			host := unreachable
+			if len(tt.hosts) > 0 {
+				host = tt.hosts[0]
+			}
+			result, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:        "http",
+				Host:        host,
+				Hosts:       tt.hosts,
+				BasePath:    "/api",
+				HTTPMethod:  http.MethodPost,
+				HTTPPath:    tt.path,
+				ContentType: "application/json",
+			}, map[string]interface{}{"name": "widget"})
+			assert.Equal(t, tt.wantRequests, requests)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+			assert.JSONEq(t, `{"name":"widget"}`, gotBody, "the request body is sent again to the fallback host")
+		})
+	}
+}
+
+func TestInvoker_Invoke_BinaryResponses(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		want        interface{}
+	}{
+		{name: "image", contentType: "image/png", body: png, want: usecase.BinaryResult{MIMEType: "image/png", Data: png}},
+		{name: "octet stream", contentType: "application/octet-stream", body: []byte("raw"), want: usecase.BinaryResult{MIMEType: "application/octet-stream", Data: []byte("raw")}},
+		{name: "unknown type with binary body", contentType: "application/x-custom", body: []byte{0xff, 0xfe}, want: usecase.BinaryResult{MIMEType: "application/x-custom", Data: []byte{0xff, 0xfe}}},
+		{name: "plain text", contentType: "text/plain; charset=utf-8", body: []byte("hello"), want: "hello"},
+		{name: "unknown type with text body", contentType: "application/x-custom", body: []byte("hello"), want: "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = w.Write(tt.body)
+			}))
+			t.Cleanup(server.Close)
+
+			invoker := httpinvoker.New(server.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+			result, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:              "http",
+				Host:              server.URL,
+				HTTPMethod:        http.MethodGet,
+				HTTPPath:          "/file",
+				StaticQueryParams: map[string]string{"api_key": "secret"},
+			}, nil)
+			require.NoError(t, err)
+			if want, ok := tt.want.(usecase.BinaryResult); ok {
+				want.URI = server.URL + "/file" // without the API key
+				tt.want = want
+			}
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestInvoker_Invoke_ResponseHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "41")
+		if r.URL.Path == "/orders/7/label" {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n"))
+			return
+		}
+		if r.URL.Path == "/orders" {
+			w.Header().Set("Location", "/orders/7")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":7}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	invoker := httpinvoker.New(server.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		headers []string
+		want    interface{}
+	}{
+		{
+			name:    "configured headers next to the body",
+			method:  http.MethodPost,
+			path:    "/orders",
+			headers: []string{"Location", "X-RateLimit-Remaining", "Retry-After"},
+			want: map[string]interface{}{
+				"body":    map[string]interface{}{"id": float64(7)},
+				"headers": map[string]string{"Location": "/orders/7", "X-RateLimit-Remaining": "41"},
+			},
+		},
+		{
+			name:   "not configured",
+			method: http.MethodPost,
+			path:   "/orders",
+			want:   map[string]interface{}{"id": float64(7)},
+		},
+		{
+			name:    "none of the headers present",
+			method:  http.MethodPost,
+			path:    "/orders",
+			headers: []string{"Retry-After"},
+			want: map[string]interface{}{
+				"body":    map[string]interface{}{"id": float64(7)},
+				"headers": map[string]string{},
+			},
+		},
+		{
+			name:    "bodiless response",
+			method:  http.MethodDelete,
+			path:    "/orders/7",
+			headers: []string{"X-RateLimit-Remaining"},
+			want: map[string]interface{}{
+				"body":    map[string]interface{}{"status": http.StatusNoContent},
+				"headers": map[string]string{"X-RateLimit-Remaining": "41"},
+			},
+		},
+		{
+			name:    "binary response",
+			method:  http.MethodGet,
+			path:    "/orders/7/label",
+			headers: []string{"X-RateLimit-Remaining"},
+			want: usecase.BinaryResult{
+				MIMEType: "image/png",
+				Data:     []byte("\x89PNG\r\n\x1a\n"),
+				URI:      server.URL + "/orders/7/label",
+				Headers:  map[string]string{"X-RateLimit-Remaining": "41"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:            "http",
+				Host:            server.URL,
+				HTTPMethod:      tt.method,
+				HTTPPath:        tt.path,
+				ContentType:     "application/json",
+				ResponseHeaders: tt.headers,
+			}, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestInvoker_Invoke_StaticBodyParams(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	invoker := httpinvoker.New(server.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name      string
+		bodyParam string
+		params    map[string]interface{}
+		want      map[string]interface{}
+	}{
+		{
+			name:   "added to a body of parameters",
+			params: map[string]interface{}{"name": "widget"},
+			want:   map[string]interface{}{"name": "widget", "source": "mcp", "version": float64(2)},
+		},
+		{
+			name:   "caller's value wins",
+			params: map[string]interface{}{"name": "widget", "version": float64(3)},
+			want:   map[string]interface{}{"name": "widget", "source": "mcp", "version": float64(3)},
+		},
+		{
+			name:      "added to an object body parameter",
+			bodyParam: "item",
+			params:    map[string]interface{}{"item": map[string]interface{}{"name": "widget"}},
+			want:      map[string]interface{}{"name": "widget", "source": "mcp", "version": float64(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:             "http",
+				Host:             server.URL,
+				HTTPMethod:       http.MethodPost,
+				HTTPPath:         "/items",
+				ContentType:      "application/json",
+				BodyParam:        tt.bodyParam,
+				StaticBodyParams: map[string]interface{}{"source": "mcp", "version": 2},
+			}, tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInvoker_Invoke_FormBody(t *testing.T) {
+	var contentType string
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	invoker := httpinvoker.New(server.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	_, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+		Type:        "http",
+		Host:        server.URL,
+		HTTPMethod:  http.MethodPost,
+		HTTPPath:    "/token",
+		ContentType: "application/x-www-form-urlencoded",
+	}, map[string]interface{}{
+		"grant_type": "client_credentials",
+		"scope":      []interface{}{"read", "write"},
+		"ttl":        float64(3600),
+		"audience":   nil,
+		"resource":   []interface{}{"orders", nil},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/x-www-form-urlencoded", contentType)
+	assert.Equal(t, url.Values{
+		"grant_type": {"client_credentials"},
+		"scope":      {"read", "write"},
+		"ttl":        {"3600"},
+		"resource":   {"orders"},
+	}, form, "null values are omitted")
+}
+
+func TestInvoker_Invoke_UnlabeledJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A nil Content-Type keeps net/http from sniffing one.
+		w.Header()["Content-Type"] = nil
+		switch r.URL.Path {
+		case "/items":
+			// Flushing before the body is complete makes the response chunked.
+			_, _ = w.Write([]byte(`[{"id":1},`))
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte(` {"id":2}]`))
+		case "/broken":
+			_, _ = w.Write([]byte(`{"id":`))
+		default:
+			_, _ = w.Write([]byte(`plain text`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	invoker := httpinvoker.New(server.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name string
+		path string
+		want interface{}
+	}{
+		{
+			name: "chunked JSON without Content-Type",
+			path: "/items",
+			want: []interface{}{map[string]interface{}{"id": float64(1)}, map[string]interface{}{"id": float64(2)}},
+		},
+		{name: "invalid JSON stays text", path: "/broken", want: `{"id":`},
+		{name: "text stays text", path: "/text", want: "plain text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:       "http",
+				Host:       server.URL,
+				HTTPMethod: http.MethodGet,
+				HTTPPath:   tt.path,
+			}, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestInvoker_Invoke_MethodTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(200 * time.Millisecond):
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name    string
+		method  string
+		opts    []httpinvoker.Option
+		wantErr bool
+	}{
+		{
+			name:    "GET uses its shorter timeout",
+			method:  http.MethodGet,
+			opts:    []httpinvoker.Option{httpinvoker.WithCallTimeout(5 * time.Second), httpinvoker.WithMethodTimeouts(map[string]time.Duration{"get": 50 * time.Millisecond})},
+			wantErr: true,
+		},
+		{
+			name:   "POST falls back to the default timeout",
+			method: http.MethodPost,
+			opts:   []httpinvoker.Option{httpinvoker.WithCallTimeout(5 * time.Second), httpinvoker.WithMethodTimeouts(map[string]time.Duration{"get": 50 * time.Millisecond})},
+		},
+		{
+			name:    "default timeout",
+			method:  http.MethodPost,
+			opts:    []httpinvoker.Option{httpinvoker.WithCallTimeout(50 * time.Millisecond)},
+			wantErr: true,
+		},
+		{
+			name:   "method timeout overrides a shorter default",
+			method: http.MethodPost,
+			opts:   []httpinvoker.Option{httpinvoker.WithCallTimeout(50 * time.Millisecond), httpinvoker.WithMethodTimeouts(map[string]time.Duration{"POST": 5 * time.Second})},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoker := httpinvoker.New(server.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)), tt.opts...)
+			result, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:       "http",
+				Host:       server.URL,
+				HTTPMethod: tt.method,
+				HTTPPath:   "/reports",
+			}, nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{"ok": true}, result)
+		})
+	}
+}
+
+func TestInvoker_Invoke_DateNormalization(t *testing.T) {
+	tests := []struct {
+		name      string
+		disabled  bool
+		params    map[string]interface{}
+		wantQuery string
+		wantBody  string
+		wantErr   string
+	}{
+		{
+			name:      "loose date and date-time",
+			params:    map[string]interface{}{"day": "2024-1-2", "since": "2024-1-2 9:30", "note": "2024-1-2"},
+			wantQuery: "day=2024-01-02",
+			wantBody:  `{"note":"2024-1-2","since":"2024-01-02T09:30:00Z"}`,
+		},
+		{
+			name:      "already RFC 3339",
+			params:    map[string]interface{}{"day": "2024-01-02", "since": "2024-01-02T09:30:00.5+09:00"},
+			wantQuery: "day=2024-01-02",
+			wantBody:  `{"since":"2024-01-02T09:30:00.5+09:00"}`,
+		},
+		{
+			name:      "date-time given as a date",
+			params:    map[string]interface{}{"day": "Jan 2, 2024", "since": "2024/01/02"},
+			wantQuery: "day=2024-01-02",
+			wantBody:  `{"since":"2024-01-02T00:00:00Z"}`,
+		},
+		{
+			name:    "unparseable date",
+			params:  map[string]interface{}{"day": "next tuesday"},
+			wantErr: `parameter day: "next tuesday" is not a valid date`,
+		},
+		{
+			name:    "not a string",
+			params:  map[string]interface{}{"since": 1704153600},
+			wantErr: "parameter since must be a date-time string",
+		},
+		{
+			name:      "disabled",
+			disabled:  true,
+			params:    map[string]interface{}{"day": "2024-1-2", "since": "2024-1-2 9:30"},
+			wantQuery: "day=2024-1-2",
+			wantBody:  `{"since":"2024-1-2 9:30"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery, gotBody string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotQuery = r.URL.RawQuery
+				body, _ := io.ReadAll(r.Body)
+				gotBody = string(body)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			t.Cleanup(server.Close)
+
+			invoker := httpinvoker.New(server.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)),
+				httpinvoker.WithDateNormalization(!tt.disabled))
+			_, err := invoker.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:        "http",
+				Host:        server.URL,
+				HTTPMethod:  http.MethodPost,
+				HTTPPath:    "/reports",
+				QueryParams: []string{"day"},
+				ContentType: "application/json",
+				DateParams:  map[string]string{"day": "date", "since": "date-time"},
+			}, tt.params)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Equal(t, usecase.ErrorCategoryInvalidInput, usecase.ErrorCategoryOf(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.JSONEq(t, tt.wantBody, gotBody)
+		})
+	}
+}
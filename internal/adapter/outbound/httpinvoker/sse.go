@@ -0,0 +1,61 @@
+package httpinvoker
+
+import (
+	"bufio"
+	"bytes"
+	"mime"
+	"strings"
+)
+
+// isEventStream reports whether contentType is text/event-stream.
+func isEventStream(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/event-stream"
+}
+
+// parseSSE parses a Server-Sent Events body into {event, data} objects, one per
+// dispatched event. Multi-line data is joined with "\n", events without a type
+// default to "message", and comments and id/retry fields are ignored.
+func parseSSE(body []byte) ([]map[string]string, error) {
+	events := []map[string]string{}
+	eventType := ""
+	var data []string
+
+	dispatch := func() {
+		if len(data) > 0 {
+			if eventType == "" {
+				eventType = "message"
+			}
+			events = append(events, map[string]string{"event": eventType, "data": strings.Join(data, "\n")})
+		}
+		eventType = ""
+		data = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(body)+1)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			dispatch()
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	// A stream that ends without a trailing blank line still delivers its last event.
+	dispatch()
+	return events, nil
+}
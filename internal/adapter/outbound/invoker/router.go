@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 
 	"github.com/i2y/mcpizer/internal/adapter/outbound/connect"
 	"github.com/i2y/mcpizer/internal/adapter/outbound/grpcinvoker"
@@ -12,36 +13,69 @@ import (
 	"github.com/i2y/mcpizer/internal/usecase"
 )
 
+// InvokerFunc adapts an ordinary function to the usecase.ToolInvoker interface.
+type InvokerFunc func(ctx context.Context, details usecase.InvocationDetails, params map[string]interface{}) (interface{}, error)
+
+// Invoke calls f(ctx, details, params).
+func (f InvokerFunc) Invoke(ctx context.Context, details usecase.InvocationDetails, params map[string]interface{}) (interface{}, error) {
+	return f(ctx, details, params)
+}
+
 // Router implements usecase.ToolInvoker and routes invocations based on the Type field
+// to the invoker registered for that type.
 type Router struct {
-	httpInvoker    *httpinvoker.Invoker
-	grpcInvoker    *grpcinvoker.Invoker
-	connectInvoker *connect.Invoker
-	logger         *slog.Logger
+	mu       sync.RWMutex
+	invokers map[string]usecase.ToolInvoker
+	logger   *slog.Logger
 }
 
-// NewRouter creates a new invoker router
+// NewRouter creates a new invoker router with the built-in "http" (also used for an
+// empty type), "grpc", and "connect" invokers registered.
 func NewRouter(httpInv *httpinvoker.Invoker, grpcInv *grpcinvoker.Invoker, connectInv *connect.Invoker, logger *slog.Logger) *Router {
-	return &Router{
-		httpInvoker:    httpInv,
-		grpcInvoker:    grpcInv,
-		connectInvoker: connectInv,
-		logger:         logger.With("component", "invoker_router"),
+	r := &Router{
+		invokers: make(map[string]usecase.ToolInvoker),
+		logger:   logger.With("component", "invoker_router"),
 	}
+	r.Register("http", httpInv)
+	r.Register("", httpInv)
+	r.Register("grpc", grpcRoute(grpcInv, r.logger))
+	r.Register("connect", connectRoute(connectInv))
+	return r
+}
+
+// Register maps an invocation type (InvocationDetails.Type) to an invoker,
+// replacing any invoker previously registered for that type.
+func (r *Router) Register(invocationType string, inv usecase.ToolInvoker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.invokers[invocationType] = inv
 }
 
 // Invoke routes the invocation to the appropriate invoker based on the details.Type
 func (r *Router) Invoke(ctx context.Context, details usecase.InvocationDetails, params map[string]interface{}) (interface{}, error) {
 	log := r.logger.With(slog.String("type", details.Type))
 
-	switch details.Type {
-	case "grpc":
-		log.Info("Routing to gRPC invoker")
+	r.mu.RLock()
+	inv, ok := r.invokers[details.Type]
+	r.mu.RUnlock()
+	if !ok {
+		log.Error("Unknown invocation type", slog.String("type", details.Type))
+		return nil, fmt.Errorf("unknown invocation type: %s", details.Type)
+	}
+
+	log.Info("Routing to invoker")
+	return inv.Invoke(ctx, details, params)
+}
+
+// grpcRoute adapts the gRPC invoker to the ToolInvoker interface.
+func grpcRoute(grpcInvoker *grpcinvoker.Invoker, logger *slog.Logger) InvokerFunc {
+	return func(ctx context.Context, details usecase.InvocationDetails, params map[string]interface{}) (interface{}, error) {
 		// Use Server field if available (for .proto files), otherwise fall back to Host
 		target := details.Host
 		if details.Server != "" {
 			target = details.Server
 		}
+		opts := grpcinvoker.CallOptions{LoadBalancing: details.LoadBalancing}
 		// Use Method field if available (for .proto files), otherwise use GRPCService/GRPCMethod
 		if details.Method != "" {
 			// Method already contains the full path like /package.Service/Method
@@ -51,27 +85,37 @@ func (r *Router) Invoke(ctx context.Context, details usecase.InvocationDetails,
 				// parts[0] is empty, parts[1] is package.Service, parts[2] is Method
 				// parts[1] contains the full service name like "package.Service"
 				method := parts[2]
-				return r.grpcInvoker.InvokeGRPC(ctx, target, parts[1], method, params)
+				// Descriptors known from the schema make server reflection unnecessary.
+				if details.FileDescriptor != nil {
+					files, err := grpcinvoker.DescriptorSet(details.FileDescriptor)
+					if err != nil {
+						logger.Warn("Ignoring unreadable file descriptor of gRPC tool, falling back to server reflection.",
+							slog.String("method", details.Method), slog.Any("error", err))
+					} else {
+						opts.Files = files
+					}
+				}
+				return grpcInvoker.InvokeGRPCWithOptions(ctx, target, parts[1], method, opts, params)
 			}
 		}
-		return r.grpcInvoker.InvokeGRPC(ctx, target, details.GRPCService, details.GRPCMethod, params)
+		return grpcInvoker.InvokeGRPCWithOptions(ctx, target, details.GRPCService, details.GRPCMethod, opts, params)
+	}
+}
 
-	case "connect":
-		log.Info("Routing to Connect-RPC invoker")
+// connectRoute adapts the Connect-RPC invoker to the ToolInvoker interface.
+func connectRoute(connectInvoker *connect.Invoker) InvokerFunc {
+	return func(ctx context.Context, details usecase.InvocationDetails, params map[string]interface{}) (interface{}, error) {
 		// Use Server field for the Connect-RPC server URL
 		server := details.Host
 		if details.Server != "" {
 			server = details.Server
 		}
 		// Method contains the full path like /package.Service/Method
-		return r.connectInvoker.InvokeHTTP(ctx, server, details.Method, params)
-
-	case "http", "":
-		log.Info("Routing to HTTP invoker")
-		return r.httpInvoker.Invoke(ctx, details, params)
-
-	default:
-		log.Error("Unknown invocation type", slog.String("type", details.Type))
-		return nil, fmt.Errorf("unknown invocation type: %s", details.Type)
+		opts := connect.HTTPOptions{
+			ProtocolVersion: details.ConnectProtocolVersion,
+			AcceptEncoding:  details.AcceptEncoding,
+			AcceptLanguage:  details.AcceptLanguage,
+		}
+		return connectInvoker.InvokeHTTPWithOptions(ctx, server, details.Method, params, opts)
 	}
 }
@@ -0,0 +1,92 @@
+package invoker_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/grpcinvoker"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/httpinvoker"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/invoker"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+func newTestRouter(t *testing.T, client *http.Client) *invoker.Router {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return invoker.NewRouter(httpinvoker.New(client, logger), nil, nil, logger)
+}
+
+func TestRouter_Invoke_CustomType(t *testing.T) {
+	router := newTestRouter(t, http.DefaultClient)
+
+	var got usecase.InvocationDetails
+	router.Register("graphql", invoker.InvokerFunc(func(ctx context.Context, details usecase.InvocationDetails, params map[string]interface{}) (interface{}, error) {
+		got = details
+		return map[string]interface{}{"query": params["query"]}, nil
+	}))
+
+	details := usecase.InvocationDetails{Type: "graphql", Host: "https://graphql.example.com"}
+	result, err := router.Invoke(context.Background(), details, map[string]interface{}{"query": "{ pets { name } }"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"query": "{ pets { name } }"}, result)
+	assert.Equal(t, details, got)
+}
+
+func TestRouter_Invoke_BuiltInHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	router := newTestRouter(t, server.Client())
+
+	for _, invocationType := range []string{"http", ""} {
+		t.Run("type "+invocationType, func(t *testing.T) {
+			result, err := router.Invoke(context.Background(), usecase.InvocationDetails{
+				Type:       invocationType,
+				Host:       server.URL,
+				HTTPMethod: http.MethodGet,
+				HTTPPath:   "/status",
+			}, nil)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{"ok": true}, result)
+		})
+	}
+}
+
+func TestRouter_Invoke_UnknownType(t *testing.T) {
+	router := newTestRouter(t, http.DefaultClient)
+
+	_, err := router.Invoke(context.Background(), usecase.InvocationDetails{Type: "soap"}, nil)
+	assert.EqualError(t, err, "unknown invocation type: soap")
+}
+
+func TestRouter_Invoke_GRPCInvalidDescriptor(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	router := invoker.NewRouter(nil, grpcinvoker.NewInvoker(logger), nil, logger)
+
+	// Nothing listens on the target, so the reflection fallback fails as well.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err := router.Invoke(ctx, usecase.InvocationDetails{
+		Type:           "grpc",
+		Host:           "127.0.0.1:1",
+		Method:         "/test.v1.UserService/GetUser",
+		FileDescriptor: "not a descriptor",
+	}, map[string]interface{}{})
+
+	require.Error(t, err)
+	assert.Contains(t, logs.String(), "Ignoring unreadable file descriptor of gRPC tool")
+	assert.Contains(t, logs.String(), "/test.v1.UserService/GetUser")
+}
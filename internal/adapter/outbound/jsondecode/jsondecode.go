@@ -0,0 +1,25 @@
+package jsondecode
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Unmarshal decodes data like json.Unmarshal. With useNumber, numbers are kept
+// as json.Number so large integers (e.g., 64-bit IDs) round-trip exactly.
+func Unmarshal(data []byte, v interface{}, useNumber bool) error {
+	if !useNumber {
+		return json.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid data after top-level JSON value")
+	}
+	return nil
+}
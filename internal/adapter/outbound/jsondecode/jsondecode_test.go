@@ -0,0 +1,40 @@
+package jsondecode_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/jsondecode"
+)
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		useNumber bool
+		want      interface{}
+		wantErr   bool
+	}{
+		{name: "float64", data: `{"id":9007199254740993}`, want: map[string]interface{}{"id": float64(9007199254740993)}},
+		{name: "number", data: `{"id":9007199254740993}`, useNumber: true, want: map[string]interface{}{"id": json.Number("9007199254740993")}},
+		{name: "trailing data", data: `{"id":1} {"id":2}`, useNumber: true, wantErr: true},
+		{name: "trailing data without numbers", data: `{"id":1} {"id":2}`, wantErr: true},
+		{name: "invalid", data: `{"id":`, useNumber: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got interface{}
+			err := jsondecode.Unmarshal([]byte(tt.data), &got, tt.useNumber)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -94,3 +94,16 @@ func (r *InMemoryToolRepository) FindInvocationDetailsByName(ctx context.Context
 	r.logger.Debug("Found invocation details", slog.String("tool_name", name))
 	return &details, nil
 }
+
+// Delete removes tools and their invocation details by name.
+func (r *InMemoryToolRepository) Delete(ctx context.Context, names ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range names {
+		delete(r.tools, name)
+		delete(r.invocationDetails, name)
+	}
+	r.logger.Debug("Deleted tools from repository", slog.Int("count", len(names)), slog.Int("total_tools", len(r.tools)))
+	return nil
+}
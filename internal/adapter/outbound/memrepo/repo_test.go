@@ -206,3 +206,23 @@ func TestInMemoryToolRepository_SaveOverwrite(t *testing.T) {
 	assert.Len(list, 1)
 	assert.Equal(toolV2, list[0])
 }
+
+func TestInMemoryToolRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	tools := []domain.Tool{{Name: "keep"}, {Name: "drop"}}
+	details := []usecase.InvocationDetails{{Type: "http", Host: "keep"}, {Type: "http", Host: "drop"}}
+	require.NoError(t, repo.Save(ctx, tools, details))
+
+	require.NoError(t, repo.Delete(ctx, "drop", "unknown"))
+
+	_, err := repo.FindToolByName(ctx, "drop")
+	assert.ErrorIs(t, err, usecase.ErrToolNotFound)
+	_, err = repo.FindInvocationDetailsByName(ctx, "drop")
+	assert.ErrorIs(t, err, usecase.ErrToolNotFound)
+
+	list, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Tool{{Name: "keep"}}, list)
+}
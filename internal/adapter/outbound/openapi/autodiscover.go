@@ -26,18 +26,53 @@ var commonOpenAPIPaths = []string{
 	"/api-spec.json",           // Custom spec name
 }
 
+// versionedOpenAPIPathTemplates are probed for each API version when discovering
+// every spec at a base URL; %d is replaced with the version number.
+var versionedOpenAPIPathTemplates = []string{
+	"/v%d/openapi.json",
+	"/v%d/swagger.json",
+	"/v%d/api-docs",
+	"/api/v%d/openapi.json",
+	"/swagger/v%d/swagger.json",
+}
+
+// maxDiscoveredAPIVersion is the highest API version probed by DiscoverAllSchemas.
+const maxDiscoveredAPIVersion = 5
+
+// DefaultProbeConcurrency is the number of discovery probes in flight at once.
+const DefaultProbeConcurrency = 4
+
 // AutoDiscoverer attempts to find OpenAPI schemas from base URLs
 type AutoDiscoverer struct {
-	client *http.Client
-	logger *slog.Logger
+	client      *http.Client
+	logger      *slog.Logger
+	concurrency int
+}
+
+// AutoDiscovererOption configures optional AutoDiscoverer behavior.
+type AutoDiscovererOption func(*AutoDiscoverer)
+
+// WithProbeConcurrency runs up to n discovery probes at once; 1 probes the paths one
+// after another. Values below 1 keep DefaultProbeConcurrency.
+func WithProbeConcurrency(n int) AutoDiscovererOption {
+	return func(d *AutoDiscoverer) {
+		if n > 0 {
+			d.concurrency = n
+		}
+	}
 }
 
 // NewAutoDiscoverer creates a new OpenAPI schema auto-discoverer
-func NewAutoDiscoverer(client *http.Client, logger *slog.Logger) *AutoDiscoverer {
-	return &AutoDiscoverer{
-		client: client,
-		logger: logger.With("component", "openapi_autodiscoverer"),
+func NewAutoDiscoverer(client *http.Client, logger *slog.Logger, opts ...AutoDiscovererOption) *AutoDiscoverer {
+	d := &AutoDiscoverer{
+		client:      client,
+		logger:      logger.With("component", "openapi_autodiscoverer"),
+		concurrency: DefaultProbeConcurrency,
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 // DiscoverSchema attempts to find an OpenAPI schema from a base URL
@@ -57,18 +92,17 @@ func (d *AutoDiscoverer) DiscoverSchema(ctx context.Context, baseURL string) (st
 	}
 
 	// Try each common path
-	for _, path := range commonOpenAPIPaths {
-		schemaURL := parsedURL.String() + path
-		log.Debug("Trying OpenAPI path", slog.String("url", schemaURL))
-
-		if found, err := d.checkOpenAPIEndpoint(ctx, schemaURL); found {
-			log.Info("Found OpenAPI schema", slog.String("url", schemaURL))
-			return schemaURL, nil
-		} else if err != nil {
-			log.Debug("Failed to check endpoint",
-				slog.String("url", schemaURL),
-				slog.Any("error", err))
-		}
+	candidates := make([]string, len(commonOpenAPIPaths))
+	for i, path := range commonOpenAPIPaths {
+		candidates[i] = parsedURL.String() + path
+	}
+	found, err := d.probe(ctx, log, candidates, true, d.checkOpenAPIEndpoint)
+	if err != nil {
+		return "", err
+	}
+	if len(found) > 0 {
+		log.Info("Found OpenAPI schema", slog.String("url", found[0]))
+		return found[0], nil
 	}
 
 	// Try to find links in the root page (some services expose discovery links)
@@ -88,16 +122,12 @@ func (d *AutoDiscoverer) checkOpenAPIEndpoint(ctx context.Context, schemaURL str
 	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(reqCtx, "GET", schemaURL, nil)
-	if err != nil {
-		return false, err
-	}
-
 	// Some APIs require specific headers
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "MCP-Bridge/1.0")
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	header.Set("User-Agent", "MCP-Bridge/1.0")
 
-	resp, err := d.client.Do(req)
+	resp, err := d.probeRequest(reqCtx, schemaURL, header)
 	if err != nil {
 		return false, err
 	}
@@ -108,16 +138,8 @@ func (d *AutoDiscoverer) checkOpenAPIEndpoint(ctx context.Context, schemaURL str
 		return false, nil
 	}
 
-	// Check content type
-	contentType := resp.Header.Get("Content-Type")
-	if !strings.Contains(contentType, "application/json") &&
-		!strings.Contains(contentType, "application/vnd.oai.openapi+json") {
-		return false, nil
-	}
-
-	// We could parse and validate the JSON here, but for discovery
-	// purposes, just checking the response is enough
-	return true, nil
+	// Check content type, or sniff the body of servers that mislabel their spec
+	return d.holdsOpenAPIDocument(reqCtx, schemaURL, header, resp)
 }
 
 // checkRootPageForLinks checks the root page for OpenAPI discovery links
@@ -148,6 +170,9 @@ func (d *AutoDiscoverer) ResolveSchemaSource(ctx context.Context, source string)
 	log.Info("Source appears to be a base URL, attempting auto-discovery")
 	discoveredURL, err := d.DiscoverSchema(ctx, source)
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
 		// If auto-discovery fails, return the original source
 		// This allows manual schema URLs to still work
 		log.Warn("Auto-discovery failed, using original source", slog.Any("error", err))
@@ -175,6 +200,9 @@ func (d *AutoDiscoverer) ResolveSchemaSourceWithHeaders(ctx context.Context, sou
 	log.Info("Source appears to be a base URL, attempting auto-discovery with headers")
 	discoveredURL, err := d.DiscoverSchemaWithHeaders(ctx, source, headers)
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
 		// If auto-discovery fails, return the original source
 		// This allows manual schema URLs to still work
 		log.Warn("Auto-discovery failed, using original source", slog.Any("error", err))
@@ -201,17 +229,13 @@ func (d *AutoDiscoverer) DiscoverSchemaWithHeaders(ctx context.Context, baseURL
 	}
 
 	// Try common OpenAPI paths
-	for _, path := range commonOpenAPIPaths {
-		testURL := strings.TrimRight(baseURL, "/") + path
-		log.Debug("Testing OpenAPI path", slog.String("url", testURL))
-
-		if valid, err := d.isValidOpenAPIWithHeaders(ctx, testURL, headers); err != nil {
-			log.Debug("Error checking path", slog.String("url", testURL), slog.Any("error", err))
-			continue
-		} else if valid {
-			log.Info("Found OpenAPI schema", slog.String("url", testURL))
-			return testURL, nil
-		}
+	found, err := d.probe(ctx, log, probeURLs(baseURL, commonOpenAPIPaths), true, d.headerProbe(headers))
+	if err != nil {
+		return "", err
+	}
+	if len(found) > 0 {
+		log.Info("Found OpenAPI schema", slog.String("url", found[0]))
+		return found[0], nil
 	}
 
 	// Try to find discovery links on the root page
@@ -222,27 +246,147 @@ func (d *AutoDiscoverer) DiscoverSchemaWithHeaders(ctx context.Context, baseURL
 	return "", fmt.Errorf("no OpenAPI schema found at base URL: %s", baseURL)
 }
 
+// DiscoverAllSchemas returns every OpenAPI schema URL found at a base URL, including
+// versioned specs such as /v1/openapi.json and /v2/openapi.json, in probe order.
+func (d *AutoDiscoverer) DiscoverAllSchemas(ctx context.Context, baseURL string, headers map[string]string) ([]string, error) {
+	log := d.logger.With(slog.String("base_url", baseURL))
+	log.Info("Attempting to auto-discover all OpenAPI schemas")
+
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if parsedURL.Scheme == "" {
+		return nil, fmt.Errorf("base URL must include scheme (http:// or https://)")
+	}
+
+	found, err := d.probe(ctx, log, probeURLs(baseURL, discoveryPaths()), false, d.headerProbe(headers))
+	if err != nil {
+		return nil, err
+	}
+	for _, schemaURL := range found {
+		log.Info("Found OpenAPI schema", slog.String("url", schemaURL))
+	}
+
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no OpenAPI schema found at base URL: %s", baseURL)
+	}
+	return found, nil
+}
+
+// probeURLs joins baseURL with each of paths.
+func probeURLs(baseURL string, paths []string) []string {
+	urls := make([]string, len(paths))
+	for i, path := range paths {
+		urls[i] = strings.TrimRight(baseURL, "/") + path
+	}
+	return urls
+}
+
+// headerProbe returns a probe checking a URL with isValidOpenAPIWithHeaders.
+func (d *AutoDiscoverer) headerProbe(headers map[string]string) func(context.Context, string) (bool, error) {
+	return func(ctx context.Context, testURL string) (bool, error) {
+		return d.isValidOpenAPIWithHeaders(ctx, testURL, headers)
+	}
+}
+
+// probe checks urls with up to d.concurrency checks in flight and returns the ones
+// that hold an OpenAPI schema, in the order of urls. With first set it returns only
+// the earliest one, and stops the remaining checks once it is known. It returns
+// ctx's error as soon as ctx is done.
+func (d *AutoDiscoverer) probe(ctx context.Context, log *slog.Logger, urls []string, first bool, check func(context.Context, string) (bool, error)) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		index int
+		found bool
+	}
+	results := make(chan result)
+	slots := make(chan struct{}, max(d.concurrency, 1))
+	go func() {
+		for i, testURL := range urls {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				defer func() { <-slots }()
+				log.Debug("Testing OpenAPI path", slog.String("url", testURL))
+				found, err := check(ctx, testURL)
+				if err != nil && ctx.Err() == nil {
+					log.Debug("Error checking path", slog.String("url", testURL), slog.Any("error", err))
+				}
+				select {
+				case results <- result{index: i, found: found}:
+				case <-ctx.Done():
+				}
+			}()
+		}
+	}()
+
+	// Results arrive in any order; next is the earliest URL whose result is still pending.
+	done := make([]bool, len(urls))
+	hits := make([]bool, len(urls))
+	var found []string
+	for next := 0; next < len(urls); {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case r := <-results:
+			done[r.index], hits[r.index] = true, r.found
+		}
+		for ; next < len(urls) && done[next]; next++ {
+			if hits[next] {
+				found = append(found, urls[next])
+				if first {
+					return found, nil
+				}
+			}
+		}
+	}
+	return found, nil
+}
+
+// discoveryPaths returns the common paths followed by the versioned paths, without duplicates.
+func discoveryPaths() []string {
+	seen := make(map[string]struct{})
+	var paths []string
+	add := func(path string) {
+		if _, ok := seen[path]; !ok {
+			seen[path] = struct{}{}
+			paths = append(paths, path)
+		}
+	}
+	for _, path := range commonOpenAPIPaths {
+		add(path)
+	}
+	for version := 1; version <= maxDiscoveredAPIVersion; version++ {
+		for _, tmpl := range versionedOpenAPIPathTemplates {
+			add(fmt.Sprintf(tmpl, version))
+		}
+	}
+	return paths
+}
+
 // isValidOpenAPIWithHeaders checks if a URL returns a valid OpenAPI response with custom headers
 func (d *AutoDiscoverer) isValidOpenAPIWithHeaders(ctx context.Context, testURL string, headers map[string]string) (bool, error) {
 	// Create a timeout context for the probe
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, testURL, nil)
-	if err != nil {
-		return false, err
-	}
-
 	// Set standard headers
-	req.Header.Set("Accept", "application/json, application/vnd.oai.openapi+json")
-	req.Header.Set("User-Agent", "MCPizer/1.0")
+	header := http.Header{}
+	header.Set("Accept", "application/json, application/vnd.oai.openapi+json")
+	header.Set("User-Agent", "MCPizer/1.0")
 
 	// Add custom headers
 	for key, value := range headers {
-		req.Header.Set(key, value)
+		header.Set(key, value)
 	}
 
-	resp, err := d.client.Do(req)
+	resp, err := d.probeRequest(ctx, testURL, header)
 	if err != nil {
 		return false, err
 	}
@@ -253,16 +397,34 @@ func (d *AutoDiscoverer) isValidOpenAPIWithHeaders(ctx context.Context, testURL
 		return false, nil
 	}
 
-	// Check content type
-	contentType := resp.Header.Get("Content-Type")
-	if !strings.Contains(contentType, "application/json") &&
-		!strings.Contains(contentType, "application/vnd.oai.openapi+json") {
-		return false, nil
+	// Check content type, or sniff the body of servers that mislabel their spec
+	return d.holdsOpenAPIDocument(ctx, testURL, header, resp)
+}
+
+// probeRequest requests testURL with HEAD, which tells status and content type without
+// downloading the document, and falls back to GET when the server does not allow HEAD.
+// The caller closes the response body.
+func (d *AutoDiscoverer) probeRequest(ctx context.Context, testURL string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, testURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header.Clone()
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusMethodNotAllowed && resp.StatusCode != http.StatusNotImplemented {
+		return resp, nil
 	}
+	resp.Body.Close()
 
-	// We could parse and validate the JSON here, but for discovery
-	// purposes, just checking the response is enough
-	return true, nil
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, testURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header.Clone()
+	return d.client.Do(req)
 }
 
 // checkRootPageForLinksWithHeaders checks the root page for OpenAPI discovery links with custom headers
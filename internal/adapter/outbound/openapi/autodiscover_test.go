@@ -0,0 +1,165 @@
+package openapi_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/openapi"
+)
+
+func TestAutoDiscoverer_DiscoverSchema_Cancellation(t *testing.T) {
+	// Every probe hangs until the client gives up, like an unreachable host.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	discoverer := openapi.NewAutoDiscoverer(srv.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name     string
+		discover func(ctx context.Context) error
+	}{
+		{
+			name: "DiscoverSchema",
+			discover: func(ctx context.Context) error {
+				_, err := discoverer.DiscoverSchema(ctx, srv.URL)
+				return err
+			},
+		},
+		{
+			name: "DiscoverSchemaWithHeaders",
+			discover: func(ctx context.Context) error {
+				_, err := discoverer.DiscoverSchemaWithHeaders(ctx, srv.URL, nil)
+				return err
+			},
+		},
+		{
+			name: "DiscoverAllSchemas",
+			discover: func(ctx context.Context) error {
+				_, err := discoverer.DiscoverAllSchemas(ctx, srv.URL, nil)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			start := time.Now()
+			err := tt.discover(ctx)
+			require.ErrorIs(t, err, context.Canceled)
+			assert.Less(t, time.Since(start), time.Second, "discovery should stop promptly once cancelled")
+		})
+	}
+}
+
+func TestAutoDiscoverer_DiscoverSchema_PrefersProbeOrder(t *testing.T) {
+	// /openapi.json answers last, but it is probed before /swagger.json and wins.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/openapi.json":
+			time.Sleep(100 * time.Millisecond)
+		case "/swagger.json":
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, concurrency := range []int{1, openapi.DefaultProbeConcurrency} {
+		discoverer := openapi.NewAutoDiscoverer(srv.Client(), logger, openapi.WithProbeConcurrency(concurrency))
+
+		found, err := discoverer.DiscoverSchema(context.Background(), srv.URL)
+		require.NoError(t, err)
+		assert.Equal(t, srv.URL+"/openapi.json", found)
+
+		all, err := discoverer.DiscoverAllSchemas(context.Background(), srv.URL, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{srv.URL + "/openapi.json", srv.URL + "/swagger.json"}, all)
+	}
+}
+
+func TestAutoDiscoverer_DiscoverSchema_HeadProbe(t *testing.T) {
+	const largeBody = 64 << 20
+
+	tests := []struct {
+		name        string
+		allowHead   bool
+		wantMethods []string
+	}{
+		{name: "HEAD allowed", allowHead: true, wantMethods: []string{http.MethodHead}},
+		{name: "HEAD not allowed", allowHead: false, wantMethods: []string{http.MethodHead, http.MethodGet}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var methods []string
+			written := make(chan int, 1)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/openapi.json" {
+					http.NotFound(w, r)
+					return
+				}
+				mu.Lock()
+				methods = append(methods, r.Method)
+				mu.Unlock()
+				if r.Method == http.MethodHead && !tt.allowHead {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Length", strconv.Itoa(largeBody))
+				if r.Method == http.MethodHead {
+					return
+				}
+				// Count how much of the large document reaches the client before it hangs up.
+				chunk := make([]byte, 64<<10)
+				n := 0
+				for n < largeBody {
+					m, err := w.Write(chunk)
+					n += m
+					if err != nil {
+						break
+					}
+				}
+				written <- n
+			}))
+			defer srv.Close()
+
+			discoverer := openapi.NewAutoDiscoverer(srv.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)), openapi.WithProbeConcurrency(1))
+			found, err := discoverer.DiscoverSchemaWithHeaders(context.Background(), srv.URL, nil)
+			require.NoError(t, err)
+			assert.Equal(t, srv.URL+"/openapi.json", found)
+
+			mu.Lock()
+			assert.Equal(t, tt.wantMethods, methods)
+			mu.Unlock()
+			if !tt.allowHead {
+				select {
+				case n := <-written:
+					assert.Less(t, n, largeBody, "the probe should not download the whole document")
+				case <-time.After(5 * time.Second):
+					t.Fatal("GET probe did not finish")
+				}
+			}
+		})
+	}
+}
@@ -3,12 +3,12 @@ package openapi
 import (
 	"context"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
 
+	"github.com/i2y/mcpizer/internal/adapter/outbound/tlsclient"
 	"github.com/i2y/mcpizer/internal/domain"
 	"github.com/i2y/mcpizer/internal/usecase"
 
@@ -18,20 +18,44 @@ import (
 // SchemaFetcher implements the usecase.SchemaFetcher interface for OpenAPI schemas.
 type SchemaFetcher struct {
 	httpClient     *http.Client
+	tlsClients     *tlsclient.Cache
 	logger         *slog.Logger
 	autoDiscoverer *AutoDiscoverer
+	// streamThreshold is the declared size from which JSON documents are streamed.
+	streamThreshold int64
 }
 
-// NewSchemaFetcher creates a new OpenAPI SchemaFetcher.
-func NewSchemaFetcher(client *http.Client, logger *slog.Logger) *SchemaFetcher {
+// SchemaFetcherOption configures optional SchemaFetcher behavior.
+type SchemaFetcherOption func(*SchemaFetcher)
+
+// WithStreamThreshold streams JSON documents whose Content-Length or file size is at
+// least n bytes, or unknown, into the parser instead of reading them whole first. Streamed schemas
+// carry no RawData, so skipped operations are reported without their spec lines.
+// Zero or less never streams.
+func WithStreamThreshold(n int64) SchemaFetcherOption {
+	return func(f *SchemaFetcher) {
+		f.streamThreshold = n
+	}
+}
+
+// NewSchemaFetcher creates a new OpenAPI SchemaFetcher. JSON documents of unknown size
+// or of at least DefaultStreamThreshold bytes are streamed unless WithStreamThreshold
+// says otherwise.
+func NewSchemaFetcher(client *http.Client, logger *slog.Logger, opts ...SchemaFetcherOption) *SchemaFetcher {
 	if client == nil {
 		client = http.DefaultClient
 	}
-	return &SchemaFetcher{
-		httpClient:     client,
-		logger:         logger.With("component", "openapi_fetcher"),
-		autoDiscoverer: NewAutoDiscoverer(client, logger),
+	f := &SchemaFetcher{
+		httpClient:      client,
+		tlsClients:      tlsclient.NewCache(client),
+		logger:          logger.With("component", "openapi_fetcher"),
+		autoDiscoverer:  NewAutoDiscoverer(client, logger),
+		streamThreshold: DefaultStreamThreshold,
+	}
+	for _, opt := range opts {
+		opt(f)
 	}
+	return f
 }
 
 // Fetch loads an OpenAPI schema from a URL or local file path.
@@ -75,17 +99,25 @@ func (f *SchemaFetcher) Fetch(ctx context.Context, src string) (domain.APISchema
 			return domain.APISchema{}, fmt.Errorf("failed to fetch schema from URL %s: status %s", resolvedSrc, resp.Status)
 		}
 
-		bodyBytes, readErr := io.ReadAll(resp.Body)
+		body, readErr := readSpec(resp.Body, resp.ContentLength, f.streamThreshold)
 		if readErr != nil {
 			log.Error("Failed to read response body from URL", slog.Any("error", readErr))
 			return domain.APISchema{}, fmt.Errorf("failed to read response body from %s: %w", resolvedSrc, readErr)
 		}
-		rawData = bodyBytes
-		doc, err = loader.LoadFromData(rawData)
+		if !body.looksLikeOpenAPI() {
+			log.Warn("Response does not look like an OpenAPI document", slog.String("content_type", resp.Header.Get("Content-Type")))
+			return domain.APISchema{}, fmt.Errorf("response from %s does not look like an OpenAPI document (Content-Type %q)", resolvedSrc, resp.Header.Get("Content-Type"))
+		}
+		if body.stream != nil {
+			log.Debug("Streaming large OpenAPI document", slog.Int64("content_length", resp.ContentLength))
+		}
+		// The declared content type is not trusted: some servers send JSON specs as text/html.
+		rawData = body.data
+		doc, err = body.load(loader)
 
 	} else {
 		log.Debug("Assuming local file path")
-		fileData, readErr := os.ReadFile(resolvedSrc)
+		fileData, readErr := f.openSpec(resolvedSrc)
 		if readErr != nil {
 			// Log specific error based on whether it looked like a URL initially
 			if parseErr == nil {
@@ -96,11 +128,12 @@ func (f *SchemaFetcher) Fetch(ctx context.Context, src string) (domain.APISchema
 				return domain.APISchema{}, fmt.Errorf("failed to read schema from file %s: %w", resolvedSrc, readErr)
 			}
 		}
-		rawData = fileData
-		doc, err = loader.LoadFromData(rawData)
+		rawData = fileData.data
+		doc, err = fileData.load(loader)
 	}
 
 	if err != nil {
+		err = locateParseError(ctx, rawData, err)
 		log.Error("Failed to parse OpenAPI schema data", slog.Any("error", err))
 		return domain.APISchema{}, fmt.Errorf("failed to parse OpenAPI schema from %s: %w", src, err)
 	}
@@ -125,8 +158,13 @@ func (f *SchemaFetcher) FetchWithConfig(ctx context.Context, config usecase.Sche
 		log.Info("Fetching OpenAPI schema with custom headers", slog.Int("header_count", len(config.Headers)))
 	}
 
+	client, discoverer, err := f.clientFor(config)
+	if err != nil {
+		return domain.APISchema{}, fmt.Errorf("invalid TLS settings for %s: %w", config.URL, err)
+	}
+
 	// Try auto-discovery first
-	resolvedSrc, err := f.autoDiscoverer.ResolveSchemaSourceWithHeaders(ctx, config.URL, config.Headers)
+	resolvedSrc, err := discoverer.ResolveSchemaSourceWithHeaders(ctx, config.URL, config.Headers)
 	if err != nil {
 		log.Warn("Failed to resolve schema source", slog.Any("error", err))
 		// Continue with original source
@@ -155,7 +193,7 @@ func (f *SchemaFetcher) FetchWithConfig(ctx context.Context, config usecase.Sche
 			req.Header.Set(key, value)
 		}
 
-		resp, httpErr := f.httpClient.Do(req)
+		resp, httpErr := client.Do(req)
 		if httpErr != nil {
 			log.Error("Failed to fetch schema from URL", slog.Any("error", httpErr))
 			return domain.APISchema{}, fmt.Errorf("failed to fetch schema from URL %s: %w", config.URL, httpErr)
@@ -167,18 +205,26 @@ func (f *SchemaFetcher) FetchWithConfig(ctx context.Context, config usecase.Sche
 			return domain.APISchema{}, fmt.Errorf("failed to fetch schema from URL %s: status %s", resolvedSrc, resp.Status)
 		}
 
-		bodyBytes, readErr := io.ReadAll(resp.Body)
+		body, readErr := readSpec(resp.Body, resp.ContentLength, f.streamThreshold)
 		if readErr != nil {
 			log.Error("Failed to read response body from URL", slog.Any("error", readErr))
 			return domain.APISchema{}, fmt.Errorf("failed to read response body from %s: %w", resolvedSrc, readErr)
 		}
-		rawData = bodyBytes
-		doc, err = loader.LoadFromData(rawData)
+		if !body.looksLikeOpenAPI() {
+			log.Warn("Response does not look like an OpenAPI document", slog.String("content_type", resp.Header.Get("Content-Type")))
+			return domain.APISchema{}, fmt.Errorf("response from %s does not look like an OpenAPI document (Content-Type %q)", resolvedSrc, resp.Header.Get("Content-Type"))
+		}
+		if body.stream != nil {
+			log.Debug("Streaming large OpenAPI document", slog.Int64("content_length", resp.ContentLength))
+		}
+		// The declared content type is not trusted: some servers send JSON specs as text/html.
+		rawData = body.data
+		doc, err = body.load(loader)
 
 	} else {
 		// For local files, headers are ignored
 		log.Debug("Assuming local file path (headers ignored)")
-		fileData, readErr := os.ReadFile(resolvedSrc)
+		fileData, readErr := f.openSpec(resolvedSrc)
 		if readErr != nil {
 			if parseErr == nil {
 				log.Error("Source looked like URL but fetch failed, and file read also failed", slog.Any("file_read_error", readErr))
@@ -188,11 +234,12 @@ func (f *SchemaFetcher) FetchWithConfig(ctx context.Context, config usecase.Sche
 				return domain.APISchema{}, fmt.Errorf("failed to read schema from file %s: %w", resolvedSrc, readErr)
 			}
 		}
-		rawData = fileData
-		doc, err = loader.LoadFromData(rawData)
+		rawData = fileData.data
+		doc, err = fileData.load(loader)
 	}
 
 	if err != nil {
+		err = locateParseError(ctx, rawData, err)
 		log.Error("Failed to parse OpenAPI schema data", slog.Any("error", err))
 		return domain.APISchema{}, fmt.Errorf("failed to parse OpenAPI schema from %s: %w", config.URL, err)
 	}
@@ -201,6 +248,23 @@ func (f *SchemaFetcher) FetchWithConfig(ctx context.Context, config usecase.Sche
 		log.Warn("OpenAPI schema validation failed", slog.Any("validation_error", validateErr))
 	}
 
+	// A per-source server overrides the document's servers block, including the
+	// servers of individual paths and operations
+	if config.Server != "" {
+		log.Info("Overriding OpenAPI servers with configured server", slog.String("server", config.Server))
+		doc.Servers = openapi3.Servers{{URL: config.Server}}
+		for _, pathItem := range doc.Paths.Map() {
+			pathItem.Servers = nil
+			for _, operation := range pathItem.Operations() {
+				operation.Servers = nil
+			}
+		}
+	}
+
+	if len(config.RequestContentTypes) > 0 {
+		preferRequestContentTypes(doc, config.RequestContentTypes)
+	}
+
 	log.Info("Successfully fetched and parsed OpenAPI schema")
 	return domain.APISchema{
 		Source:     config.URL,
@@ -209,3 +273,88 @@ func (f *SchemaFetcher) FetchWithConfig(ctx context.Context, config usecase.Sche
 		ParsedData: doc,
 	}, nil
 }
+
+// FetchAll discovers every OpenAPI schema at config.URL (e.g., /v1/openapi.json and
+// /v2/openapi.json) and fetches each one. The returned schemas have their spec URL
+// as Source.
+func (f *SchemaFetcher) FetchAll(ctx context.Context, config usecase.SchemaSourceConfig) ([]domain.APISchema, error) {
+	log := f.logger.With(slog.String("source", config.URL))
+
+	_, discoverer, err := f.clientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS settings for %s: %w", config.URL, err)
+	}
+	specURLs, err := discoverer.DiscoverAllSchemas(ctx, config.URL, config.Headers)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Discovered OpenAPI schemas", slog.Any("urls", specURLs))
+
+	schemas := make([]domain.APISchema, 0, len(specURLs))
+	for _, specURL := range specURLs {
+		specConfig := config
+		specConfig.URL = specURL
+		schema, err := f.FetchWithConfig(ctx, specConfig)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, nil
+}
+
+// openSpec reads the document in the file at path; see readSpec. A streamed file is
+// closed once the document is loaded.
+func (f *SchemaFetcher) openSpec(path string) (spec, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return spec{}, err
+	}
+	size := int64(-1)
+	if info, err := file.Stat(); err == nil && info.Mode().IsRegular() {
+		size = info.Size()
+	}
+	s, err := readSpec(file, size, f.streamThreshold)
+	if err != nil || s.stream == nil {
+		file.Close()
+		return s, err
+	}
+	s.closer = file
+	return s, nil
+}
+
+// clientFor returns the HTTP client and auto-discoverer to fetch config's schema
+// with: the fetcher's own, unless the source has TLS settings.
+func (f *SchemaFetcher) clientFor(config usecase.SchemaSourceConfig) (*http.Client, *AutoDiscoverer, error) {
+	opts := tlsclient.Options{CAFile: config.TLSCAFile, InsecureSkipVerify: config.TLSInsecureSkipVerify}
+	if opts.Empty() {
+		return f.httpClient, f.autoDiscoverer, nil
+	}
+	client, err := f.tlsClients.Client(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	discoverer := *f.autoDiscoverer
+	discoverer.client = client
+	return client, &discoverer, nil
+}
+
+// preferRequestContentTypes narrows every request body declaring several media types
+// to the first of preferred it declares, so that tools are generated for that one.
+// Bodies declaring none of them are left as they are.
+func preferRequestContentTypes(doc *openapi3.T, preferred []string) {
+	for _, pathItem := range doc.Paths.Map() {
+		for _, operation := range pathItem.Operations() {
+			if operation.RequestBody == nil || operation.RequestBody.Value == nil || len(operation.RequestBody.Value.Content) < 2 {
+				continue
+			}
+			content := operation.RequestBody.Value.Content
+			for _, mediaType := range preferred {
+				if mt := content.Get(mediaType); mt != nil {
+					operation.RequestBody.Value.Content = openapi3.Content{mediaType: mt}
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package openapi_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/openapi"
+	"github.com/i2y/mcpizer/internal/domain"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+func TestSchemaFetcher_FetchWithConfig_TLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write([]byte(`
+openapi: 3.0.0
+info:
+  title: Internal
+  version: 1.0.0
+paths: {}
+`))
+	}))
+	defer srv.Close()
+
+	// The test server's certificate is self-signed, so it serves as its own CA.
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0o600))
+
+	tests := []struct {
+		name     string
+		config   usecase.SchemaSourceConfig
+		wantErr  bool
+		errMatch string
+	}{
+		{
+			name:     "untrusted certificate",
+			config:   usecase.SchemaSourceConfig{URL: srv.URL + "/openapi.yaml"},
+			wantErr:  true,
+			errMatch: "certificate",
+		},
+		{
+			name:   "custom CA",
+			config: usecase.SchemaSourceConfig{URL: srv.URL + "/openapi.yaml", TLSCAFile: caFile},
+		},
+		{
+			name:   "insecure skip verify",
+			config: usecase.SchemaSourceConfig{URL: srv.URL + "/openapi.yaml", TLSInsecureSkipVerify: true},
+		},
+		{
+			name:     "missing CA file",
+			config:   usecase.SchemaSourceConfig{URL: srv.URL + "/openapi.yaml", TLSCAFile: filepath.Join(t.TempDir(), "missing.pem")},
+			wantErr:  true,
+			errMatch: "invalid TLS settings",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := openapi.NewSchemaFetcher(&http.Client{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			schema, err := fetcher.FetchWithConfig(context.Background(), tt.config)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMatch)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.config.URL, schema.Source)
+			assert.NotNil(t, schema.ParsedData)
+		})
+	}
+}
+
+func TestSchemaFetcher_FetchWithConfig_RequestContentTypes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write([]byte(`
+openapi: 3.0.0
+info:
+  title: Tokens
+  version: 1.0.0
+servers:
+  - url: https://auth.example.com
+paths:
+  /token:
+    post:
+      operationId: createToken
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                credentials:
+                  type: object
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [grant_type]
+              properties:
+                grant_type:
+                  type: string
+                scope:
+                  type: string
+      responses:
+        "200":
+          description: ok
+`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name            string
+		contentTypes    []string
+		wantContentType string
+		wantProperties  []string
+	}{
+		{
+			name:            "JSON by default",
+			wantContentType: "application/json",
+			wantProperties:  []string{"credentials"},
+		},
+		{
+			name:            "configured form",
+			contentTypes:    []string{"multipart/form-data", "application/x-www-form-urlencoded", "application/json"},
+			wantContentType: "application/x-www-form-urlencoded",
+			wantProperties:  []string{"grant_type", "scope"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			schema, err := openapi.NewSchemaFetcher(srv.Client(), logger).FetchWithConfig(context.Background(), usecase.SchemaSourceConfig{
+				URL:                 srv.URL + "/openapi.yaml",
+				RequestContentTypes: tt.contentTypes,
+			})
+			require.NoError(t, err)
+
+			tools, details, err := openapi.NewToolGenerator(logger).Generate(schema)
+			require.NoError(t, err)
+			require.Len(t, tools, 1)
+			require.Len(t, details, 1)
+
+			assert.Equal(t, tt.wantContentType, details[0].ContentType)
+			assert.Empty(t, details[0].BodyParam)
+			var properties []string
+			for name := range tools[0].InputSchema.Properties {
+				properties = append(properties, name)
+			}
+			assert.ElementsMatch(t, tt.wantProperties, properties)
+			if tt.wantContentType == "application/x-www-form-urlencoded" {
+				assert.Equal(t, domain.JSONSchemaProps{Type: "string"}, tools[0].InputSchema.Properties["scope"])
+				assert.Equal(t, []string{"grant_type"}, tools[0].InputSchema.Required)
+			}
+		})
+	}
+}
+
+func TestSchemaFetcher_Fetch_MislabeledContentType(t *testing.T) {
+	const jsonSpec = `{"openapi":"3.0.0","info":{"title":"Notes","version":"1.0.0"},` +
+		`"paths":{"/notes":{"get":{"operationId":"listNotes","responses":{"200":{"description":"ok"}}}}}}`
+	const htmlPage = "<!DOCTYPE html><html><body>Not found</body></html>"
+
+	tests := []struct {
+		name    string
+		spec    string // served as text/html at /openapi.json
+		wantErr string
+	}{
+		{name: "JSON spec served as HTML", spec: jsonSpec},
+		{name: "YAML spec served as HTML", spec: "# Notes API\nopenapi: 3.0.0\ninfo:\n  title: Notes\n  version: 1.0.0\npaths: {}\n"},
+		{name: "YAML spec with quoted keys", spec: "\"openapi\": 3.0.0\n\"info\":\n  \"title\": Notes\n  \"version\": 1.0.0\n\"paths\": {}\n"},
+		{name: "YAML spec with single-quoted keys", spec: "'swagger' : '2.0'\ninfo:\n  title: Notes\n  version: 1.0.0\npaths: {}\n"},
+		{name: "HTML page", spec: htmlPage, wantErr: "does not look like an OpenAPI document"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				switch r.URL.Path {
+				case "/openapi.json":
+					_, _ = w.Write([]byte(tt.spec))
+				case "/":
+					_, _ = w.Write([]byte(htmlPage))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			fetcher := openapi.NewSchemaFetcher(srv.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+			fetches := map[string]func() (domain.APISchema, error){
+				// The base URL is resolved by discovery, whose probe sniffs the body too.
+				"Fetch": func() (domain.APISchema, error) {
+					return fetcher.Fetch(context.Background(), srv.URL)
+				},
+				"FetchWithConfig": func() (domain.APISchema, error) {
+					return fetcher.FetchWithConfig(context.Background(), usecase.SchemaSourceConfig{URL: srv.URL})
+				},
+			}
+			for name, fetch := range fetches {
+				schema, err := fetch()
+				if tt.wantErr != "" {
+					require.Error(t, err, name)
+					assert.Contains(t, err.Error(), tt.wantErr, name)
+					continue
+				}
+				require.NoError(t, err, name)
+				assert.Equal(t, []byte(tt.spec), schema.RawData, name)
+			}
+		})
+	}
+}
+
+func TestSchemaFetcher_Fetch_LocatesBrokenOperation(t *testing.T) {
+	const spec = `openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewPet'
+      responses:
+        "201":
+          description: created
+  /pets/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Pet:
+      type: object
+`
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(spec), 0o600))
+
+	fetcher := openapi.NewSchemaFetcher(http.DefaultClient, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	_, err := fetcher.Fetch(context.Background(), path)
+	require.Error(t, err)
+	// Only the operation with the dangling $ref is named, at the line declaring it.
+	assert.Contains(t, err.Error(), "POST /pets (line 11): ")
+	assert.NotContains(t, err.Error(), "GET /pets")
+	assert.Contains(t, err.Error(), "NewPet")
+}
+
+func TestSchemaFetcher_Fetch_LocatesManyBrokenOperations(t *testing.T) {
+	tests := []struct {
+		name       string
+		broken     map[int]bool // indexes of the operations with a dangling $ref
+		wantNamed  []string
+		wantNumber int
+	}{
+		{
+			name:       "two among many",
+			broken:     map[int]bool{3: true, 196: true},
+			wantNamed:  []string{"POST /items3", "POST /items196"},
+			wantNumber: 2,
+		},
+		{
+			name: "more than are named",
+			broken: func() map[int]bool {
+				m := map[int]bool{}
+				for i := 0; i < 15; i++ {
+					m[i*10] = true
+				}
+				return m
+			}(),
+			wantNamed:  []string{"POST /items0", "POST /items90"},
+			wantNumber: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			b.WriteString("openapi: 3.0.0\ninfo:\n  title: Items\n  version: 1.0.0\npaths:\n")
+			for i := 0; i < 200; i++ {
+				schema := "type: object"
+				if tt.broken[i] {
+					schema = "$ref: '#/components/schemas/Missing'"
+				}
+				fmt.Fprintf(&b, "  /items%d:\n    get:\n      responses:\n        \"200\":\n          description: ok\n", i)
+				fmt.Fprintf(&b, "    post:\n      requestBody:\n        content:\n          application/json:\n            schema:\n              %s\n", schema)
+				b.WriteString("      responses:\n        \"201\":\n          description: created\n")
+			}
+			path := filepath.Join(t.TempDir(), "openapi.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(b.String()), 0o600))
+
+			fetcher := openapi.NewSchemaFetcher(http.DefaultClient, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			_, err := fetcher.Fetch(context.Background(), path)
+			require.Error(t, err)
+			for _, named := range tt.wantNamed {
+				assert.Contains(t, err.Error(), named+" (line ")
+			}
+			assert.Equal(t, tt.wantNumber, strings.Count(err.Error(), "(line "))
+			assert.NotContains(t, err.Error(), "GET /items")
+		})
+	}
+}
+
+func TestSchemaFetcher_Fetch_StreamsLargeSpec(t *testing.T) {
+	// Enough operations, padded with long descriptions, to pass DefaultStreamThreshold.
+	var b strings.Builder
+	b.WriteString(` {"openapi":"3.0.0","info":{"title":"Big","version":"1.0.0"},"paths":{`)
+	description := strings.Repeat("A long operation description. ", 50)
+	const operations = 6000
+	for i := 0; i < operations; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `"/items/%d":{"get":{"operationId":"getItem%d","description":%q,`+
+			`"responses":{"200":{"description":"ok","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Item"}}}}}}}`,
+			i, i, description)
+	}
+	b.WriteString(`},"components":{"schemas":{"Item":{"type":"object","properties":{"id":{"type":"string"}}}}}}`)
+	spec := b.String()
+	require.Greater(t, int64(len(spec)), int64(openapi.DefaultStreamThreshold))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/openapi.json.gz" {
+			// The client decompresses the body, so its size is unknown.
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			_, _ = io.WriteString(gz, spec)
+			_ = gz.Close()
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(spec)))
+		_, _ = io.WriteString(w, spec)
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	require.NoError(t, os.WriteFile(path, []byte(spec), 0o600))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tests := []struct {
+		name        string
+		opts        []openapi.SchemaFetcherOption
+		source      string
+		wantRawData bool
+	}{
+		{name: "URL", source: srv.URL + "/openapi.json"},
+		{name: "gzipped URL", source: srv.URL + "/openapi.json.gz"},
+		{name: "file", source: path},
+		{name: "streaming disabled", opts: []openapi.SchemaFetcherOption{openapi.WithStreamThreshold(0)}, source: path, wantRawData: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := openapi.NewSchemaFetcher(srv.Client(), logger, tt.opts...)
+			schema, err := fetcher.FetchWithConfig(context.Background(), usecase.SchemaSourceConfig{URL: tt.source})
+			require.NoError(t, err)
+			// Streamed documents keep no copy of their bytes.
+			assert.Equal(t, tt.wantRawData, schema.RawData != nil)
+
+			doc, ok := schema.ParsedData.(*openapi3.T)
+			require.True(t, ok)
+			assert.Equal(t, operations, doc.Paths.Len())
+			item := doc.Paths.Find("/items/42")
+			require.NotNil(t, item)
+			require.NotNil(t, item.Get)
+			response := item.Get.Responses.Status(200)
+			require.NotNil(t, response)
+			schemaRef := response.Value.Content.Get("application/json").Schema
+			require.NotNil(t, schemaRef.Value, "$ref is resolved")
+			assert.Contains(t, schemaRef.Value.Properties, "id")
+		})
+	}
+}
+
+func TestSchemaFetcher_Fetch_StreamedNonOpenAPIDocument(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.(http.Flusher).Flush() // no Content-Length
+		_, _ = io.WriteString(w, `{"status":"ok","paths":{}}`)
+	}))
+	defer srv.Close()
+
+	fetcher := openapi.NewSchemaFetcher(srv.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	_, err := fetcher.Fetch(context.Background(), srv.URL+"/health.json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no openapi or swagger field")
+}
@@ -1,9 +1,14 @@
 package openapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/i2y/mcpizer/internal/domain"
@@ -15,60 +20,208 @@ import (
 
 // ToolGenerator implements the usecase.ToolGenerator interface for OpenAPI schemas.
 type ToolGenerator struct {
-	logger *slog.Logger
+	logger             *slog.Logger
+	defaultHost        string
+	bodyParamName      string
+	textOutputFallback bool
+	endpointInDesc     bool
+	preserveNameCase   bool
+	undeclaredPathVars bool
+	namespace          string // replaces the title-derived namespace; NoNamespace drops it
+}
+
+// DefaultBodyParamName is the tool parameter that carries a non-object request body.
+const DefaultBodyParamName = "body"
+
+// NoNamespace, given to WithNamespace, leaves tool names without a namespace.
+const NoNamespace = "none"
+
+// GeneratorOption configures optional ToolGenerator behavior.
+type GeneratorOption func(*ToolGenerator)
+
+// WithDefaultHost sets the base URL (e.g., "https://api.example.com/v1") used when a
+// document has no usable HTTP/HTTPS entry in its servers block.
+func WithDefaultHost(host string) GeneratorOption {
+	return func(g *ToolGenerator) {
+		g.defaultHost = host
+	}
+}
+
+// WithBodyParamName sets the tool parameter that carries a request body which is
+// not a JSON object (e.g., a string or an array). Empty keeps DefaultBodyParamName.
+func WithBodyParamName(name string) GeneratorOption {
+	return func(g *ToolGenerator) {
+		if name != "" {
+			g.bodyParamName = name
+		}
+	}
+}
+
+// WithEndpointInDescription appends the operation's method and path, e.g.
+// " (GET /users/{id})", to every tool description.
+func WithEndpointInDescription(enabled bool) GeneratorOption {
+	return func(g *ToolGenerator) {
+		g.endpointInDesc = enabled
+	}
+}
+
+// WithPreserveNameCase keeps the letter case of titles, operationIds, and path
+// segments in tool names (e.g., "getUserById" instead of "getuserbyid"). Separators
+// are still replaced with underscores.
+func WithPreserveNameCase(enabled bool) GeneratorOption {
+	return func(g *ToolGenerator) {
+		g.preserveNameCase = enabled
+	}
+}
+
+// WithUndeclaredPathParams turns every {name} placeholder of an operation's path that
+// no path parameter declares, as in some malformed specs, into a required string
+// parameter substituted into the path.
+func WithUndeclaredPathParams(enabled bool) GeneratorOption {
+	return func(g *ToolGenerator) {
+		g.undeclaredPathVars = enabled
+	}
+}
+
+// WithTextOutputFallback makes operations without a JSON success response advertise
+// a string output schema, describing the response media type, instead of none.
+// Without a 2xx response the "default" response is used.
+func WithTextOutputFallback(enabled bool) GeneratorOption {
+	return func(g *ToolGenerator) {
+		g.textOutputFallback = enabled
+	}
+}
+
+// WithNamespace sets the namespace that prefixes every tool name (e.g., "acme" in
+// "acme_getuser") instead of the one derived from each document's title, so that the
+// tools of all sources share it. NoNamespace drops the prefix ("getuser"); tool names
+// of different sources may then collide. Empty keeps the title-derived namespace.
+func WithNamespace(namespace string) GeneratorOption {
+	return func(g *ToolGenerator) {
+		g.namespace = namespace
+	}
 }
 
 // NewToolGenerator creates a new OpenAPI ToolGenerator.
-func NewToolGenerator(logger *slog.Logger) *ToolGenerator {
-	return &ToolGenerator{
-		logger: logger.With("component", "openapi_generator"),
+func NewToolGenerator(logger *slog.Logger, opts ...GeneratorOption) *ToolGenerator {
+	g := &ToolGenerator{
+		logger:        logger.With("component", "openapi_generator"),
+		bodyParamName: DefaultBodyParamName,
 	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
 }
 
 // Generate converts an OpenAPI document into MCP Tools and corresponding InvocationDetails.
 func (g *ToolGenerator) Generate(schema domain.APISchema) ([]domain.Tool, []usecase.InvocationDetails, error) {
+	tools, detailsList, _, err := g.GenerateWithReport(schema)
+	return tools, detailsList, err
+}
+
+// GenerateWithReport is Generate, also returning the operations skipped because no
+// tool could be generated for them, with their lines in the schema's raw document.
+func (g *ToolGenerator) GenerateWithReport(schema domain.APISchema) ([]domain.Tool, []usecase.InvocationDetails, []usecase.SkippedOperation, error) {
 	log := g.logger.With(slog.String("source", schema.Source))
 	log.Info("Generating tools from OpenAPI schema.")
 
 	doc, ok := schema.ParsedData.(*openapi3.T)
 	if !ok || doc == nil {
 		log.Error("Invalid or missing parsed OpenAPI document in APISchema.")
-		return nil, nil, fmt.Errorf("invalid or missing parsed OpenAPI document in APISchema")
+		return nil, nil, nil, fmt.Errorf("invalid or missing parsed OpenAPI document in APISchema")
 	}
 
 	// Determine base host URL and base path from the schema's Servers block.
 	// Pass schema.Source to resolve relative server URLs.
-	host, basePath, err := g.determineHostAndBasePathFromServers(schema.Source, doc.Servers)
-	if err != nil {
-		// If no suitable server URL found, log warning and potentially return error or continue without host.
-		log.Error("Failed to determine host/basePath from OpenAPI servers block.", slog.Any("error", err))
-		// Return error as host is crucial for invocation details.
-		return nil, nil, fmt.Errorf("could not determine host/basePath from OpenAPI servers: %w", err)
+	hosts, basePath, err := g.determineHostsAndBasePathFromServers(schema.Source, doc.Servers)
+	var host string
+	if err == nil {
+		host = hosts[0]
+	} else {
+		if g.defaultHost == "" {
+			log.Error("Failed to determine host/basePath from OpenAPI servers block.", slog.Any("error", err))
+			// Return error as host is crucial for invocation details.
+			return nil, nil, nil, fmt.Errorf("could not determine host/basePath for OpenAPI document %s: %w; "+
+				"set MCPIZER_OPENAPI_DEFAULT_HOST or the source's \"server\" field to the API base URL", schema.Source, err)
+		}
+		host, basePath, err = splitBaseURL(g.defaultHost)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid default host %q: %w", g.defaultHost, err)
+		}
+		log.Warn("No usable server in OpenAPI document, using default host.", slog.String("default_host", g.defaultHost))
 	}
 	log.Info("Determined host and basePath for generation.", slog.String("host", host), slog.String("basePath", basePath))
 
 	var tools []domain.Tool
 	var detailsList []usecase.InvocationDetails
-	// Determine namespace (consider making configurable).
-	namespace := sanitizeName(doc.Info.Title)
-	if namespace == "" {
-		namespace = "openapi"
+	// Determine namespace, unless one is configured.
+	var namespace string
+	switch g.namespace {
+	case "":
+		namespace = g.sanitizeName(doc.Info.Title)
+		if namespace == "" {
+			namespace = "openapi"
+		}
+	case NoNamespace:
+	default:
+		namespace = g.sanitizeName(g.namespace)
 	}
 	log = log.With(slog.String("namespace", namespace))
 
+	// operationIds must be unique, but specs in the wild sometimes reuse them.
+	// Count them up front so every colliding operation gets a disambiguated name.
+	operationIDCounts := countOperationIDs(doc)
+	// Names are known up front so that response links can refer to the tools they suggest.
+	toolNames := g.operationToolNames(namespace, doc, operationIDCounts)
+
+	// Webhooks (OpenAPI 3.1) are requests the API sends, not operations it serves.
+	if _, ok := doc.Extensions["webhooks"]; ok {
+		log.Info("Skipping webhooks in OpenAPI document; they are not invocable operations.")
+	}
+
 	// Iterate through paths and operations to create tools.
 	generatedCount := 0
 	skippedCount := 0
+	var skipped []usecase.SkippedOperation
+	var lines map[string]int // operation lines, located once something is skipped
+	skip := func(path, method string, err error) {
+		if lines == nil {
+			lines = operationLines(schema.RawData)
+		}
+		operation := method + " " + path
+		skipped = append(skipped, usecase.SkippedOperation{Operation: operation, Line: lines[operation], Reason: err.Error()})
+		skippedCount++
+	}
 	for path, pathItem := range doc.Paths.Map() {
 		if pathItem == nil {
 			continue
 		}
+		if isCallbackPath(path) {
+			log.Warn("Skipping path with a runtime expression; it describes a callback, not an invocable operation.",
+				slog.String("path", path))
+			skippedCount += len(pathItem.Operations())
+			continue
+		}
 		for method, operation := range pathItem.Operations() {
 			if operation == nil {
 				continue
 			}
+			if len(operation.Callbacks) > 0 {
+				log.Debug("Ignoring callbacks declared on operation.",
+					slog.String("path", path),
+					slog.String("method", method),
+					slog.Int("callback_count", len(operation.Callbacks)))
+			}
 
-			toolName := generateToolName(namespace, path, method, operation)
+			toolName := toolNames[operation]
+			if operationIDCounts[operation.OperationID] > 1 {
+				log.Warn("Duplicate operationId in OpenAPI document, disambiguating tool name with method and path.",
+					slog.String("operation_id", operation.OperationID),
+					slog.String("path", path),
+					slog.String("method", method),
+					slog.String("tool_name", toolName))
+			}
 			log := log.With(slog.String("path", path), slog.String("method", method), slog.String("tool_name", toolName))
 
 			description := operation.Description
@@ -77,39 +230,70 @@ func (g *ToolGenerator) Generate(schema domain.APISchema) ([]domain.Tool, []usec
 			}
 			if description == "" {
 				description = fmt.Sprintf("Executes %s %s", method, path) // Fallback description
+			} else if g.endpointInDesc {
+				description = fmt.Sprintf("%s (%s %s)", strings.TrimSpace(description), method, path)
+			}
+
+			// Parameters declared for the whole path apply to each of its operations.
+			op := operation
+			if len(pathItem.Parameters) > 0 {
+				withPathParams := *operation
+				withPathParams.Parameters = operationParameters(pathItem, operation)
+				op = &withPathParams
 			}
 
-			inputSchema, err := g.generateInputSchema(log, operation.Parameters, operation.RequestBody)
+			bodyParam := g.bodyParamFor(op)
+			inputSchema, err := g.generateInputSchema(log, op.Parameters, op.RequestBody, bodyParam)
 			if err != nil {
 				log.Warn("Warning: skipping tool due to input schema generation error.", slog.Any("error", err))
-				skippedCount++
+				skip(path, method, err)
 				continue
 			}
 
-			outputSchema, err := g.generateOutputSchema(log, operation.Responses)
+			var undeclared []string
+			if g.undeclaredPathVars {
+				undeclared = undeclaredPathParams(path, op.Parameters)
+				if len(undeclared) > 0 {
+					log.Warn("Path has placeholders without a declared path parameter, adding them as string parameters.",
+						slog.Any("params", undeclared))
+					addUndeclaredPathParams(inputSchema, undeclared)
+				}
+			}
+
+			outputSchema, err := g.generateOutputSchema(log, operation.Responses, schema.ResponseStatuses)
 			if err != nil {
 				log.Warn("Warning: skipping tool due to output schema generation error.", slog.Any("error", err))
-				skippedCount++
+				skip(path, method, err)
 				continue
 			}
 
 			tool := domain.Tool{
 				Name:         toolName,
+				Title:        generateToolTitle(path, method, operation),
 				Description:  description,
 				InputSchema:  *inputSchema,
 				OutputSchema: outputSchema, // Might be nil
+				Annotations:  annotationsForMethod(method),
+				Links:        operationLinks(log, doc, operation, toolNames, operationIDCounts),
 			}
 			tools = append(tools, tool)
 
 			// Generate InvocationDetails (passes the determined host and basePath)
-			details, err := g.generateInvocationDetails(log, host, basePath, path, method, operation)
+			opHosts, opBasePath := g.operationHosts(log, schema.Source, pathItem, operation, hosts, host, basePath)
+			details, err := g.generateInvocationDetails(log, opHosts[0], opBasePath, path, method, op, bodyParam)
+			if err == nil && len(opHosts) > 1 {
+				details.Hosts = opHosts
+			}
+			if err == nil && len(undeclared) > 0 {
+				details.PathParams = append(details.PathParams, undeclared...)
+			}
 			if err != nil {
 				log.Warn("Warning: skipping tool due to invocation details generation error.", slog.Any("error", err))
 				// Remove the tool we just added if details generation failed?
 				if len(tools) > 0 {
 					tools = tools[:len(tools)-1]
 				}
-				skippedCount++
+				skip(path, method, err)
 				continue
 			}
 			detailsList = append(detailsList, *details)
@@ -118,20 +302,22 @@ func (g *ToolGenerator) Generate(schema domain.APISchema) ([]domain.Tool, []usec
 		}
 	}
 
+	dropDanglingLinks(tools)
+
 	log.Info("Finished generating tools from OpenAPI schema.",
 		slog.Int("generated_count", generatedCount),
 		slog.Int("skipped_count", skippedCount))
-	return tools, detailsList, nil
+	return tools, detailsList, skipped, nil
 }
 
-// determineHostAndBasePathFromServers tries to find a suitable base URL from the Servers array.
+// determineHostsAndBasePathFromServers tries to find a suitable base URL from the Servers array.
 // It prioritizes HTTP/HTTPS URLs. If a relative URL is found, it resolves it
-// against the schemaSourceURL. Returns the first valid one found (scheme://host, basePath, error).
+// against the schemaSourceURL. Returns the hosts (scheme://host) of the valid ones
+// sharing the first one's base path, in document order, and that base path.
 // BasePath will be empty if the resolved URL has no path component.
-func (g *ToolGenerator) determineHostAndBasePathFromServers(schemaSourceURL string, servers openapi3.Servers) (string, string, error) {
+func (g *ToolGenerator) determineHostsAndBasePathFromServers(schemaSourceURL string, servers openapi3.Servers) ([]string, string, error) {
 	if len(servers) == 0 {
-		// Fallback or configuration could be added here if desired
-		return "", "", fmt.Errorf("no servers defined in OpenAPI document")
+		return nil, "", fmt.Errorf("no servers defined in OpenAPI document")
 	}
 
 	baseSourceURL, err := url.Parse(schemaSourceURL)
@@ -141,12 +327,17 @@ func (g *ToolGenerator) determineHostAndBasePathFromServers(schemaSourceURL stri
 		baseSourceURL = nil // Ensure we don't accidentally use a broken base URL
 	}
 
+	var hosts []string
+	var basePath string
 	for _, server := range servers {
 		if server == nil || server.URL == "" {
 			continue
 		}
-		// TODO: Add support for server variables substitution if needed.
-		serverURL := server.URL
+		serverURL, ok := substituteServerVariables(server)
+		if !ok {
+			g.logger.Warn("Server URL has variables without a default value, skipping.", slog.String("url", server.URL))
+			continue // Try next server
+		}
 
 		parsedServerURL, err := url.Parse(serverURL)
 		if err != nil {
@@ -174,14 +365,19 @@ func (g *ToolGenerator) determineHostAndBasePathFromServers(schemaSourceURL stri
 
 		// Check if the (potentially resolved) URL is suitable
 		if (resolvedURL.Scheme == "http" || resolvedURL.Scheme == "https") && resolvedURL.Host != "" {
-			// Found a suitable HTTP/HTTPS URL.
-			host := fmt.Sprintf("%s://%s", resolvedURL.Scheme, resolvedURL.Host)
-			basePath := resolvedURL.Path
-			// Clean the base path (remove trailing slash unless it's just "/")
-			if len(basePath) > 1 && strings.HasSuffix(basePath, "/") {
-				basePath = basePath[:len(basePath)-1]
+			// Found a suitable HTTP/HTTPS URL. The first one is used; later ones serving
+			// the same base path are kept as fallbacks for when it is unreachable.
+			host, path := hostAndBasePath(resolvedURL)
+			if len(hosts) == 0 {
+				hosts, basePath = []string{host}, path
+			} else if path == basePath {
+				if !slices.Contains(hosts, host) {
+					hosts = append(hosts, host)
+				}
+			} else {
+				g.logger.Debug("Skipping server with a different base path as fallback.", slog.String("url", serverURL))
 			}
-			return host, basePath, nil
+			continue
 		}
 
 		// Log if an absolute URL was found but wasn't http/https
@@ -190,30 +386,346 @@ func (g *ToolGenerator) determineHostAndBasePathFromServers(schemaSourceURL stri
 		}
 	}
 
-	return "", "", fmt.Errorf("no suitable HTTP/HTTPS server URL found or resolvable in OpenAPI document")
+	if len(hosts) > 0 {
+		return hosts, basePath, nil
+	}
+	return nil, "", fmt.Errorf("no suitable HTTP/HTTPS server URL found or resolvable in OpenAPI document")
+}
+
+// operationHosts returns the hosts and base path serving an operation: those of the
+// operation's own servers, else of its path item's, else docHosts (or, when the
+// document has no usable server, defaultHost) and docBasePath.
+func (g *ToolGenerator) operationHosts(log *slog.Logger, source string, pathItem *openapi3.PathItem, operation *openapi3.Operation, docHosts []string, defaultHost, docBasePath string) ([]string, string) {
+	var operationServers openapi3.Servers
+	if operation.Servers != nil {
+		operationServers = *operation.Servers
+	}
+	for _, servers := range []openapi3.Servers{operationServers, pathItem.Servers} {
+		if len(servers) == 0 {
+			continue
+		}
+		hosts, basePath, err := g.determineHostsAndBasePathFromServers(source, servers)
+		if err == nil {
+			return hosts, basePath
+		}
+		log.Warn("No usable server in path or operation servers override, trying the next level.", slog.Any("error", err))
+	}
+	if len(docHosts) == 0 {
+		return []string{defaultHost}, docBasePath
+	}
+	return docHosts, docBasePath
+}
+
+// serverVariablePattern matches a {variable} in a server URL template.
+var serverVariablePattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// substituteServerVariables resolves the {variable} placeholders of a server URL,
+// in the host as well as the path (e.g. "/api/{version}"), with their default
+// values. It reports false if a placeholder has no declared default.
+func substituteServerVariables(server *openapi3.Server) (string, bool) {
+	resolved := true
+	serverURL := serverVariablePattern.ReplaceAllStringFunc(server.URL, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		if variable, ok := server.Variables[name]; ok && variable != nil && variable.Default != "" {
+			return variable.Default
+		}
+		resolved = false
+		return placeholder
+	})
+	return serverURL, resolved
+}
+
+// hostAndBasePath splits an absolute URL into "scheme://host" and its path without a trailing slash.
+func hostAndBasePath(u *url.URL) (string, string) {
+	host := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+	basePath := u.Path
+	// Clean the base path (remove trailing slash unless it's just "/")
+	if len(basePath) > 1 && strings.HasSuffix(basePath, "/") {
+		basePath = basePath[:len(basePath)-1]
+	}
+	return host, basePath
+}
+
+// splitBaseURL parses a configured base URL into host and base path.
+func splitBaseURL(baseURL string) (string, string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", fmt.Errorf("must be an absolute http:// or https:// URL")
+	}
+	host, basePath := hostAndBasePath(u)
+	return host, basePath, nil
+}
+
+// operationParameters returns the parameters declared for pathItem's whole path
+// followed by operation's own. An operation parameter overrides a path parameter
+// of the same name and location.
+func operationParameters(pathItem *openapi3.PathItem, operation *openapi3.Operation) openapi3.Parameters {
+	params := make(openapi3.Parameters, 0, len(pathItem.Parameters)+len(operation.Parameters))
+	for _, paramRef := range pathItem.Parameters {
+		if paramRef == nil || paramRef.Value == nil {
+			continue
+		}
+		if operation.Parameters.GetByInAndName(paramRef.Value.In, paramRef.Value.Name) != nil {
+			continue
+		}
+		params = append(params, paramRef)
+	}
+	return append(params, operation.Parameters...)
+}
+
+// undeclaredPathParams returns the names of path's {name} placeholders that no path
+// parameter in params declares, in order of appearance.
+func undeclaredPathParams(path string, params openapi3.Parameters) []string {
+	declared := make(map[string]bool)
+	for _, paramRef := range params {
+		if paramRef != nil && paramRef.Value != nil && paramRef.Value.In == openapi3.ParameterInPath {
+			declared[paramRef.Value.Name] = true
+		}
+	}
+	var names []string
+	for _, match := range serverVariablePattern.FindAllStringSubmatch(path, -1) {
+		if name := match[1]; !declared[name] && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// addUndeclaredPathParams adds the undeclared path parameter names to schema as
+// required strings, keeping any property of the same name.
+func addUndeclaredPathParams(schema *domain.JSONSchemaProps, names []string) {
+	if schema.Properties == nil {
+		schema.Properties = make(map[string]domain.JSONSchemaProps)
+	}
+	for _, name := range names {
+		if _, exists := schema.Properties[name]; !exists {
+			schema.Properties[name] = domain.JSONSchemaProps{Type: "string", Description: "Path parameter " + name}
+		}
+		if !slices.Contains(schema.Required, name) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// isCallbackPath reports whether path is a callback URL expression such as
+// "{$request.body#/callbackUrl}" rather than a path served by the API.
+func isCallbackPath(path string) bool {
+	return strings.Contains(path, "{$")
 }
 
 // generateToolName creates a unique and descriptive name for the tool.
-// Example strategy: {namespace}-{operationId} or {namespace}-{method}-{path parts}
-func generateToolName(namespace, path, method string, op *openapi3.Operation) string {
+// Example strategy: {namespace}-{operationId} or {namespace}-{method}-{path parts}.
+// An empty namespace is left out.
+func (g *ToolGenerator) generateToolName(namespace, path, method string, op *openapi3.Operation) string {
 	if op.OperationID != "" {
-		return fmt.Sprintf("%s_%s", namespace, sanitizeName(op.OperationID))
+		if namespace == "" {
+			return g.sanitizeName(op.OperationID)
+		}
+		return fmt.Sprintf("%s_%s", namespace, g.sanitizeName(op.OperationID))
 	}
 
 	// Fallback: use method and path
 	pathParts := strings.Split(strings.Trim(path, "/"), "/")
 	var nameParts []string
-	nameParts = append(nameParts, namespace, strings.ToLower(method))
+	if namespace != "" {
+		nameParts = append(nameParts, namespace)
+	}
+	nameParts = append(nameParts, strings.ToLower(method))
 	for _, part := range pathParts {
 		if !strings.HasPrefix(part, "{") && !strings.HasSuffix(part, "}") {
-			nameParts = append(nameParts, sanitizeName(part))
+			nameParts = append(nameParts, g.sanitizeName(part))
 		}
 	}
 	return strings.Join(nameParts, "_")
 }
 
+// operationToolNames returns the tool name of every operation in doc, disambiguating
+// operations that share an operationId.
+func (g *ToolGenerator) operationToolNames(namespace string, doc *openapi3.T, operationIDCounts map[string]int) map[*openapi3.Operation]string {
+	names := make(map[*openapi3.Operation]string)
+	for path, pathItem := range doc.Paths.Map() {
+		if pathItem == nil || isCallbackPath(path) {
+			continue
+		}
+		for method, operation := range pathItem.Operations() {
+			if operation == nil {
+				continue
+			}
+			name := g.generateToolName(namespace, path, method, operation)
+			if operationIDCounts[operation.OperationID] > 1 {
+				name = g.disambiguateToolName(name, path, method)
+			}
+			names[operation] = name
+		}
+	}
+	return names
+}
+
+// operationLinks returns the links declared on operation's success responses, naming
+// the tools of the operations they point to. Links to unknown operations, ambiguous
+// operationIds, or other documents are left out.
+func operationLinks(log *slog.Logger, doc *openapi3.T, operation *openapi3.Operation, toolNames map[*openapi3.Operation]string, operationIDCounts map[string]int) []domain.ToolLink {
+	if operation.Responses == nil {
+		return nil
+	}
+	var links []domain.ToolLink
+	seen := make(map[string]bool)
+	codes := make([]string, 0, operation.Responses.Len())
+	for code := range operation.Responses.Map() {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		response := operation.Responses.Value(code)
+		if response == nil || response.Value == nil {
+			continue
+		}
+		names := make([]string, 0, len(response.Value.Links))
+		for name := range response.Value.Links {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			linkRef := response.Value.Links[name]
+			if seen[name] || linkRef == nil || linkRef.Value == nil {
+				continue
+			}
+			link := linkRef.Value
+			target := linkTarget(doc, link, operationIDCounts)
+			if target == nil || toolNames[target] == "" {
+				log.Debug("Ignoring response link to an unknown operation.", slog.String("link", name),
+					slog.String("operation_id", link.OperationID), slog.String("operation_ref", link.OperationRef))
+				continue
+			}
+			seen[name] = true
+			toolLink := domain.ToolLink{Name: name, Tool: toolNames[target], Description: link.Description}
+			for param, value := range link.Parameters {
+				if toolLink.Parameters == nil {
+					toolLink.Parameters = make(map[string]string, len(link.Parameters))
+				}
+				toolLink.Parameters[param] = fmt.Sprint(value)
+			}
+			links = append(links, toolLink)
+		}
+	}
+	return links
+}
+
+// linkTarget returns the operation of doc that link points to by operationId or by a
+// local operationRef such as "#/paths/~1users~1{id}/get", or nil.
+func linkTarget(doc *openapi3.T, link *openapi3.Link, operationIDCounts map[string]int) *openapi3.Operation {
+	if link.OperationID != "" {
+		if operationIDCounts[link.OperationID] != 1 {
+			return nil
+		}
+		for _, pathItem := range doc.Paths.Map() {
+			for _, operation := range pathItem.Operations() {
+				if operation != nil && operation.OperationID == link.OperationID {
+					return operation
+				}
+			}
+		}
+		return nil
+	}
+	ref, ok := strings.CutPrefix(link.OperationRef, "#/paths/")
+	if !ok {
+		return nil
+	}
+	i := strings.LastIndex(ref, "/")
+	if i < 0 {
+		return nil
+	}
+	path := strings.NewReplacer("~1", "/", "~0", "~").Replace(ref[:i])
+	pathItem := doc.Paths.Value(path)
+	if pathItem == nil {
+		return nil
+	}
+	return pathItem.GetOperation(strings.ToUpper(ref[i+1:]))
+}
+
+// dropDanglingLinks removes links to tools that were not generated, e.g. because
+// their operation's schemas could not be converted.
+func dropDanglingLinks(tools []domain.Tool) {
+	generated := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		generated[tool.Name] = true
+	}
+	for i := range tools {
+		var links []domain.ToolLink
+		for _, link := range tools[i].Links {
+			if generated[link.Tool] {
+				links = append(links, link)
+			}
+		}
+		tools[i].Links = links
+	}
+}
+
+// countOperationIDs returns how many operations in doc use each non-empty operationId.
+func countOperationIDs(doc *openapi3.T) map[string]int {
+	counts := make(map[string]int)
+	for _, pathItem := range doc.Paths.Map() {
+		if pathItem == nil {
+			continue
+		}
+		for _, operation := range pathItem.Operations() {
+			if operation != nil && operation.OperationID != "" {
+				counts[operation.OperationID]++
+			}
+		}
+	}
+	return counts
+}
+
+// disambiguateToolName appends the method and every path segment (including
+// parameter names) so operations sharing an operationId get distinct names.
+func (g *ToolGenerator) disambiguateToolName(toolName, path, method string) string {
+	nameParts := []string{toolName, strings.ToLower(method)}
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if part = g.sanitizeName(strings.Trim(part, "{}")); part != "" {
+			nameParts = append(nameParts, part)
+		}
+	}
+	return strings.Join(nameParts, "_")
+}
+
+// generateToolTitle returns a human-readable title for the operation, preferring the
+// summary, then the unsanitized operationId, then "METHOD /path".
+func generateToolTitle(path, method string, op *openapi3.Operation) string {
+	if title := strings.TrimSpace(op.Summary); title != "" {
+		return title
+	}
+	if op.OperationID != "" {
+		return op.OperationID
+	}
+	return fmt.Sprintf("%s %s", strings.ToUpper(method), path)
+}
+
+// annotationsForMethod infers MCP behavior hints from the HTTP method.
+// Safe methods are read-only; everything else may modify upstream state.
+func annotationsForMethod(method string) *domain.ToolAnnotations {
+	readOnly, destructive, idempotent := false, true, false
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS":
+		readOnly, destructive, idempotent = true, false, true
+	case "PUT", "DELETE":
+		idempotent = true
+	}
+	return &domain.ToolAnnotations{
+		ReadOnlyHint:    &readOnly,
+		DestructiveHint: &destructive,
+		IdempotentHint:  &idempotent,
+	}
+}
+
 // generateInputSchema combines parameters and request body into a single JSON Schema.
-func (g *ToolGenerator) generateInputSchema(log *slog.Logger, params openapi3.Parameters, requestBody *openapi3.RequestBodyRef) (*domain.JSONSchemaProps, error) {
+// A request body that is not an object becomes the bodyParam parameter.
+func (g *ToolGenerator) generateInputSchema(log *slog.Logger, params openapi3.Parameters, requestBody *openapi3.RequestBodyRef, bodyParam string) (*domain.JSONSchemaProps, error) {
 	props := make(map[string]domain.JSONSchemaProps)
 	var required []string
 
@@ -229,12 +741,19 @@ func (g *ToolGenerator) generateInputSchema(log *slog.Logger, params openapi3.Pa
 		}
 		// Only include query and path params in the primary input schema typically.
 		// Headers/cookies might be handled differently (e.g., via config or separate invocation metadata).
+		if _, ok := constantQueryValue(param); ok {
+			continue // Sent automatically, see generateInvocationDetails
+		}
 		if param.In == openapi3.ParameterInQuery || param.In == openapi3.ParameterInPath {
 			paramSchema, err := g.convertSchemaRef(log, param.Schema)
 			if err != nil {
 				return nil, fmt.Errorf("error converting schema for parameter %s: %w", param.Name, err)
 			}
-			// TODO: Add parameter description to schema description?
+			description := param.Description
+			if description == "" {
+				description = param.Schema.Value.Description
+			}
+			paramSchema.Description = describeWithExample(description, parameterExample(param))
 			props[param.Name] = *paramSchema
 			if param.Required {
 				required = append(required, param.Name)
@@ -244,10 +763,9 @@ func (g *ToolGenerator) generateInputSchema(log *slog.Logger, params openapi3.Pa
 
 	// Process request body
 	if requestBody != nil && requestBody.Value != nil && requestBody.Value.Content != nil {
-		// Prefer application/json
-		jsonContent := requestBody.Value.Content.Get("application/json")
-		if jsonContent != nil && jsonContent.Schema != nil && jsonContent.Schema.Value != nil {
-			bodySchemaRef := jsonContent.Schema
+		// Prefer application/json, then a form
+		if _, bodyContent := requestBodyContent(requestBody.Value.Content); bodyContent != nil {
+			bodySchemaRef := bodyContent.Schema
 			bodySchema, err := g.convertSchemaRef(log, bodySchemaRef)
 			if err != nil {
 				return nil, fmt.Errorf("error converting request body schema: %w", err)
@@ -269,19 +787,19 @@ func (g *ToolGenerator) generateInputSchema(log *slog.Logger, params openapi3.Pa
 				// Merge required fields from body schema
 				required = append(required, bodySchema.Required...)
 			} else {
-				// If the body is not an object (e.g., plain string, array), need a strategy.
-				// Option: Wrap it in a key, e.g., {"body": ...}. For now, add it as a special key.
-				if _, exists := props["requestBody"]; exists {
-					return nil, fmt.Errorf("cannot represent non-object request body when 'requestBody' key is already used by a parameter")
+				// A non-object body (e.g., plain string, array) is wrapped in a single
+				// parameter; generateInvocationDetails names the same one as BodyParam.
+				if _, exists := props[bodyParam]; exists {
+					return nil, fmt.Errorf("cannot represent non-object request body when '%s' key is already used by a parameter", bodyParam)
 				}
-				props["requestBody"] = *bodySchema
+				props[bodyParam] = *bodySchema
 				if requestBody.Value.Required {
-					required = append(required, "requestBody")
+					required = append(required, bodyParam)
 				}
 			}
 		} else {
 			// Handle other content types or lack of schema if necessary
-			log.Warn("Warning: Request body found but application/json schema is missing or invalid.")
+			log.Warn("Warning: Request body found but application/json or form schema is missing or invalid.")
 		}
 	}
 
@@ -297,15 +815,16 @@ func (g *ToolGenerator) generateInputSchema(log *slog.Logger, params openapi3.Pa
 }
 
 // generateOutputSchema finds the most suitable response (e.g., 200 OK with JSON)
-// and converts its schema.
-func (g *ToolGenerator) generateOutputSchema(log *slog.Logger, responses *openapi3.Responses) (*domain.JSONSchemaProps, error) {
+// and converts its schema. Responses under preferredStatuses (e.g., "202", "2XX", or
+// "default") are tried first, in order.
+func (g *ToolGenerator) generateOutputSchema(log *slog.Logger, responses *openapi3.Responses, preferredStatuses []string) (*domain.JSONSchemaProps, error) {
 	if responses == nil || responses.Map() == nil {
 		return nil, nil // No output schema defined
 	}
 
-	// Prioritize 200 or 201 response, then other 2xx
+	// Prioritize the source's preferred statuses, then 200 or 201 response, then other 2xx
 	var successResponse *openapi3.ResponseRef
-	statusCodes := []string{"200", "201"}
+	statusCodes := append(append([]string{}, preferredStatuses...), "200", "201")
 	for _, code := range statusCodes {
 		if respRef, ok := responses.Map()[code]; ok {
 			successResponse = respRef
@@ -321,6 +840,9 @@ func (g *ToolGenerator) generateOutputSchema(log *slog.Logger, responses *openap
 			}
 		}
 	}
+	if successResponse == nil && g.textOutputFallback {
+		successResponse = responses.Default()
+	}
 
 	if successResponse == nil || successResponse.Value == nil || successResponse.Value.Content == nil {
 		log.Debug("Warning: No suitable success response found or it has no content")
@@ -330,7 +852,9 @@ func (g *ToolGenerator) generateOutputSchema(log *slog.Logger, responses *openap
 	// Prefer application/json content
 	jsonContent := successResponse.Value.Content.Get("application/json")
 	if jsonContent == nil || jsonContent.Schema == nil || jsonContent.Schema.Value == nil {
-		// Consider text/plain or other types? For now, only JSON.
+		if g.textOutputFallback {
+			return textOutputSchema(successResponse.Value.Content), nil
+		}
 		log.Debug("Warning: No JSON schema found for success response")
 		return nil, nil // No JSON schema found for success response
 	}
@@ -343,6 +867,76 @@ func (g *ToolGenerator) generateOutputSchema(log *slog.Logger, responses *openap
 	return outputSchema, nil
 }
 
+// textOutputSchema describes a non-JSON response as a string, naming its media types.
+func textOutputSchema(content openapi3.Content) *domain.JSONSchemaProps {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	description := "Response body as text"
+	if len(mediaTypes) > 0 {
+		description += " (" + strings.Join(mediaTypes, ", ") + ")"
+	}
+	return &domain.JSONSchemaProps{Type: "string", Description: description}
+}
+
+// acceptHeader builds an Accept header from the content types of all 2xx responses.
+// JSON types are listed first; other types follow with a lower quality value so
+// servers doing content negotiation pick JSON when they can. It returns "" if no
+// success response declares content.
+func acceptHeader(responses *openapi3.Responses) string {
+	if responses == nil {
+		return ""
+	}
+	var codes []string
+	for code := range responses.Map() {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	var jsonTypes, otherTypes []string
+	seen := make(map[string]struct{})
+	for _, code := range codes {
+		respRef := responses.Map()[code]
+		if respRef == nil || respRef.Value == nil {
+			continue
+		}
+		contentTypes := make([]string, 0, len(respRef.Value.Content))
+		for contentType := range respRef.Value.Content {
+			contentTypes = append(contentTypes, contentType)
+		}
+		sort.Strings(contentTypes)
+		for _, contentType := range contentTypes {
+			if _, ok := seen[contentType]; ok {
+				continue
+			}
+			seen[contentType] = struct{}{}
+			if isJSONMediaType(contentType) {
+				jsonTypes = append(jsonTypes, contentType)
+			} else {
+				otherTypes = append(otherTypes, contentType)
+			}
+		}
+	}
+	if len(jsonTypes) == 0 || len(otherTypes) == 0 {
+		return strings.Join(append(jsonTypes, otherTypes...), ", ")
+	}
+	for i, contentType := range otherTypes {
+		otherTypes[i] = contentType + ";q=0.9"
+	}
+	return strings.Join(append(jsonTypes, otherTypes...), ", ")
+}
+
+// isJSONMediaType reports whether contentType is application/json or a +json type.
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
 // convertSchemaRef converts an openapi3.SchemaRef into a domain.JSONSchemaProps.
 // This is recursive and handles basic types, objects, arrays, and enums.
 func (g *ToolGenerator) convertSchemaRef(log *slog.Logger, ref *openapi3.SchemaRef) (*domain.JSONSchemaProps, error) {
@@ -366,10 +960,11 @@ func (g *ToolGenerator) convertSchemaRef(log *slog.Logger, ref *openapi3.SchemaR
 	}
 
 	props := domain.JSONSchemaProps{
-		Type:   schemaType,
-		Format: schema.Format,
-		Enum:   schema.Enum,
-		// TODO: Map other fields like description, default, validation constraints
+		Type:        schemaType,
+		Format:      schema.Format,
+		Enum:        schema.Enum,
+		Description: describeWithExample(schema.Description, schema.Example),
+		// TODO: Map other fields like default, validation constraints
 	}
 
 	switch schemaType { // Switch on the string representation
@@ -417,7 +1012,7 @@ func (g *ToolGenerator) convertSchemaRef(log *slog.Logger, ref *openapi3.SchemaR
 }
 
 // generateInvocationDetails creates the details needed to invoke the API endpoint.
-func (g *ToolGenerator) generateInvocationDetails(log *slog.Logger, host, basePath, path, method string, op *openapi3.Operation) (*usecase.InvocationDetails, error) {
+func (g *ToolGenerator) generateInvocationDetails(log *slog.Logger, host, basePath, path, method string, op *openapi3.Operation, bodyParam string) (*usecase.InvocationDetails, error) {
 	details := usecase.InvocationDetails{
 		Type:         "http", // HTTP REST API
 		Host:         host,
@@ -428,6 +1023,7 @@ func (g *ToolGenerator) generateInvocationDetails(log *slog.Logger, host, basePa
 		QueryParams:  []string{},
 		HeaderParams: make(map[string]string),
 		ContentType:  "application/json", // Default assumption
+		Accept:       acceptHeader(op.Responses),
 	}
 
 	// Extract parameter names by location
@@ -436,10 +1032,20 @@ func (g *ToolGenerator) generateInvocationDetails(log *slog.Logger, host, basePa
 			continue
 		}
 		param := paramRef.Value
+		if format := dateFormat(param.Schema); format != "" && param.In != openapi3.ParameterInCookie {
+			addDateParam(&details, param.Name, format)
+		}
 		switch param.In {
 		case openapi3.ParameterInPath:
 			details.PathParams = append(details.PathParams, param.Name)
 		case openapi3.ParameterInQuery:
+			if value, ok := constantQueryValue(param); ok {
+				if details.StaticQueryParams == nil {
+					details.StaticQueryParams = make(map[string]string)
+				}
+				details.StaticQueryParams[param.Name] = value
+				continue
+			}
 			details.QueryParams = append(details.QueryParams, param.Name)
 		case openapi3.ParameterInHeader:
 			// How to handle header params? Are they static or dynamic?
@@ -455,11 +1061,10 @@ func (g *ToolGenerator) generateInvocationDetails(log *slog.Logger, host, basePa
 
 	// Determine BodyParam and ContentType
 	if op.RequestBody != nil && op.RequestBody.Value != nil && op.RequestBody.Value.Content != nil {
-		// Prefer application/json
-		jsonContent := op.RequestBody.Value.Content.Get("application/json")
-		if jsonContent != nil && jsonContent.Schema != nil && jsonContent.Schema.Value != nil {
-			bodySchema := jsonContent.Schema.Value
-			details.ContentType = "application/json"
+		// Prefer application/json, then a form
+		if mediaType, bodyContent := requestBodyContent(op.RequestBody.Value.Content); bodyContent != nil {
+			bodySchema := bodyContent.Schema.Value
+			details.ContentType = mediaType
 
 			// Check the first type if specified
 			var bodySchemaType string
@@ -473,9 +1078,17 @@ func (g *ToolGenerator) generateInvocationDetails(log *slog.Logger, host, basePa
 				// directly from the input parameters.
 				// Let's leave BodyParam empty and let the invoker figure it out based on remaining params?
 				details.BodyParam = "" // Indicate complex body construction needed
+				for name, property := range bodySchema.Properties {
+					if format := dateFormat(property); format != "" {
+						addDateParam(&details, name, format)
+					}
+				}
 			} else {
-				// If body is a primitive/array, assume it maps to a single input param named "requestBody".
-				details.BodyParam = "requestBody"
+				// If body is a primitive/array, it maps to the single wrapping input param.
+				details.BodyParam = bodyParam
+				if format := dateFormat(bodyContent.Schema); format != "" {
+					addDateParam(&details, bodyParam, format)
+				}
 			}
 		} else {
 			// Handle other content types (e.g., form-urlencoded, plain text) if needed
@@ -489,7 +1102,7 @@ func (g *ToolGenerator) generateInvocationDetails(log *slog.Logger, host, basePa
 			if firstContentType != "" {
 				log.Debug("Using first available content type for non-JSON request body", slog.String("contentType", firstContentType))
 				details.ContentType = firstContentType
-				details.BodyParam = "requestBody" // Assume non-JSON maps to single input
+				details.BodyParam = bodyParam // Assume non-JSON maps to single input
 			} else {
 				details.ContentType = "" // No content type found
 				details.BodyParam = ""
@@ -506,9 +1119,172 @@ func (g *ToolGenerator) generateInvocationDetails(log *slog.Logger, host, basePa
 
 // --- Helpers ---
 
-// sanitizeName removes characters unsuitable for identifiers and replaces them.
-func sanitizeName(name string) string {
-	name = strings.ToLower(name)
+// dateFormat returns "date" or "date-time" if schema is a string of that format, and
+// "" otherwise.
+func dateFormat(schema *openapi3.SchemaRef) string {
+	if schema == nil || schema.Value == nil || !schema.Value.Type.Is("string") {
+		return ""
+	}
+	switch schema.Value.Format {
+	case "date", "date-time":
+		return schema.Value.Format
+	}
+	return ""
+}
+
+// addDateParam records that the tool input name holds a date of the given format.
+func addDateParam(details *usecase.InvocationDetails, name, format string) {
+	if details.DateParams == nil {
+		details.DateParams = make(map[string]string)
+	}
+	details.DateParams[name] = format
+}
+
+// formMediaType is the media type of URL-encoded form request bodies.
+const formMediaType = "application/x-www-form-urlencoded"
+
+// bodyParamFor returns the tool parameter carrying op's request body if that is not
+// an object. A scalar body that is the operation's only input is named for what it
+// holds, after the operation's x-codegen-request-body-name or the schema's title, or
+// else "text" for strings and "value" for other scalars, unless a body parameter
+// name other than DefaultBodyParamName is configured.
+func (g *ToolGenerator) bodyParamFor(op *openapi3.Operation) string {
+	if g.bodyParamName != DefaultBodyParamName || op.RequestBody == nil || op.RequestBody.Value == nil {
+		return g.bodyParamName
+	}
+	_, bodyContent := requestBodyContent(op.RequestBody.Value.Content)
+	if bodyContent == nil {
+		return g.bodyParamName
+	}
+	bodySchema := bodyContent.Schema.Value
+	var bodyType string
+	if bodySchema.Type != nil && len(*bodySchema.Type) > 0 {
+		bodyType = (*bodySchema.Type)[0]
+	}
+	if bodyType != "string" && bodyType != "number" && bodyType != "integer" && bodyType != "boolean" {
+		return g.bodyParamName
+	}
+	for _, paramRef := range op.Parameters {
+		if paramRef == nil || paramRef.Value == nil {
+			continue
+		}
+		if _, ok := constantQueryValue(paramRef.Value); ok {
+			continue
+		}
+		if paramRef.Value.In == openapi3.ParameterInPath || paramRef.Value.In == openapi3.ParameterInQuery {
+			return g.bodyParamName
+		}
+	}
+
+	if name, ok := op.Extensions["x-codegen-request-body-name"].(string); ok {
+		if name = parameterName(name); name != "" {
+			return name
+		}
+	}
+	if name := parameterName(strings.ToLower(bodySchema.Title)); name != "" {
+		return name
+	}
+	if bodyType == "string" {
+		return "text"
+	}
+	return "value"
+}
+
+// nonIdentifierPattern matches runs of characters not allowed in parameter names.
+var nonIdentifierPattern = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// parameterName turns a name such as "note text" into a parameter name ("note_text").
+func parameterName(name string) string {
+	return strings.Trim(nonIdentifierPattern.ReplaceAllString(name, "_"), "_")
+}
+
+// requestBodyContent returns the request body media type whose schema becomes tool
+// input: application/json, else a URL-encoded form. Sources may narrow a body's media
+// types to their preferred one beforehand (see SchemaSourceConfig.RequestContentTypes).
+func requestBodyContent(content openapi3.Content) (string, *openapi3.MediaType) {
+	for _, mediaType := range []string{"application/json", formMediaType} {
+		if mt := content.Get(mediaType); mt != nil && mt.Schema != nil && mt.Schema.Value != nil {
+			return mediaType, mt
+		}
+	}
+	return "", nil
+}
+
+// maxExampleLength bounds the JSON of an example appended to a description, so
+// that large sample payloads do not bloat the tool definition.
+const maxExampleLength = 200
+
+// parameterExample returns the parameter's example: its own example, else the
+// first of its named examples (by name), else its schema's example.
+func parameterExample(param *openapi3.Parameter) any {
+	if param.Example != nil {
+		return param.Example
+	}
+	names := make([]string, 0, len(param.Examples))
+	for name, ref := range param.Examples {
+		if ref != nil && ref.Value != nil && ref.Value.Value != nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return param.Examples[names[0]].Value.Value
+	}
+	if param.Schema != nil && param.Schema.Value != nil {
+		return param.Schema.Value.Example
+	}
+	return nil
+}
+
+// describeWithExample appends example to description as compact JSON, as in
+// `The pet's name. Example: "Rex"`. Examples that cannot be encoded or exceed
+// maxExampleLength are left out.
+func describeWithExample(description string, example any) string {
+	if example == nil {
+		return description
+	}
+	encoded, err := json.Marshal(example)
+	if err != nil || len(encoded) > maxExampleLength {
+		return description
+	}
+	if description == "" {
+		return "Example: " + string(encoded)
+	}
+	return strings.TrimRight(description, " ") + " Example: " + string(encoded)
+}
+
+// constantQueryValue reports the value of a query parameter whose schema allows
+// exactly one value, via a single-value enum or a (3.1) const, so that it can be
+// sent automatically instead of being asked from the caller.
+func constantQueryValue(param *openapi3.Parameter) (string, bool) {
+	if param.In != openapi3.ParameterInQuery || param.Schema == nil || param.Schema.Value == nil {
+		return "", false
+	}
+	schema := param.Schema.Value
+	if len(schema.Enum) == 1 && schema.Enum[0] != nil {
+		return formatConstant(schema.Enum[0]), true
+	}
+	if value, ok := schema.Extensions["const"]; ok && value != nil {
+		return formatConstant(value), true
+	}
+	return "", false
+}
+
+// formatConstant renders a schema constant as a query value; numbers decoded as
+// float64 are printed without an exponent.
+func formatConstant(value any) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// sanitizeName removes characters unsuitable for identifiers and replaces them. The
+// name is lowercased unless the generator preserves name case.
+func (g *ToolGenerator) sanitizeName(name string) string {
+	if !g.preserveNameCase {
+		name = strings.ToLower(name)
+	}
 	// Replace non-alphanumeric characters with underscore (for Claude Desktop compatibility)
 	replacer := strings.NewReplacer(" ", "_", "-", "_", "/", "_", ".", "_")
 	name = replacer.Replace(name)
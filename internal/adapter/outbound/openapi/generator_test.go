@@ -0,0 +1,1428 @@
+package openapi_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/httpinvoker"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/openapi"
+	"github.com/i2y/mcpizer/internal/domain"
+)
+
+const petstoreSpec = `
+openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://petstore.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+      responses:
+        "200":
+          description: ok
+  /pets/{petId}:
+    delete:
+      operationId: deletePet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "204":
+          description: deleted
+`
+
+// generateFromSpec parses spec and runs the OpenAPI generator over it, returning tools keyed by name.
+func generateFromSpec(t *testing.T, spec string) map[string]domain.Tool {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	tools, details, err := generator.Generate(domain.APISchema{
+		Source:     "https://petstore.example.com/openapi.yaml",
+		Type:       domain.SchemaTypeOpenAPI,
+		ParsedData: doc,
+	})
+	require.NoError(t, err)
+	require.Len(t, details, len(tools))
+
+	byName := make(map[string]domain.Tool, len(tools))
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+	return byName
+}
+
+func TestToolGenerator_Generate_Annotations(t *testing.T) {
+	tools := generateFromSpec(t, petstoreSpec)
+
+	list, ok := tools["petstore_listpets"]
+	require.True(t, ok, "GET tool should be generated")
+	require.NotNil(t, list.Annotations)
+	assert.True(t, *list.Annotations.ReadOnlyHint)
+	assert.False(t, *list.Annotations.DestructiveHint)
+
+	del, ok := tools["petstore_deletepet"]
+	require.True(t, ok, "DELETE tool should be generated")
+	require.NotNil(t, del.Annotations)
+	assert.False(t, *del.Annotations.ReadOnlyHint)
+	assert.True(t, *del.Annotations.DestructiveHint)
+	assert.True(t, *del.Annotations.IdempotentHint)
+}
+
+func TestToolGenerator_Generate_Title(t *testing.T) {
+	tools := generateFromSpec(t, petstoreSpec)
+
+	tests := []struct {
+		name      string
+		wantTitle string
+	}{
+		{name: "petstore_listpets", wantTitle: "List all pets"}, // from summary
+		{name: "petstore_deletepet", wantTitle: "deletePet"},    // from unsanitized operationId
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, ok := tools[tt.name]
+			require.True(t, ok)
+			assert.Equal(t, tt.wantTitle, tool.Title)
+			assert.NotEqual(t, tool.Name, tool.Title)
+		})
+	}
+}
+
+func TestToolGenerator_Generate_DuplicateOperationID(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://petstore.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: getPets
+      responses:
+        "200":
+          description: ok
+  /pets/{petId}:
+    get:
+      operationId: getPets
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+  /owners:
+    get:
+      operationId: listOwners
+      responses:
+        "200":
+          description: ok
+`
+	tools := generateFromSpec(t, spec)
+
+	assert.Len(t, tools, 3)
+	assert.Contains(t, tools, "petstore_getpets_get_pets")
+	assert.Contains(t, tools, "petstore_getpets_get_pets_petid")
+	assert.Contains(t, tools, "petstore_listowners", "unique operationIds keep their plain names")
+}
+
+func TestToolGenerator_Generate_NoServers(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: ok
+`
+	const source = "https://petstore.example.com/openapi.yaml"
+
+	tests := []struct {
+		name         string
+		opts         []openapi.GeneratorOption
+		wantErr      []string
+		wantHost     string
+		wantBasePath string
+	}{
+		{
+			name:    "no default host",
+			wantErr: []string{source, "MCPIZER_OPENAPI_DEFAULT_HOST", `"server"`},
+		},
+		{
+			name:         "default host",
+			opts:         []openapi.GeneratorOption{openapi.WithDefaultHost("https://fallback.example.com/api/")},
+			wantHost:     "https://fallback.example.com",
+			wantBasePath: "/api",
+		},
+		{
+			name:    "invalid default host",
+			opts:    []openapi.GeneratorOption{openapi.WithDefaultHost("fallback.example.com")},
+			wantErr: []string{"invalid default host"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			require.NoError(t, err)
+
+			generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)), tt.opts...)
+			tools, details, err := generator.Generate(domain.APISchema{
+				Source:     source,
+				Type:       domain.SchemaTypeOpenAPI,
+				ParsedData: doc,
+			})
+			if len(tt.wantErr) > 0 {
+				require.Error(t, err)
+				for _, want := range tt.wantErr {
+					assert.Contains(t, err.Error(), want)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, tools, 1)
+			require.Len(t, details, 1)
+			assert.Equal(t, tt.wantHost, details[0].Host)
+			assert.Equal(t, tt.wantBasePath, details[0].BasePath)
+		})
+	}
+}
+
+func TestToolGenerator_Generate_Accept(t *testing.T) {
+	tests := []struct {
+		name       string
+		responses  string
+		wantAccept string
+	}{
+		{
+			name: "json only",
+			responses: `
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: object}`,
+			wantAccept: "application/json",
+		},
+		{
+			name: "json preferred over csv",
+			responses: `
+        "200":
+          description: ok
+          content:
+            text/csv:
+              schema: {type: string}
+            application/json:
+              schema: {type: object}`,
+			wantAccept: "application/json, text/csv;q=0.9",
+		},
+		{
+			name: "non-json only",
+			responses: `
+        "200":
+          description: ok
+          content:
+            text/plain:
+              schema: {type: string}`,
+			wantAccept: "text/plain",
+		},
+		{
+			name: "no content",
+			responses: `
+        "204":
+          description: no content`,
+			wantAccept: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := `
+openapi: 3.0.0
+info:
+  title: Reports
+  version: 1.0.0
+servers:
+  - url: https://reports.example.com
+paths:
+  /report:
+    get:
+      operationId: getReport
+      responses:` + tt.responses + `
+`
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			require.NoError(t, err)
+
+			generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			_, details, err := generator.Generate(domain.APISchema{
+				Source:     "https://reports.example.com/openapi.yaml",
+				Type:       domain.SchemaTypeOpenAPI,
+				ParsedData: doc,
+			})
+			require.NoError(t, err)
+			require.Len(t, details, 1)
+			assert.Equal(t, tt.wantAccept, details[0].Accept)
+		})
+	}
+}
+
+func TestToolGenerator_Generate_ConstantQueryParams(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  title: Reports
+  version: 1.0.0
+servers:
+  - url: https://reports.example.com
+paths:
+  /reports:
+    get:
+      operationId: listReports
+      parameters:
+        - name: format
+          in: query
+          required: true
+          schema:
+            type: string
+            enum: [json]
+        - name: version
+          in: query
+          schema:
+            type: integer
+            enum: [1000000]
+        - name: sort
+          in: query
+          schema:
+            type: string
+            enum: [asc, desc]
+      responses:
+        "200":
+          description: ok
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	tools, details, err := generator.Generate(domain.APISchema{
+		Source:     "https://reports.example.com/openapi.yaml",
+		Type:       domain.SchemaTypeOpenAPI,
+		ParsedData: doc,
+	})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	require.Len(t, details, 1)
+
+	// Single-value enums are sent automatically instead of being tool inputs.
+	assert.NotContains(t, tools[0].InputSchema.Properties, "format")
+	assert.NotContains(t, tools[0].InputSchema.Properties, "version")
+	assert.NotContains(t, tools[0].InputSchema.Required, "format")
+	assert.Contains(t, tools[0].InputSchema.Properties, "sort")
+	assert.Equal(t, map[string]string{"format": "json", "version": "1000000"}, details[0].StaticQueryParams)
+	assert.Equal(t, []string{"sort"}, details[0].QueryParams)
+}
+
+func TestToolGenerator_Generate_ServerVariables(t *testing.T) {
+	tests := []struct {
+		name         string
+		servers      string
+		wantHost     string
+		wantBasePath string
+		wantHosts    []string
+	}{
+		{
+			name: "variable in path",
+			servers: `
+  - url: https://api.example.com/api/{version}
+    variables:
+      version:
+        default: v2
+        enum: [v1, v2]`,
+			wantHost:     "https://api.example.com",
+			wantBasePath: "/api/v2",
+		},
+		{
+			name: "variables in host and path",
+			servers: `
+  - url: https://{region}.example.com/{basePath}/
+    variables:
+      region:
+        default: eu
+      basePath:
+        default: public/v1`,
+			wantHost:     "https://eu.example.com",
+			wantBasePath: "/public/v1",
+		},
+		{
+			name: "server with undeclared variable is skipped",
+			servers: `
+  - url: https://api.example.com/{tenant}
+  - url: https://fallback.example.com/v1`,
+			wantHost:     "https://fallback.example.com",
+			wantBasePath: "/v1",
+		},
+		{
+			name: "further servers with the same base path are fallbacks",
+			servers: `
+  - url: https://api.example.com/v1
+  - url: https://eu.api.example.com/v1/
+  - url: https://legacy.example.com/old
+  - url: https://api.example.com/v1`,
+			wantHost:     "https://api.example.com",
+			wantBasePath: "/v1",
+			wantHosts:    []string{"https://api.example.com", "https://eu.api.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+servers:` + tt.servers + `
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: ok
+`
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			require.NoError(t, err)
+
+			generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			_, details, err := generator.Generate(domain.APISchema{
+				Source:     "https://api.example.com/openapi.yaml",
+				Type:       domain.SchemaTypeOpenAPI,
+				ParsedData: doc,
+			})
+			require.NoError(t, err)
+			require.Len(t, details, 1)
+			assert.Equal(t, tt.wantHost, details[0].Host)
+			assert.Equal(t, tt.wantBasePath, details[0].BasePath)
+			assert.Equal(t, tt.wantHosts, details[0].Hosts)
+		})
+	}
+}
+
+func TestToolGenerator_Generate_ServerOverrides(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: ok
+    post:
+      operationId: createPet
+      servers:
+        - url: https://write.example.com/v2
+      responses:
+        "201":
+          description: created
+  /uploads:
+    servers:
+      - url: https://uploads.example.com
+    put:
+      operationId: upload
+      responses:
+        "204":
+          description: stored
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	_, details, err := generator.Generate(domain.APISchema{
+		Source:     "https://api.example.com/openapi.yaml",
+		Type:       domain.SchemaTypeOpenAPI,
+		ParsedData: doc,
+	})
+	require.NoError(t, err)
+	require.Len(t, details, 3)
+
+	targets := make(map[string]string)
+	for _, d := range details {
+		targets[d.HTTPMethod+" "+d.HTTPPath] = d.Host + d.BasePath
+	}
+	assert.Equal(t, map[string]string{
+		"GET /pets":    "https://api.example.com/v1",
+		"POST /pets":   "https://write.example.com/v2",
+		"PUT /uploads": "https://uploads.example.com",
+	}, targets)
+}
+
+func TestToolGenerator_Generate_SkipsCallbacksAndWebhooks(t *testing.T) {
+	spec := `
+openapi: 3.1.0
+info:
+  title: Events
+  version: 1.0.0
+servers:
+  - url: https://events.example.com
+paths:
+  /subscriptions:
+    post:
+      operationId: subscribe
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                callbackUrl:
+                  type: string
+      responses:
+        "201":
+          description: subscribed
+      callbacks:
+        onEvent:
+          "{$request.body#/callbackUrl}":
+            post:
+              operationId: eventCallback
+              responses:
+                "200":
+                  description: ok
+  "{$request.body#/callbackUrl}/ping":
+    post:
+      operationId: pingCallback
+      responses:
+        "200":
+          description: ok
+webhooks:
+  newEvent:
+    post:
+      operationId: newEventWebhook
+      responses:
+        "200":
+          description: ok
+`
+
+	tools := generateFromSpec(t, spec)
+
+	assert.Len(t, tools, 1)
+	assert.Contains(t, tools, "events_subscribe")
+}
+
+func TestToolGenerator_Generate_BodyParamName(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  title: Notes
+  version: 1.0.0
+servers:
+  - url: https://notes.example.com
+paths:
+  /notes/{noteId}/text:
+    put:
+      operationId: setNoteText
+      parameters:
+        - name: noteId
+          in: path
+          required: true
+          schema:
+            type: string
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: string
+      responses:
+        "204":
+          description: updated
+`
+
+	tests := []struct {
+		name     string
+		opts     []openapi.GeneratorOption
+		wantName string
+	}{
+		{name: "default", wantName: openapi.DefaultBodyParamName},
+		{name: "configured", opts: []openapi.GeneratorOption{openapi.WithBodyParamName("text")}, wantName: "text"},
+		{name: "empty keeps default", opts: []openapi.GeneratorOption{openapi.WithBodyParamName("")}, wantName: "body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			require.NoError(t, err)
+
+			generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)), tt.opts...)
+			tools, details, err := generator.Generate(domain.APISchema{
+				Source:     "https://notes.example.com/openapi.yaml",
+				Type:       domain.SchemaTypeOpenAPI,
+				ParsedData: doc,
+			})
+			require.NoError(t, err)
+			require.Len(t, tools, 1)
+			require.Len(t, details, 1)
+
+			assert.Equal(t, domain.JSONSchemaProps{Type: "string"}, tools[0].InputSchema.Properties[tt.wantName])
+			assert.ElementsMatch(t, []string{"noteId", tt.wantName}, tools[0].InputSchema.Required)
+			assert.Equal(t, tt.wantName, details[0].BodyParam)
+		})
+	}
+}
+
+func TestToolGenerator_Generate_TextOutputFallback(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  title: Reports
+  version: 1.0.0
+servers:
+  - url: https://reports.example.com
+paths:
+  /reports/{id}/csv:
+    get:
+      operationId: getReportCSV
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: the report
+          content:
+            text/plain:
+              schema:
+                type: string
+  /health:
+    get:
+      operationId: health
+      responses:
+        default:
+          description: health status
+          content:
+            text/plain: {}
+`
+
+	tests := []struct {
+		name       string
+		opts       []openapi.GeneratorOption
+		wantReport *domain.JSONSchemaProps
+		wantHealth *domain.JSONSchemaProps
+	}{
+		{name: "disabled"},
+		{
+			name:       "enabled",
+			opts:       []openapi.GeneratorOption{openapi.WithTextOutputFallback(true)},
+			wantReport: &domain.JSONSchemaProps{Type: "string", Description: "Response body as text (text/plain)"},
+			wantHealth: &domain.JSONSchemaProps{Type: "string", Description: "Response body as text (text/plain)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			require.NoError(t, err)
+
+			generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)), tt.opts...)
+			tools, _, err := generator.Generate(domain.APISchema{
+				Source:     "https://reports.example.com/openapi.yaml",
+				Type:       domain.SchemaTypeOpenAPI,
+				ParsedData: doc,
+			})
+			require.NoError(t, err)
+
+			byName := make(map[string]domain.Tool, len(tools))
+			for _, tool := range tools {
+				byName[tool.Name] = tool
+			}
+			require.Contains(t, byName, "reports_getreportcsv")
+			require.Contains(t, byName, "reports_health")
+			assert.Equal(t, tt.wantReport, byName["reports_getreportcsv"].OutputSchema)
+			assert.Equal(t, tt.wantHealth, byName["reports_health"].OutputSchema)
+		})
+	}
+}
+
+func TestToolGenerator_Generate_Examples(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://petstore.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: status
+          in: query
+          description: Filter by status.
+          example: available
+          schema:
+            type: string
+        - name: tag
+          in: query
+          examples:
+            puppy:
+              value: [puppy, small]
+          schema:
+            type: array
+            items:
+              type: string
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            example: 20
+      responses:
+        "200":
+          description: ok
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                  description: The pet's name.
+                  example: Rex
+                payload:
+                  type: string
+                  example: ` + strings.Repeat("x", 300) + `
+      responses:
+        "201":
+          description: created
+`
+
+	tools := generateFromSpec(t, spec)
+
+	list := tools["petstore_listpets"].InputSchema.Properties
+	assert.Equal(t, `Filter by status. Example: "available"`, list["status"].Description)
+	assert.Equal(t, `Example: ["puppy","small"]`, list["tag"].Description)
+	assert.Equal(t, `Example: 20`, list["limit"].Description)
+
+	create := tools["petstore_createpet"].InputSchema.Properties
+	assert.Equal(t, `The pet's name. Example: "Rex"`, create["name"].Description)
+	assert.Empty(t, create["payload"].Description, "oversized examples are left out")
+}
+
+func TestToolGenerator_Generate_EndpointInDescription(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  title: Users
+  version: 1.0.0
+servers:
+  - url: https://users.example.com
+paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+      summary: Get a user.
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+  /health:
+    get:
+      operationId: health
+      responses:
+        "200":
+          description: ok
+`
+
+	tests := []struct {
+		name    string
+		enabled bool
+		want    map[string]string
+	}{
+		{
+			name: "disabled",
+			want: map[string]string{"users_getuser": "Get a user.", "users_health": "Executes GET /health"},
+		},
+		{
+			name:    "enabled",
+			enabled: true,
+			want:    map[string]string{"users_getuser": "Get a user. (GET /users/{id})", "users_health": "Executes GET /health"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			require.NoError(t, err)
+
+			generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)),
+				openapi.WithEndpointInDescription(tt.enabled))
+			tools, _, err := generator.Generate(domain.APISchema{
+				Source:     "https://users.example.com/openapi.yaml",
+				Type:       domain.SchemaTypeOpenAPI,
+				ParsedData: doc,
+			})
+			require.NoError(t, err)
+
+			got := make(map[string]string, len(tools))
+			for _, tool := range tools {
+				got[tool.Name] = tool.Description
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestToolGenerator_Generate_PreserveNameCase(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  title: User Service
+  version: 1.0.0
+servers:
+  - url: https://users.example.com
+paths:
+  /users/{id}:
+    get:
+      operationId: getUserById
+      responses:
+        "200":
+          description: ok
+  /userGroups:
+    get:
+      responses:
+        "200":
+          description: ok
+`
+
+	tests := []struct {
+		name     string
+		preserve bool
+		want     []string
+	}{
+		{
+			name: "lowercased by default",
+			want: []string{"user_service_get_usergroups", "user_service_getuserbyid"},
+		},
+		{
+			name:     "case preserved",
+			preserve: true,
+			want:     []string{"User_Service_get_userGroups", "User_Service_getUserById"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			require.NoError(t, err)
+
+			generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)),
+				openapi.WithPreserveNameCase(tt.preserve))
+			tools, _, err := generator.Generate(domain.APISchema{
+				Source:     "https://users.example.com/openapi.yaml",
+				Type:       domain.SchemaTypeOpenAPI,
+				ParsedData: doc,
+			})
+			require.NoError(t, err)
+
+			var names []string
+			for _, tool := range tools {
+				names = append(names, tool.Name)
+			}
+			assert.ElementsMatch(t, tt.want, names)
+		})
+	}
+}
+
+func TestToolGenerator_Generate_UndeclaredPathParams(t *testing.T) {
+	// {org} is used in the path but only {repo} is declared.
+	spec := `
+openapi: 3.0.0
+info:
+  title: Repos
+  version: 1.0.0
+servers:
+  - url: https://repos.example.com
+paths:
+  /orgs/{org}/repos/{repo}:
+    get:
+      operationId: getRepo
+      parameters:
+        - name: repo
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+`
+
+	tests := []struct {
+		name           string
+		enabled        bool
+		wantPathParams []string
+	}{
+		{name: "ignored by default", wantPathParams: []string{"repo"}},
+		{name: "treated as path params", enabled: true, wantPathParams: []string{"repo", "org"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			require.NoError(t, err)
+
+			generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)),
+				openapi.WithUndeclaredPathParams(tt.enabled))
+			tools, details, err := generator.Generate(domain.APISchema{
+				Source:     "https://repos.example.com/openapi.yaml",
+				Type:       domain.SchemaTypeOpenAPI,
+				ParsedData: doc,
+			})
+			require.NoError(t, err)
+			require.Len(t, tools, 1)
+			require.Len(t, details, 1)
+
+			assert.Equal(t, tt.wantPathParams, details[0].PathParams)
+			org, ok := tools[0].InputSchema.Properties["org"]
+			if !tt.enabled {
+				assert.False(t, ok)
+				assert.NotContains(t, tools[0].InputSchema.Required, "org")
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, "string", org.Type)
+			assert.Contains(t, tools[0].InputSchema.Required, "org")
+
+			// The HTTP invoker substitutes the undeclared placeholder like a declared one.
+			var gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+			details[0].Host = server.URL
+			invoker := httpinvoker.New(server.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+			_, err = invoker.Invoke(context.Background(), details[0], map[string]interface{}{"org": "acme", "repo": "widgets"})
+			require.NoError(t, err)
+			assert.Equal(t, "/orgs/acme/repos/widgets", gotPath)
+		})
+	}
+}
+
+func TestToolGenerator_Generate_PathLevelParams(t *testing.T) {
+	// {id} is declared once for the path; getItem overrides the path-level fields parameter.
+	spec := `
+openapi: 3.0.0
+info:
+  title: Items
+  version: 1.0.0
+servers:
+  - url: https://items.example.com
+paths:
+  /items/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        description: Item ID
+        schema:
+          type: integer
+      - name: fields
+        in: query
+        description: Fields to return
+        schema:
+          type: string
+    get:
+      operationId: getItem
+      parameters:
+        - name: fields
+          in: query
+          description: Fields of the item to return
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+    delete:
+      operationId: deleteItem
+      responses:
+        "204":
+          description: deleted
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)),
+		openapi.WithUndeclaredPathParams(true))
+	tools, details, err := generator.Generate(domain.APISchema{
+		Source:     "https://items.example.com/openapi.yaml",
+		Type:       domain.SchemaTypeOpenAPI,
+		ParsedData: doc,
+	})
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+	require.Len(t, details, 2)
+
+	wantFields := map[string]string{"items_getitem": "Fields of the item to return", "items_deleteitem": "Fields to return"}
+	for i, tool := range tools {
+		t.Run(tool.Name, func(t *testing.T) {
+			id := tool.InputSchema.Properties["id"]
+			assert.Equal(t, "integer", id.Type, "the declared type is kept")
+			assert.Equal(t, "Item ID", id.Description)
+			assert.Contains(t, tool.InputSchema.Required, "id")
+			assert.Equal(t, wantFields[tool.Name], tool.InputSchema.Properties["fields"].Description)
+			assert.Equal(t, []string{"id"}, details[i].PathParams)
+			assert.Equal(t, []string{"fields"}, details[i].QueryParams)
+		})
+	}
+}
+
+func TestToolGenerator_Generate_Links(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  title: Users
+  version: 1.0.0
+servers:
+  - url: https://users.example.com
+paths:
+  /users:
+    post:
+      operationId: createUser
+      responses:
+        "201":
+          description: created
+          links:
+            GetUserByUserId:
+              operationId: getUser
+              description: Fetch the created user.
+              parameters:
+                userId: $response.body#/id
+            DeleteUser:
+              operationRef: "#/paths/~1users~1{userId}/delete"
+              parameters:
+                userId: $response.body#/id
+            Elsewhere:
+              operationRef: "https://other.example.com/openapi.json#/paths/~1things/get"
+        "400":
+          description: invalid
+          links:
+            Retry:
+              operationId: createUser
+  /users/{userId}:
+    parameters:
+      - name: userId
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: getUser
+      responses:
+        "200":
+          description: ok
+    delete:
+      responses:
+        "204":
+          description: deleted
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	tools, _, err := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil))).Generate(domain.APISchema{
+		Source:     "https://users.example.com/openapi.yaml",
+		Type:       domain.SchemaTypeOpenAPI,
+		ParsedData: doc,
+	})
+	require.NoError(t, err)
+
+	byName := make(map[string]domain.Tool)
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+	require.Contains(t, byName, "users_createuser")
+	// Only success responses count, and links to other documents are left out.
+	assert.Equal(t, []domain.ToolLink{
+		{Name: "DeleteUser", Tool: "users_delete_users", Parameters: map[string]string{"userId": "$response.body#/id"}},
+		{
+			Name:        "GetUserByUserId",
+			Tool:        "users_getuser",
+			Description: "Fetch the created user.",
+			Parameters:  map[string]string{"userId": "$response.body#/id"},
+		},
+	}, byName["users_createuser"].Links)
+	assert.Nil(t, byName["users_getuser"].Links)
+}
+
+func TestToolGenerator_Generate_ScalarBodyParamName(t *testing.T) {
+	const specTemplate = `
+openapi: 3.0.0
+info:
+  title: Notes
+  version: 1.0.0
+servers:
+  - url: https://notes.example.com
+paths:
+  /notes:
+    post:
+      operationId: createNote%s
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              %s
+      responses:
+        "201":
+          description: created
+`
+
+	tests := []struct {
+		name      string
+		extension string
+		schema    string
+		opts      []openapi.GeneratorOption
+		wantName  string
+	}{
+		{name: "string", schema: "type: string", wantName: "text"},
+		{name: "integer", schema: "type: integer", wantName: "value"},
+		{name: "schema title", schema: "{type: string, title: Note text}", wantName: "note_text"},
+		{name: "codegen extension", extension: "\n      x-codegen-request-body-name: noteBody", schema: "{type: string, title: Note text}", wantName: "noteBody"},
+		{name: "array keeps default", schema: "{type: array, items: {type: string}}", wantName: openapi.DefaultBodyParamName},
+		{name: "configured name wins", schema: "type: string", opts: []openapi.GeneratorOption{openapi.WithBodyParamName("payload")}, wantName: "payload"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(fmt.Sprintf(specTemplate, tt.extension, tt.schema)))
+			require.NoError(t, err)
+
+			tools, details, err := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)), tt.opts...).Generate(domain.APISchema{
+				Source:     "https://notes.example.com/openapi.yaml",
+				Type:       domain.SchemaTypeOpenAPI,
+				ParsedData: doc,
+			})
+			require.NoError(t, err)
+			require.Len(t, tools, 1)
+			require.Len(t, details, 1)
+
+			assert.Contains(t, tools[0].InputSchema.Properties, tt.wantName)
+			assert.Equal(t, []string{tt.wantName}, tools[0].InputSchema.Required)
+			assert.Equal(t, tt.wantName, details[0].BodyParam)
+		})
+	}
+
+	// The HTTP invoker sends the renamed parameter as the body.
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(fmt.Sprintf(specTemplate, "", "type: string")))
+	require.NoError(t, err)
+	_, details, err := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil))).Generate(domain.APISchema{
+		Source:     "https://notes.example.com/openapi.yaml",
+		Type:       domain.SchemaTypeOpenAPI,
+		ParsedData: doc,
+	})
+	require.NoError(t, err)
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	details[0].Host = server.URL
+	invoker := httpinvoker.New(server.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	_, err = invoker.Invoke(context.Background(), details[0], map[string]interface{}{"text": "Buy milk"})
+	require.NoError(t, err)
+	assert.Equal(t, `"Buy milk"`, gotBody)
+}
+
+func TestToolGenerator_GenerateWithReport(t *testing.T) {
+	const spec = `openapi: 3.0.0
+info:
+  title: Notes
+  version: 1.0.0
+servers:
+  - url: https://notes.example.com
+paths:
+  /notes:
+    get:
+      operationId: listNotes
+      responses:
+        "200":
+          description: ok
+    put:
+      operationId: replaceNotes
+      parameters:
+        - name: body
+          in: query
+          schema:
+            type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: array
+              items:
+                type: string
+      responses:
+        "204":
+          description: replaced
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	tools, details, skipped, err := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil))).GenerateWithReport(domain.APISchema{
+		Source:     "https://notes.example.com/openapi.yaml",
+		Type:       domain.SchemaTypeOpenAPI,
+		RawData:    []byte(spec),
+		ParsedData: doc,
+	})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	require.Len(t, details, 1)
+	assert.Equal(t, "notes_listnotes", tools[0].Name)
+
+	// The query parameter named "body" leaves no room for the array request body.
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "PUT /notes", skipped[0].Operation)
+	assert.Equal(t, 14, skipped[0].Line)
+	assert.Equal(t, "PUT /notes (line 14)", skipped[0].String())
+	assert.Contains(t, skipped[0].Reason, "'body' key is already used by a parameter")
+}
+
+func TestToolGenerator_Generate_DateParams(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: Reports
+  version: 1.0.0
+servers:
+  - url: https://reports.example.com
+paths:
+  /reports/{day}:
+    post:
+      operationId: createReport
+      parameters:
+        - {name: day, in: path, required: true, schema: {type: string, format: date}}
+        - {name: tz, in: query, schema: {type: string}}
+        - {name: session, in: cookie, schema: {type: string, format: date-time}}
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                since: {type: string, format: date-time}
+                count: {type: integer}
+      responses:
+        "201":
+          description: created
+  /reports/{day}/due:
+    put:
+      operationId: setDueDate
+      parameters:
+        - {name: day, in: path, required: true, schema: {type: string}}
+      requestBody:
+        content:
+          application/json:
+            schema: {type: string, format: date}
+      responses:
+        "204":
+          description: set
+`
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+
+	tools, details, err := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil))).Generate(domain.APISchema{
+		Source:     "https://reports.example.com/openapi.yaml",
+		Type:       domain.SchemaTypeOpenAPI,
+		ParsedData: doc,
+	})
+	require.NoError(t, err)
+
+	dateParams := make(map[string]map[string]string)
+	for i, tool := range tools {
+		dateParams[tool.Name] = details[i].DateParams
+	}
+	assert.Equal(t, map[string]map[string]string{
+		// Cookie parameters are no tool inputs.
+		"reports_createreport": {"day": "date", "since": "date-time"},
+		"reports_setduedate":   {openapi.DefaultBodyParamName: "date"},
+	}, dateParams)
+}
+
+func TestToolGenerator_Generate_Namespace(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  title: Pet Store
+  version: 1.0.0
+servers:
+  - url: https://petstore.example.com
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+  /pets/{id}/owner:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+`
+
+	tests := []struct {
+		name      string
+		opts      []openapi.GeneratorOption
+		wantNames []string
+	}{
+		{name: "title", wantNames: []string{"pet_store_getpet", "pet_store_get_pets_owner"}},
+		{name: "configured", opts: []openapi.GeneratorOption{openapi.WithNamespace("Acme")}, wantNames: []string{"acme_getpet", "acme_get_pets_owner"}},
+		{name: "none", opts: []openapi.GeneratorOption{openapi.WithNamespace(openapi.NoNamespace)}, wantNames: []string{"getpet", "get_pets_owner"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			require.NoError(t, err)
+
+			generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)), tt.opts...)
+			tools, _, err := generator.Generate(domain.APISchema{
+				Source:     "https://petstore.example.com/openapi.yaml",
+				Type:       domain.SchemaTypeOpenAPI,
+				ParsedData: doc,
+			})
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(tools))
+			for _, tool := range tools {
+				names = append(names, tool.Name)
+			}
+			assert.ElementsMatch(t, tt.wantNames, names)
+		})
+	}
+}
+
+func TestToolGenerator_Generate_ResponseStatuses(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: Exports
+  version: 1.0.0
+servers:
+  - url: https://exports.example.com
+paths:
+  /exports:
+    post:
+      operationId: startExport
+      responses:
+        "200":
+          description: export finished synchronously (legacy)
+          content:
+            application/json:
+              schema:
+                type: object
+        "202":
+          description: export job accepted
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  jobId:
+                    type: string
+                  state:
+                    type: string
+`
+
+	tests := []struct {
+		name      string
+		statuses  []string
+		wantProps []string
+	}{
+		{name: "default preference", wantProps: nil},
+		{name: "202 preferred", statuses: []string{"202"}, wantProps: []string{"jobId", "state"}},
+		{name: "first declared preference", statuses: []string{"204", "default", "202"}, wantProps: []string{"jobId", "state"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			require.NoError(t, err)
+
+			generator := openapi.NewToolGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			tools, _, err := generator.Generate(domain.APISchema{
+				Source:           "https://exports.example.com/openapi.yaml",
+				Type:             domain.SchemaTypeOpenAPI,
+				ParsedData:       doc,
+				ResponseStatuses: tt.statuses,
+			})
+			require.NoError(t, err)
+			require.Len(t, tools, 1)
+			require.NotNil(t, tools[0].OutputSchema)
+			var props []string
+			for name := range tools[0].OutputSchema.Properties {
+				props = append(props, name)
+			}
+			assert.ElementsMatch(t, tt.wantProps, props)
+		})
+	}
+}
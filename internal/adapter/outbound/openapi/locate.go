@@ -0,0 +1,154 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// operationMethods are the keys of a path item that declare operations.
+var operationMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// decodePaths decodes an OpenAPI document, JSON being a subset of YAML, and returns
+// its root node and its paths mapping. The paths node is nil if data cannot be
+// decoded or has no paths.
+func decodePaths(data []byte) (*yaml.Node, *yaml.Node) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return nil, nil
+	}
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value == "paths" && doc.Content[i+1].Kind == yaml.MappingNode {
+			return &root, doc.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// operationLines returns the line declaring each operation of an OpenAPI document,
+// keyed by "METHOD path" (e.g. "GET /users/{id}"). It returns nil if data cannot be
+// decoded.
+func operationLines(data []byte) map[string]int {
+	_, paths := decodePaths(data)
+	if paths == nil {
+		return nil
+	}
+	lines := make(map[string]int)
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		item := paths.Content[i+1]
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			if key := item.Content[j]; operationMethods[strings.ToLower(key.Value)] {
+				lines[strings.ToUpper(key.Value)+" "+paths.Content[i].Value] = key.Line
+			}
+		}
+	}
+	return lines
+}
+
+// maxLocatedOperations bounds the failing operations locateParseError names, and with
+// them how often it loads the document.
+const maxLocatedOperations = 10
+
+// pathOperation is one operation of a path item, with the path-level fields (such as
+// shared parameters) it is loaded with.
+type pathOperation struct {
+	path, item    *yaml.Node
+	shared        []*yaml.Node
+	method, value *yaml.Node
+}
+
+// pathsContent returns the content of a paths mapping holding just ops, merging the
+// operations of the same path into one path item.
+func pathsContent(ops []pathOperation) []*yaml.Node {
+	var content []*yaml.Node
+	items := make(map[*yaml.Node]*yaml.Node)
+	for _, op := range ops {
+		item, ok := items[op.item]
+		if !ok {
+			partial := *op.item
+			partial.Content = append([]*yaml.Node{}, op.shared...)
+			item = &partial
+			items[op.item] = item
+			content = append(content, op.path, item)
+		}
+		item.Content = append(item.Content, op.method, op.value)
+	}
+	return content
+}
+
+// locateParseError adds the operations and their lines to an error loading data,
+// which kin-openapi reports without saying where the problem is, e.g. for a
+// dangling $ref. If the document loads without its operations, the failing ones
+// are found by loading halves of the operations in turn, so that k failing
+// operations among n take O(k log n) loads; at most maxLocatedOperations are named.
+// Otherwise, or if no single operation fails, err is returned as is.
+func locateParseError(ctx context.Context, data []byte, err error) error {
+	root, paths := decodePaths(data)
+	if paths == nil {
+		return err
+	}
+	all := paths.Content
+	defer func() { paths.Content = all }()
+
+	load := func(content []*yaml.Node) error {
+		paths.Content = content
+		out, err := yaml.Marshal(root)
+		if err != nil {
+			return err
+		}
+		_, err = (&openapi3.Loader{Context: ctx}).LoadFromData(out)
+		return err
+	}
+	if load(nil) != nil {
+		return err
+	}
+
+	var ops []pathOperation
+	for i := 0; i+1 < len(all); i += 2 {
+		path, item := all[i], all[i+1]
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		var shared []*yaml.Node
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			if !operationMethods[strings.ToLower(item.Content[j].Value)] {
+				shared = append(shared, item.Content[j], item.Content[j+1])
+			}
+		}
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			if operationMethods[strings.ToLower(item.Content[j].Value)] {
+				ops = append(ops, pathOperation{path: path, item: item, shared: shared, method: item.Content[j], value: item.Content[j+1]})
+			}
+		}
+	}
+
+	var broken []string
+	var search func(ops []pathOperation)
+	search = func(ops []pathOperation) {
+		if len(ops) == 0 || len(broken) == maxLocatedOperations || load(pathsContent(ops)) == nil {
+			return
+		}
+		if len(ops) == 1 {
+			op := ops[0]
+			broken = append(broken, fmt.Sprintf("%s %s (line %d)", strings.ToUpper(op.method.Value), op.path.Value, op.method.Line))
+			return
+		}
+		search(ops[:len(ops)/2])
+		search(ops[len(ops)/2:])
+	}
+	search(ops)
+	if len(broken) == 0 {
+		return err
+	}
+	return fmt.Errorf("%s: %w", strings.Join(broken, ", "), err)
+}
@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// sniffLength is how much of a probed response is read to recognize an OpenAPI document.
+const sniffLength = 4096
+
+// isOpenAPIContentType reports whether a Content-Type header declares a JSON OpenAPI document.
+func isOpenAPIContentType(contentType string) bool {
+	return strings.Contains(contentType, "application/json") ||
+		strings.Contains(contentType, "application/vnd.oai.openapi+json")
+}
+
+// looksLikeOpenAPIDocument reports whether data, or its beginning, looks like an
+// OpenAPI or Swagger document: a JSON object, or YAML with a top-level "openapi:"
+// or "swagger:" key, which may be quoted. Some servers serve their spec as text/html
+// or text/plain, so the declared content type alone does not tell.
+func looksLikeOpenAPIDocument(data []byte) bool {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("{")) {
+		return true
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if isVersionKey(line, "openapi") || isVersionKey(line, "swagger") {
+			return true
+		}
+	}
+	return false
+}
+
+// isVersionKey reports whether the YAML line starts with the top-level key, plain
+// or in single or double quotes, followed by a colon.
+func isVersionKey(line []byte, key string) bool {
+	quote := ""
+	if len(line) > 0 && (line[0] == '"' || line[0] == '\'') {
+		quote = string(line[0])
+	}
+	rest, ok := bytes.CutPrefix(line, []byte(quote+key+quote))
+	if !ok {
+		return false
+	}
+	return bytes.HasPrefix(bytes.TrimLeft(rest, " \t"), []byte(":"))
+}
+
+// holdsOpenAPIDocument reports whether resp, the successful response of a probe of
+// testURL, serves an OpenAPI document. A JSON content type is trusted; otherwise the
+// beginning of the body is sniffed, requesting it with GET if resp answered a HEAD.
+func (d *AutoDiscoverer) holdsOpenAPIDocument(ctx context.Context, testURL string, header http.Header, resp *http.Response) (bool, error) {
+	if isOpenAPIContentType(resp.Header.Get("Content-Type")) {
+		return true, nil
+	}
+	body := resp.Body
+	if resp.Request != nil && resp.Request.Method == http.MethodHead {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, testURL, nil)
+		if err != nil {
+			return false, err
+		}
+		req.Header = header.Clone()
+		getResp, err := d.client.Do(req)
+		if err != nil {
+			return false, err
+		}
+		defer getResp.Body.Close()
+		if getResp.StatusCode != http.StatusOK {
+			return false, nil
+		}
+		body = getResp.Body
+	}
+	prefix, err := io.ReadAll(io.LimitReader(body, sniffLength))
+	if err != nil {
+		return false, err
+	}
+	return looksLikeOpenAPIDocument(prefix), nil
+}
@@ -0,0 +1,83 @@
+package openapi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// DefaultStreamThreshold is the declared size in bytes from which JSON OpenAPI
+// documents are decoded as they are read. Documents of unknown size are always
+// streamed.
+const DefaultStreamThreshold = 8 << 20
+
+// spec is an OpenAPI document being fetched: either its bytes, or the reader to
+// decode a large JSON document from as it is read.
+type spec struct {
+	data   []byte
+	stream *bufio.Reader
+	closer io.Closer // closed after streaming, if set
+}
+
+// readSpec reads the document from r, which declares size bytes (-1 if unknown). JSON
+// documents of unknown size, such as gzipped responses, or declaring at least
+// threshold bytes are left in r to be streamed, so that their bytes are not held
+// alongside the parsed document; other documents are read into a buffer of their
+// declared size.
+func readSpec(r io.Reader, size, threshold int64) (spec, error) {
+	if threshold > 0 && (size < 0 || size >= threshold) {
+		br := bufio.NewReaderSize(r, sniffLength)
+		// Peek returns what is available on a short read; its error surfaces when decoding.
+		head, _ := br.Peek(sniffLength)
+		head = bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n")
+		if bytes.HasPrefix(head, []byte("{")) {
+			return spec{stream: br}, nil
+		}
+		r = br
+	}
+	if size < 0 {
+		data, err := io.ReadAll(r)
+		return spec{data: data}, err
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, size+bytes.MinRead))
+	_, err := buf.ReadFrom(r)
+	return spec{data: buf.Bytes()}, err
+}
+
+// looksLikeOpenAPI reports whether the document looks like an OpenAPI document; see
+// looksLikeOpenAPIDocument. A streamed document is a JSON object and always does
+// until load checks its openapi or swagger field.
+func (s spec) looksLikeOpenAPI() bool {
+	return s.stream != nil || looksLikeOpenAPIDocument(s.data)
+}
+
+// load parses the document and resolves its references, as loader.LoadFromData does.
+func (s spec) load(loader *openapi3.Loader) (*openapi3.T, error) {
+	if s.stream == nil {
+		return loader.LoadFromData(s.data)
+	}
+	if s.closer != nil {
+		defer s.closer.Close()
+	}
+	// A leading byte order mark is not valid JSON.
+	if bom, err := s.stream.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		_, _ = s.stream.Discard(3)
+	}
+	doc := &openapi3.T{}
+	if err := json.NewDecoder(s.stream).Decode(doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON document: %w", err)
+	}
+	// Unknown fields such as swagger are kept in Extensions.
+	if _, swagger := doc.Extensions["swagger"]; doc.OpenAPI == "" && !swagger {
+		return nil, errors.New("document is not an OpenAPI document: it has no openapi or swagger field")
+	}
+	if err := loader.ResolveRefsIn(doc, nil); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
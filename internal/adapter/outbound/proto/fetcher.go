@@ -33,16 +33,19 @@ func (f *SchemaFetcher) Fetch(ctx context.Context, src string) (domain.APISchema
 	log := f.logger.With(slog.String("source", src))
 	log.Info("Fetching .proto schema")
 
-	// Validate that the URL ends with .proto
-	if !strings.HasSuffix(src, ".proto") {
-		return domain.APISchema{}, fmt.Errorf("source must be a .proto file, got: %s", src)
+	// The gRPC server may be given in the URL fragment (file://schema.proto#grpc://host:50051)
+	location, server := splitServerFragment(src)
+
+	// Validate that the URL is a .proto file or a compiled descriptor set
+	if !strings.HasSuffix(location, ".proto") && !isDescriptorSet(location) {
+		return domain.APISchema{}, fmt.Errorf("source must be a .proto file or a .pb/.desc descriptor set, got: %s", src)
 	}
 
 	var data []byte
 	var err error
 
 	// Parse URL to check scheme
-	parsedURL, err := url.Parse(src)
+	parsedURL, err := url.Parse(location)
 	if err != nil {
 		log.Error("Failed to parse URL", slog.Any("error", err))
 		return domain.APISchema{}, fmt.Errorf("failed to parse URL: %w", err)
@@ -61,7 +64,7 @@ func (f *SchemaFetcher) Fetch(ctx context.Context, src string) (domain.APISchema
 	} else {
 		// Handle HTTP/HTTPS URLs
 		// Create HTTP request with context
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
 		if err != nil {
 			log.Error("Failed to create HTTP request", slog.Any("error", err))
 			return domain.APISchema{}, fmt.Errorf("failed to create request: %w", err)
@@ -92,13 +95,16 @@ func (f *SchemaFetcher) Fetch(ctx context.Context, src string) (domain.APISchema
 	log.Info("Successfully fetched .proto file", slog.Int("size", len(data)))
 
 	// Return the schema with raw proto content
-	// The ParsedData will be populated by the generator after parsing
-	return domain.APISchema{
-		Source:     src,
-		Type:       domain.SchemaTypeProto,
-		RawData:    data,
-		ParsedData: nil, // Will be parsed by the generator
-	}, nil
+	// The descriptors will be parsed by the generator
+	schema := domain.APISchema{
+		Source:  src,
+		Type:    domain.SchemaTypeProto,
+		RawData: data,
+	}
+	if server != "" {
+		schema.ParsedData = map[string]string{"server": server}
+	}
+	return schema, nil
 }
 
 // FetchWithConfig fetches a .proto file with custom headers.
@@ -106,16 +112,22 @@ func (f *SchemaFetcher) FetchWithConfig(ctx context.Context, config usecase.Sche
 	log := f.logger.With(slog.String("source", config.URL))
 	log.Info("Fetching .proto schema with config", slog.Int("header_count", len(config.Headers)))
 
-	// Validate that the URL ends with .proto
-	if !strings.HasSuffix(config.URL, ".proto") {
-		return domain.APISchema{}, fmt.Errorf("source must be a .proto file, got: %s", config.URL)
+	// The server YAML field takes precedence over one given in the URL fragment
+	location, server := splitServerFragment(config.URL)
+	if config.Server != "" {
+		server = config.Server
+	}
+
+	// Validate that the URL is a .proto file or a compiled descriptor set
+	if !strings.HasSuffix(location, ".proto") && !isDescriptorSet(location) {
+		return domain.APISchema{}, fmt.Errorf("source must be a .proto file or a .pb/.desc descriptor set, got: %s", config.URL)
 	}
 
 	var data []byte
 	var err error
 
 	// Parse URL to check scheme
-	parsedURL, err := url.Parse(config.URL)
+	parsedURL, err := url.Parse(location)
 	if err != nil {
 		log.Error("Failed to parse URL", slog.Any("error", err))
 		return domain.APISchema{}, fmt.Errorf("failed to parse URL: %w", err)
@@ -134,7 +146,7 @@ func (f *SchemaFetcher) FetchWithConfig(ctx context.Context, config usecase.Sche
 	} else {
 		// Handle HTTP/HTTPS URLs
 		// Create HTTP request with context
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, config.URL, nil)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
 		if err != nil {
 			log.Error("Failed to create HTTP request", slog.Any("error", err))
 			return domain.APISchema{}, fmt.Errorf("failed to create request: %w", err)
@@ -178,12 +190,18 @@ func (f *SchemaFetcher) FetchWithConfig(ctx context.Context, config usecase.Sche
 	// Note: For .proto files, we need the server field from config
 	// Store the server in ParsedData for now (will be properly structured later)
 	parsedData := map[string]string{}
-	if config.Server != "" {
-		parsedData["server"] = config.Server
+	if server != "" {
+		parsedData["server"] = server
 	}
 	if config.Mode != "" {
 		parsedData["mode"] = config.Mode
 	}
+	if config.ConnectProtocolVersion != "" {
+		parsedData["connect_protocol_version"] = config.ConnectProtocolVersion
+	}
+	if config.AcceptEncoding != "" {
+		parsedData["accept_encoding"] = config.AcceptEncoding
+	}
 
 	// Determine schema type based on configuration
 	schemaType := domain.SchemaTypeProto
@@ -198,3 +216,10 @@ func (f *SchemaFetcher) FetchWithConfig(ctx context.Context, config usecase.Sche
 		ParsedData: parsedData,
 	}, nil
 }
+
+// splitServerFragment separates a gRPC server endpoint given as the URL fragment
+// (e.g., "file:///schemas/greeter.proto#grpc://localhost:50051") from the schema location.
+func splitServerFragment(src string) (location, server string) {
+	location, server, _ = strings.Cut(src, "#")
+	return location, server
+}
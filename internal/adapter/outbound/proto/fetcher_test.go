@@ -0,0 +1,59 @@
+package proto_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	protoadapter "github.com/i2y/mcpizer/internal/adapter/outbound/proto"
+	"github.com/i2y/mcpizer/internal/domain"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+const greeterProto = `syntax = "proto3";
+package greeter.v1;
+
+message HelloRequest { string name = 1; }
+message HelloReply { string message = 1; }
+
+service Greeter {
+  rpc SayHello(HelloRequest) returns (HelloReply);
+}
+`
+
+func TestSchemaFetcher_Fetch_ServerFragment(t *testing.T) {
+	protoFile := filepath.Join(t.TempDir(), "greeter.proto")
+	require.NoError(t, os.WriteFile(protoFile, []byte(greeterProto), 0600))
+	fetcher := protoadapter.NewSchemaFetcher(http.DefaultClient, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	source := "file://" + protoFile + "#grpc://localhost:50051"
+
+	t.Run("fragment provides the server", func(t *testing.T) {
+		schema, err := fetcher.Fetch(context.Background(), source)
+		require.NoError(t, err)
+		assert.Equal(t, domain.SchemaTypeProto, schema.Type)
+		assert.Equal(t, []byte(greeterProto), schema.RawData)
+		assert.Equal(t, map[string]string{"server": "grpc://localhost:50051"}, schema.ParsedData)
+	})
+
+	t.Run("server field takes precedence over fragment", func(t *testing.T) {
+		schema, err := fetcher.FetchWithConfig(context.Background(), usecase.SchemaSourceConfig{
+			URL:    source,
+			Server: "grpc://greeter.internal:443",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"server": "grpc://greeter.internal:443"}, schema.ParsedData)
+	})
+
+	t.Run("no fragment leaves server unset", func(t *testing.T) {
+		schema, err := fetcher.Fetch(context.Background(), "file://"+protoFile)
+		require.NoError(t, err)
+		assert.Nil(t, schema.ParsedData)
+	})
+}
@@ -6,10 +6,12 @@ import (
 	"log/slog"
 	"strings"
 
+	"github.com/i2y/mcpizer/internal/adapter/outbound/protoschema"
 	"github.com/i2y/mcpizer/internal/domain"
 	"github.com/i2y/mcpizer/internal/usecase"
 	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/desc/protoparse"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
@@ -33,86 +35,187 @@ func (g *Generator) Generate(schema domain.APISchema) ([]domain.Tool, []usecase.
 	// Extract server URL and mode from ParsedData (temporary solution)
 	serverURL := ""
 	mode := "grpc" // default mode
+	var connectProtocolVersion, acceptEncoding string
 	if parsedData, ok := schema.ParsedData.(map[string]string); ok {
 		serverURL = parsedData["server"]
 		if m, ok := parsedData["mode"]; ok {
 			mode = m
 		}
+		connectProtocolVersion = parsedData["connect_protocol_version"]
+		acceptEncoding = parsedData["accept_encoding"]
 	}
 	if serverURL == "" {
 		return nil, nil, fmt.Errorf("server URL is required for .proto schemas")
 	}
 
-	// Parse the .proto content
-	parser := protoparse.Parser{
-		Accessor: func(filename string) (io.ReadCloser, error) {
-			// For now, we only support single file parsing
-			if filename == "schema.proto" {
-				return io.NopCloser(strings.NewReader(string(schema.RawData))), nil
-			}
-			return nil, fmt.Errorf("import not supported: %s", filename)
-		},
+	var fileDescs []*desc.FileDescriptor
+	var err error
+	if isDescriptorSet(schema.Source) {
+		fileDescs, err = parseDescriptorSet(schema.RawData)
+		if err != nil {
+			log.Error("Failed to load FileDescriptorSet", slog.Any("error", err))
+			return nil, nil, err
+		}
+	} else {
+		fileDescs, err = parseProtoSource(schema.RawData)
+		if err != nil {
+			log.Error("Failed to parse .proto file", slog.Any("error", err))
+			return nil, nil, err
+		}
 	}
 
-	fileDescs, err := parser.ParseFiles("schema.proto")
-	if err != nil {
-		log.Error("Failed to parse .proto file", slog.Any("error", err))
-		return nil, nil, fmt.Errorf("failed to parse .proto file: %w", err)
+	// Generate tools for each service and method
+	var tools []domain.Tool
+	var invocationDetails []usecase.InvocationDetails
+
+	for _, fileDesc := range fileDescs {
+		if len(fileDesc.GetServices()) == 0 {
+			continue
+		}
+		log.Info("Parsed .proto file", slog.String("file", fileDesc.GetName()), slog.String("package", fileDesc.GetPackage()))
+		descriptorSet := desc.ToFileDescriptorSet(fileDesc)
+
+		for _, service := range fileDesc.GetServices() {
+			serviceName := service.GetName()
+			log.Debug("Processing service", slog.String("service", serviceName))
+
+			for _, method := range service.GetMethods() {
+				methodName := method.GetName()
+				fullMethodName := fmt.Sprintf("/%s/%s", service.GetFullyQualifiedName(), methodName)
+
+				// Create tool definition
+				outputSchema := g.messageToJSONSchema(method.GetOutputType(), map[string]bool{})
+				tool := domain.Tool{
+					Name:         fmt.Sprintf("%s_%s", serviceName, methodName),
+					Title:        fmt.Sprintf("%s.%s", serviceName, methodName),
+					Description:  g.generateMethodDescription(method),
+					InputSchema:  g.generateInputSchema(method),
+					OutputSchema: &outputSchema,
+				}
+
+				// Create invocation details
+				invocationType := "grpc"
+				if mode == "http" || mode == "connect" {
+					invocationType = "connect"
+				}
+
+				details := usecase.InvocationDetails{
+					Type:       invocationType,
+					Server:     serverURL,
+					Method:     fullMethodName,
+					InputType:  method.GetInputType().GetFullyQualifiedName(),
+					OutputType: method.GetOutputType().GetFullyQualifiedName(),
+					// Store the file and its dependencies so the invoker needs no reflection
+					FileDescriptor: descriptorSet,
+				}
+				if invocationType == "connect" {
+					details.ConnectProtocolVersion = connectProtocolVersion
+					details.AcceptEncoding = acceptEncoding
+				}
+
+				tools = append(tools, tool)
+				invocationDetails = append(invocationDetails, details)
+
+				log.Debug("Generated tool for method",
+					slog.String("tool_name", tool.Name),
+					slog.String("method", fullMethodName))
+			}
+		}
 	}
 
-	if len(fileDescs) == 0 {
-		return nil, nil, fmt.Errorf("no file descriptors found in .proto file")
+	log.Info("Successfully generated tools from .proto", slog.Int("tool_count", len(tools)))
+	return tools, invocationDetails, nil
+}
+
+// isDescriptorSet reports whether src names a compiled FileDescriptorSet
+// (protoc --descriptor_set_out) rather than .proto source. A trailing @ref and a
+// #server fragment are ignored.
+func isDescriptorSet(src string) bool {
+	src, _ = splitServerFragment(src)
+	if idx := strings.LastIndex(src, "@"); idx > strings.LastIndex(src, "/") {
+		src = src[:idx]
 	}
+	return strings.HasSuffix(src, ".pb") || strings.HasSuffix(src, ".desc")
+}
 
-	fileDesc := fileDescs[0]
-	log.Info("Parsed .proto file", slog.String("package", fileDesc.GetPackage()))
+// fieldBehaviorProto is a minimal copy of google/api/field_behavior.proto so
+// that .proto sources annotating fields with (google.api.field_behavior) parse
+// without the googleapis sources on disk.
+const fieldBehaviorProto = `syntax = "proto3";
 
-	// Generate tools for each service and method
-	var tools []domain.Tool
-	var invocationDetails []usecase.InvocationDetails
+package google.api;
 
-	for _, service := range fileDesc.GetServices() {
-		serviceName := service.GetName()
-		log.Debug("Processing service", slog.String("service", serviceName))
+import "google/protobuf/descriptor.proto";
 
-		for _, method := range service.GetMethods() {
-			methodName := method.GetName()
-			fullMethodName := fmt.Sprintf("/%s.%s/%s", fileDesc.GetPackage(), serviceName, methodName)
+extend google.protobuf.FieldOptions {
+  repeated google.api.FieldBehavior field_behavior = 1052 [packed = false];
+}
 
-			// Create tool definition
-			tool := domain.Tool{
-				Name:        fmt.Sprintf("%s_%s", serviceName, methodName),
-				Description: g.generateMethodDescription(method),
-				InputSchema: g.generateInputSchema(method),
-			}
+enum FieldBehavior {
+  FIELD_BEHAVIOR_UNSPECIFIED = 0;
+  OPTIONAL = 1;
+  REQUIRED = 2;
+  OUTPUT_ONLY = 3;
+  INPUT_ONLY = 4;
+  IMMUTABLE = 5;
+  UNORDERED_LIST = 6;
+  NON_EMPTY_DEFAULT = 7;
+  IDENTIFIER = 8;
+}
+`
 
-			// Create invocation details
-			invocationType := "grpc"
-			if mode == "http" || mode == "connect" {
-				invocationType = "connect"
+// parseProtoSource parses a single self-contained .proto file.
+func parseProtoSource(data []byte) ([]*desc.FileDescriptor, error) {
+	parser := protoparse.Parser{
+		// Source info carries the comments used for tool and parameter descriptions.
+		IncludeSourceCodeInfo: true,
+		Accessor: func(filename string) (io.ReadCloser, error) {
+			// For now, we only support single file parsing plus the
+			// google.api annotations that affect the generated schema.
+			switch filename {
+			case "schema.proto":
+				return io.NopCloser(strings.NewReader(string(data))), nil
+			case "google/api/field_behavior.proto":
+				return io.NopCloser(strings.NewReader(fieldBehaviorProto)), nil
 			}
+			return nil, fmt.Errorf("import not supported: %s", filename)
+		},
+	}
 
-			details := usecase.InvocationDetails{
-				Type:       invocationType,
-				Server:     serverURL,
-				Method:     fullMethodName,
-				InputType:  method.GetInputType().GetFullyQualifiedName(),
-				OutputType: method.GetOutputType().GetFullyQualifiedName(),
-				// Store the file descriptor for later use by the invoker
-				FileDescriptor: fileDesc.AsFileDescriptorProto(),
-			}
+	fileDescs, err := parser.ParseFiles("schema.proto")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse .proto file: %w", err)
+	}
+	if len(fileDescs) == 0 {
+		return nil, fmt.Errorf("no file descriptors found in .proto file")
+	}
+	return fileDescs, nil
+}
 
-			tools = append(tools, tool)
-			invocationDetails = append(invocationDetails, details)
+// parseDescriptorSet loads a binary FileDescriptorSet. The set is self-contained
+// (protoc --include_imports), so every referenced message and enum resolves.
+func parseDescriptorSet(data []byte) ([]*desc.FileDescriptor, error) {
+	var fds descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &fds); err != nil {
+		return nil, fmt.Errorf("failed to decode FileDescriptorSet: %w", err)
+	}
+	if len(fds.GetFile()) == 0 {
+		return nil, fmt.Errorf("no file descriptors found in FileDescriptorSet")
+	}
 
-			log.Debug("Generated tool for method",
-				slog.String("tool_name", tool.Name),
-				slog.String("method", fullMethodName))
-		}
+	byName, err := desc.CreateFileDescriptorsFromSet(&fds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve FileDescriptorSet (was it built with --include_imports?): %w", err)
 	}
 
-	log.Info("Successfully generated tools from .proto", slog.Int("tool_count", len(tools)))
-	return tools, invocationDetails, nil
+	// Keep the order of the set for deterministic tool generation.
+	fileDescs := make([]*desc.FileDescriptor, 0, len(byName))
+	for _, fdp := range fds.GetFile() {
+		if fd, ok := byName[fdp.GetName()]; ok {
+			fileDescs = append(fileDescs, fd)
+		}
+	}
+	return fileDescs, nil
 }
 
 // generateMethodDescription creates a description for a gRPC method.
@@ -129,63 +232,50 @@ func (g *Generator) generateMethodDescription(method *desc.MethodDescriptor) str
 
 // generateInputSchema creates a JSON schema for the method's input message.
 func (g *Generator) generateInputSchema(method *desc.MethodDescriptor) domain.JSONSchemaProps {
-	inputType := method.GetInputType()
-
-	// Generate JSON schema from the protobuf message descriptor
-	properties := make(map[string]domain.JSONSchemaProps)
-	required := []string{}
-
-	for _, field := range inputType.GetFields() {
-		fieldName := field.GetJSONName()
-		if fieldName == "" {
-			fieldName = field.GetName()
-		}
-
-		prop := g.fieldToJSONSchema(field)
-		properties[fieldName] = prop
-
-		// In proto3, all fields are optional by default
-		// Only mark as required if it has specific field options
-		if field.IsRequired() {
-			required = append(required, fieldName)
-		}
-	}
-
-	return domain.JSONSchemaProps{
-		Type:       "object",
-		Properties: properties,
-		Required:   required,
-	}
+	return g.messageToJSONSchema(method.GetInputType(), map[string]bool{})
 }
 
 // fieldToJSONSchema converts a protobuf field descriptor to JSON schema.
-func (g *Generator) fieldToJSONSchema(field *desc.FieldDescriptor) domain.JSONSchemaProps {
-	schema := domain.JSONSchemaProps{}
-
-	// Handle repeated fields
-	if field.IsRepeated() {
-		schema.Type = "array"
-		itemSchema := g.scalarTypeToJSONSchema(field.GetType())
-		schema.Items = &itemSchema
-		return schema
-	}
-
-	// Handle maps
+// visiting holds the messages on the current path to stop on recursive types.
+func (g *Generator) fieldToJSONSchema(field *desc.FieldDescriptor, visiting map[string]bool) domain.JSONSchemaProps {
+	// Handle maps (map fields are also repeated, so check them first)
 	if field.IsMap() {
-		schema.Type = "object"
 		// For maps, we use additionalProperties in JSON Schema
 		// This is a simplified implementation
-		return schema
+		return domain.JSONSchemaProps{Type: "object"}
 	}
 
-	// Handle message types
-	if field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
-		msgType := field.GetMessageType()
-		return g.messageToJSONSchema(msgType)
+	// Handle repeated fields
+	if field.IsRepeated() {
+		itemSchema := g.singularFieldToJSONSchema(field, visiting)
+		return domain.JSONSchemaProps{
+			Type:  "array",
+			Items: &itemSchema,
+		}
 	}
 
-	// Handle scalar types
-	return g.scalarTypeToJSONSchema(field.GetType())
+	return g.singularFieldToJSONSchema(field, visiting)
+}
+
+// singularFieldToJSONSchema converts the element type of a field to JSON schema.
+func (g *Generator) singularFieldToJSONSchema(field *desc.FieldDescriptor, visiting map[string]bool) domain.JSONSchemaProps {
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		if schema, ok := protoschema.WellKnownTypeSchema(field.GetMessageType().GetFullyQualifiedName()); ok {
+			return schema
+		}
+		return g.messageToJSONSchema(field.GetMessageType(), visiting)
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		schema := domain.JSONSchemaProps{Type: "string"}
+		if enumType := field.GetEnumType(); enumType != nil {
+			for _, value := range enumType.GetValues() {
+				schema.Enum = append(schema.Enum, value.GetName())
+			}
+		}
+		return schema
+	default:
+		return g.scalarTypeToJSONSchema(field.GetType())
+	}
 }
 
 // scalarTypeToJSONSchema converts protobuf scalar types to JSON schema types.
@@ -220,8 +310,7 @@ func (g *Generator) scalarTypeToJSONSchema(protoType descriptorpb.FieldDescripto
 		}
 
 	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
-		// For enums, we could extract the allowed values
-		// For now, treat as string
+		// Enum values are resolved in singularFieldToJSONSchema
 		return domain.JSONSchemaProps{Type: "string"}
 
 	default:
@@ -231,7 +320,15 @@ func (g *Generator) scalarTypeToJSONSchema(protoType descriptorpb.FieldDescripto
 }
 
 // messageToJSONSchema converts a protobuf message descriptor to JSON schema.
-func (g *Generator) messageToJSONSchema(msg *desc.MessageDescriptor) domain.JSONSchemaProps {
+// A message already on the current path (a recursive type) becomes a bare object.
+func (g *Generator) messageToJSONSchema(msg *desc.MessageDescriptor, visiting map[string]bool) domain.JSONSchemaProps {
+	name := msg.GetFullyQualifiedName()
+	if visiting[name] {
+		return domain.JSONSchemaProps{Type: "object"}
+	}
+	visiting[name] = true
+	defer delete(visiting, name)
+
 	properties := make(map[string]domain.JSONSchemaProps)
 	required := []string{}
 
@@ -241,10 +338,13 @@ func (g *Generator) messageToJSONSchema(msg *desc.MessageDescriptor) domain.JSON
 			fieldName = field.GetName()
 		}
 
-		prop := g.fieldToJSONSchema(field)
+		prop := g.fieldToJSONSchema(field, visiting)
+		prop.Description = strings.TrimSpace(field.GetSourceInfo().GetLeadingComments())
 		properties[fieldName] = prop
 
-		if field.IsRequired() {
+		// In proto3, all fields are optional by default; only proto2 required
+		// fields and (google.api.field_behavior) = REQUIRED are marked required.
+		if field.IsRequired() || protoschema.HasRequiredBehavior(field.GetFieldOptions()) {
 			required = append(required, fieldName)
 		}
 	}
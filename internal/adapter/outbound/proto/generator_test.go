@@ -0,0 +1,234 @@
+package proto_test
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	protoadapter "github.com/i2y/mcpizer/internal/adapter/outbound/proto"
+	"github.com/i2y/mcpizer/internal/domain"
+)
+
+// petDescriptorSet builds a FileDescriptorSet equivalent to:
+//
+//	package pets.v1;
+//	enum Kind { KIND_UNSPECIFIED = 0; KIND_DOG = 1; }
+//	message Pet { string name = 1; Kind kind = 2; repeated Pet children = 3; }
+//	message GetPetRequest { string id = 1; }
+//	service PetService { rpc GetPet(GetPetRequest) returns (Pet); }
+func petDescriptorSet(t *testing.T) []byte {
+	t.Helper()
+
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	fds := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("pets/v1/pets.proto"),
+			Package: proto.String("pets.v1"),
+			Syntax:  proto.String("proto3"),
+			EnumType: []*descriptorpb.EnumDescriptorProto{{
+				Name: proto.String("Kind"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("KIND_UNSPECIFIED"), Number: proto.Int32(0)},
+					{Name: proto.String("KIND_DOG"), Number: proto.Int32(1)},
+				},
+			}},
+			MessageType: []*descriptorpb.DescriptorProto{
+				{
+					Name: proto.String("Pet"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{Name: proto.String("name"), JsonName: proto.String("name"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+						{Name: proto.String("kind"), JsonName: proto.String("kind"), Number: proto.Int32(2), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(), TypeName: proto.String(".pets.v1.Kind")},
+						{Name: proto.String("children"), JsonName: proto.String("children"), Number: proto.Int32(3), Label: repeated, Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: proto.String(".pets.v1.Pet")},
+					},
+				},
+				{
+					Name: proto.String("GetPetRequest"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{Name: proto.String("id"), JsonName: proto.String("id"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+					},
+				},
+			},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("PetService"),
+				Method: []*descriptorpb.MethodDescriptorProto{{
+					Name:       proto.String("GetPet"),
+					InputType:  proto.String(".pets.v1.GetPetRequest"),
+					OutputType: proto.String(".pets.v1.Pet"),
+				}},
+			}},
+		}},
+	}
+
+	data, err := proto.Marshal(fds)
+	require.NoError(t, err)
+	return data
+}
+
+func TestGenerator_Generate_DescriptorSet(t *testing.T) {
+	generator := protoadapter.NewGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tools, details, err := generator.Generate(domain.APISchema{
+		Source:     "file:///etc/mcpizer/pets.pb",
+		Type:       domain.SchemaTypeProto,
+		RawData:    petDescriptorSet(t),
+		ParsedData: map[string]string{"server": "grpc://pets.example.com:50051"},
+	})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	require.Len(t, details, 1)
+
+	tool := tools[0]
+	assert.Equal(t, "PetService_GetPet", tool.Name)
+	assert.Equal(t, domain.JSONSchemaProps{Type: "string"}, tool.InputSchema.Properties["id"])
+
+	require.NotNil(t, tool.OutputSchema)
+	output := *tool.OutputSchema
+	assert.Equal(t, "string", output.Properties["name"].Type)
+	assert.Equal(t, []interface{}{"KIND_UNSPECIFIED", "KIND_DOG"}, output.Properties["kind"].Enum)
+	children := output.Properties["children"]
+	assert.Equal(t, "array", children.Type)
+	require.NotNil(t, children.Items)
+	assert.Equal(t, domain.JSONSchemaProps{Type: "object"}, *children.Items, "recursive message stops at a bare object")
+
+	assert.Equal(t, "grpc", details[0].Type)
+	assert.Equal(t, "grpc://pets.example.com:50051", details[0].Server)
+	assert.Equal(t, "/pets.v1.PetService/GetPet", details[0].Method)
+	assert.Equal(t, "pets.v1.GetPetRequest", details[0].InputType)
+	assert.Equal(t, "pets.v1.Pet", details[0].OutputType)
+}
+
+func TestGenerator_Generate_InvalidDescriptorSet(t *testing.T) {
+	generator := protoadapter.NewGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, _, err := generator.Generate(domain.APISchema{
+		Source:     "file:///etc/mcpizer/pets.desc",
+		Type:       domain.SchemaTypeProto,
+		RawData:    []byte("not a descriptor set"),
+		ParsedData: map[string]string{"server": "grpc://pets.example.com:50051"},
+	})
+	assert.ErrorContains(t, err, "FileDescriptorSet")
+}
+
+func TestGenerator_Generate_FieldBehaviorRequired(t *testing.T) {
+	generator := protoadapter.NewGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	source := `syntax = "proto3";
+package books.v1;
+
+import "google/api/field_behavior.proto";
+
+message CreateBookRequest {
+  string parent = 1 [(google.api.field_behavior) = REQUIRED];
+  string title = 2;
+  string book_id = 3 [(google.api.field_behavior) = OPTIONAL];
+}
+
+message Book { string name = 1; }
+
+service BookService {
+  rpc CreateBook(CreateBookRequest) returns (Book);
+}
+`
+
+	tools, _, err := generator.Generate(domain.APISchema{
+		Source:     "file:///etc/mcpizer/books.proto",
+		Type:       domain.SchemaTypeProto,
+		RawData:    []byte(source),
+		ParsedData: map[string]string{"server": "grpc://books.example.com:50051"},
+	})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, []string{"parent"}, tools[0].InputSchema.Required)
+}
+
+func TestGenerator_Generate_WellKnownTypes(t *testing.T) {
+	generator := protoadapter.NewGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	source := `syntax = "proto3";
+package events.v1;
+
+import "google/protobuf/duration.proto";
+import "google/protobuf/empty.proto";
+import "google/protobuf/struct.proto";
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+
+message ScheduleRequest {
+  google.protobuf.Timestamp start_time = 1;
+  google.protobuf.Duration length = 2;
+  google.protobuf.Struct metadata = 3;
+  google.protobuf.Int32Value priority = 4;
+  repeated google.protobuf.Timestamp reminders = 5;
+}
+
+service EventService {
+  rpc Schedule(ScheduleRequest) returns (google.protobuf.Empty);
+}
+`
+
+	tools, _, err := generator.Generate(domain.APISchema{
+		Source:     "file:///etc/mcpizer/events.proto",
+		Type:       domain.SchemaTypeProto,
+		RawData:    []byte(source),
+		ParsedData: map[string]string{"server": "grpc://events.example.com:50051"},
+	})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+
+	props := tools[0].InputSchema.Properties
+	assert.Equal(t, domain.JSONSchemaProps{Type: "string", Format: "date-time"}, props["startTime"])
+	assert.Equal(t, domain.JSONSchemaProps{Type: "string", Format: "duration"}, props["length"])
+	assert.Equal(t, domain.JSONSchemaProps{Type: "object"}, props["metadata"])
+	assert.Equal(t, domain.JSONSchemaProps{Type: "integer"}, props["priority"])
+	require.NotNil(t, props["reminders"].Items)
+	assert.Equal(t, domain.JSONSchemaProps{Type: "string", Format: "date-time"}, *props["reminders"].Items)
+
+	require.NotNil(t, tools[0].OutputSchema)
+	assert.Equal(t, "object", tools[0].OutputSchema.Type)
+	assert.Empty(t, tools[0].OutputSchema.Properties)
+}
+
+func TestGenerator_Generate_FieldComments(t *testing.T) {
+	generator := protoadapter.NewGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	source := `syntax = "proto3";
+package books.v1;
+
+message GetBookRequest {
+  // The resource name of the book, e.g. "shelves/1/books/2".
+  string name = 1;
+  string view = 2;
+}
+
+message Book {
+  // Title shown on the cover.
+  string title = 1;
+}
+
+service BookService {
+  rpc GetBook(GetBookRequest) returns (Book);
+}
+`
+
+	tools, _, err := generator.Generate(domain.APISchema{
+		Source:     "file:///etc/mcpizer/books.proto",
+		Type:       domain.SchemaTypeProto,
+		RawData:    []byte(source),
+		ParsedData: map[string]string{"server": "grpc://books.example.com:50051"},
+	})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+
+	props := tools[0].InputSchema.Properties
+	assert.Equal(t, `The resource name of the book, e.g. "shelves/1/books/2".`, props["name"].Description)
+	assert.Empty(t, props["view"].Description, "uncommented fields have no description")
+
+	require.NotNil(t, tools[0].OutputSchema)
+	assert.Equal(t, "Title shown on the cover.", tools[0].OutputSchema.Properties["title"].Description)
+}
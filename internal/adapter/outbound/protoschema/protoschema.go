@@ -0,0 +1,130 @@
+// Package protoschema holds helpers for deriving JSON schemas from protobuf
+// descriptors, shared by the .proto and gRPC reflection generators.
+package protoschema
+
+import (
+	"strings"
+
+	"github.com/i2y/mcpizer/internal/domain"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// fieldBehaviorNumber is the field number of the google.api.field_behavior extension.
+const fieldBehaviorNumber protowire.Number = 1052
+
+// fieldBehaviorRequired is google.api.FieldBehavior.REQUIRED.
+const fieldBehaviorRequired = 2
+
+// JSONName returns the JSON name of a field, the name tools advertise for it:
+// the descriptor's json_name, or the lowerCamelCase name protoc would derive
+// (e.g. "user_id" becomes "userId"). grpcurl and Connect servers accept it in
+// requests and use it in responses, so tool inputs and outputs match.
+func JSONName(field *descriptorpb.FieldDescriptorProto) string {
+	if name := field.GetJsonName(); name != "" {
+		return name
+	}
+	var b strings.Builder
+	upperNext := false
+	for _, r := range field.GetName() {
+		switch {
+		case r == '_':
+			upperNext = true
+		case upperNext && r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+			upperNext = false
+		default:
+			b.WriteRune(r)
+			upperNext = false
+		}
+	}
+	return b.String()
+}
+
+// HasRequiredBehavior reports whether the field options carry
+// `(google.api.field_behavior) = REQUIRED`. The options are inspected on the
+// wire so it works whether or not the extension was resolved when the
+// descriptor was built (reflection responses usually leave it as unknown bytes).
+func HasRequiredBehavior(opts *descriptorpb.FieldOptions) bool {
+	if opts == nil {
+		return false
+	}
+	raw, err := proto.Marshal(opts)
+	if err != nil {
+		return false
+	}
+
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			return false
+		}
+		raw = raw[n:]
+
+		if num == fieldBehaviorNumber {
+			switch typ {
+			case protowire.VarintType:
+				v, m := protowire.ConsumeVarint(raw)
+				if m < 0 {
+					return false
+				}
+				if v == fieldBehaviorRequired {
+					return true
+				}
+			case protowire.BytesType:
+				packed, m := protowire.ConsumeBytes(raw)
+				if m < 0 {
+					return false
+				}
+				for len(packed) > 0 {
+					v, k := protowire.ConsumeVarint(packed)
+					if k < 0 {
+						return false
+					}
+					if v == fieldBehaviorRequired {
+						return true
+					}
+					packed = packed[k:]
+				}
+			}
+		}
+
+		m := protowire.ConsumeFieldValue(num, typ, raw)
+		if m < 0 {
+			return false
+		}
+		raw = raw[m:]
+	}
+	return false
+}
+
+// wellKnownTypes maps google.protobuf well-known types to the JSON schema of
+// their canonical proto3 JSON encoding.
+var wellKnownTypes = map[string]domain.JSONSchemaProps{
+	"google.protobuf.Timestamp": {Type: "string", Format: "date-time"},
+	"google.protobuf.Duration":  {Type: "string", Format: "duration"},
+	"google.protobuf.FieldMask": {Type: "string"},
+	"google.protobuf.Struct":    {Type: "object"},
+	"google.protobuf.Value":     {Type: "object"},
+	"google.protobuf.ListValue": {Type: "array"},
+	"google.protobuf.Any":       {Type: "object"},
+	"google.protobuf.Empty":     {Type: "object", Properties: map[string]domain.JSONSchemaProps{}},
+
+	"google.protobuf.DoubleValue": {Type: "number"},
+	"google.protobuf.FloatValue":  {Type: "number"},
+	"google.protobuf.Int64Value":  {Type: "integer"},
+	"google.protobuf.UInt64Value": {Type: "integer"},
+	"google.protobuf.Int32Value":  {Type: "integer"},
+	"google.protobuf.UInt32Value": {Type: "integer"},
+	"google.protobuf.BoolValue":   {Type: "boolean"},
+	"google.protobuf.StringValue": {Type: "string"},
+	"google.protobuf.BytesValue":  {Type: "string", Format: "byte"},
+}
+
+// WellKnownTypeSchema returns the schema for a google.protobuf well-known
+// message type. fullName may carry the leading dot used in descriptor type names.
+func WellKnownTypeSchema(fullName string) (domain.JSONSchemaProps, bool) {
+	schema, ok := wellKnownTypes[strings.TrimPrefix(fullName, ".")]
+	return schema, ok
+}
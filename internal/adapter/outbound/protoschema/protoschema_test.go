@@ -0,0 +1,100 @@
+package protoschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/protoschema"
+	"github.com/i2y/mcpizer/internal/domain"
+)
+
+// fieldOptions returns options carrying the field_behavior extension as unknown
+// bytes, which is how descriptors obtained via reflection present it.
+func fieldOptions(packed bool, behaviors ...uint64) *descriptorpb.FieldOptions {
+	var raw []byte
+	if packed {
+		var values []byte
+		for _, b := range behaviors {
+			values = protowire.AppendVarint(values, b)
+		}
+		raw = protowire.AppendTag(raw, 1052, protowire.BytesType)
+		raw = protowire.AppendBytes(raw, values)
+	} else {
+		for _, b := range behaviors {
+			raw = protowire.AppendTag(raw, 1052, protowire.VarintType)
+			raw = protowire.AppendVarint(raw, b)
+		}
+	}
+	opts := &descriptorpb.FieldOptions{}
+	opts.ProtoReflect().SetUnknown(raw)
+	return opts
+}
+
+func TestHasRequiredBehavior(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *descriptorpb.FieldOptions
+		want bool
+	}{
+		{name: "nil options", opts: nil},
+		{name: "no behaviors", opts: &descriptorpb.FieldOptions{}},
+		{name: "required", opts: fieldOptions(false, 2), want: true},
+		{name: "output only", opts: fieldOptions(false, 3)},
+		{name: "immutable and required", opts: fieldOptions(false, 5, 2), want: true},
+		{name: "packed required", opts: fieldOptions(true, 1, 2), want: true},
+		{name: "packed optional", opts: fieldOptions(true, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, protoschema.HasRequiredBehavior(tt.opts))
+		})
+	}
+}
+
+func TestWellKnownTypeSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		typeName string
+		want     domain.JSONSchemaProps
+		ok       bool
+	}{
+		{name: "timestamp", typeName: "google.protobuf.Timestamp", want: domain.JSONSchemaProps{Type: "string", Format: "date-time"}, ok: true},
+		{name: "descriptor type name", typeName: ".google.protobuf.Timestamp", want: domain.JSONSchemaProps{Type: "string", Format: "date-time"}, ok: true},
+		{name: "duration", typeName: ".google.protobuf.Duration", want: domain.JSONSchemaProps{Type: "string", Format: "duration"}, ok: true},
+		{name: "wrapper", typeName: ".google.protobuf.BoolValue", want: domain.JSONSchemaProps{Type: "boolean"}, ok: true},
+		{name: "user message", typeName: ".pets.v1.Pet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := protoschema.WellKnownTypeSchema(tt.typeName)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJSONName(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    *descriptorpb.FieldDescriptorProto
+		wantName string
+	}{
+		{name: "json_name set", field: &descriptorpb.FieldDescriptorProto{Name: proto.String("user_id"), JsonName: proto.String("userId")}, wantName: "userId"},
+		{name: "custom json_name", field: &descriptorpb.FieldDescriptorProto{Name: proto.String("user_id"), JsonName: proto.String("uid")}, wantName: "uid"},
+		{name: "derived from snake case", field: &descriptorpb.FieldDescriptorProto{Name: proto.String("display_name_v2")}, wantName: "displayNameV2"},
+		{name: "already camel case", field: &descriptorpb.FieldDescriptorProto{Name: proto.String("pageSize")}, wantName: "pageSize"},
+		{name: "digit after underscore", field: &descriptorpb.FieldDescriptorProto{Name: proto.String("line_2")}, wantName: "line2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantName, protoschema.JSONName(tt.field))
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package tlsclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Options are the TLS settings of a schema source's HTTPS connections.
+type Options struct {
+	// CAFile is a PEM file of certificates trusted in addition to the system roots,
+	// e.g. a private CA signing internal hosts.
+	CAFile string
+	// InsecureSkipVerify disables certificate verification, e.g. for self-signed hosts.
+	InsecureSkipVerify bool
+}
+
+// Empty reports whether the options leave the default TLS settings unchanged.
+func (o Options) Empty() bool {
+	return o.CAFile == "" && !o.InsecureSkipVerify
+}
+
+// Client returns a copy of client whose transport applies opts. The client's
+// transport must be an *http.Transport (or nil), or one returned by H2C, so that
+// its other settings, such as timeouts and the outbound host policy, carry over.
+// The client is returned unchanged when opts is empty.
+func Client(client *http.Client, opts Options) (*http.Client, error) {
+	if opts.Empty() {
+		return client, nil
+	}
+	var transport http.RoundTripper
+	switch t := client.Transport.(type) {
+	case nil:
+		tlsTransport, err := withTLS(http.DefaultTransport.(*http.Transport), opts)
+		if err != nil {
+			return nil, err
+		}
+		transport = tlsTransport
+	case *http.Transport:
+		tlsTransport, err := withTLS(t, opts)
+		if err != nil {
+			return nil, err
+		}
+		transport = tlsTransport
+	case *h2cTransport:
+		// h2c requests are cleartext; only the https:// transport needs the settings.
+		tlsTransport, err := withTLS(t.base, opts)
+		if err != nil {
+			return nil, err
+		}
+		transport = &h2cTransport{h2c: t.h2c, base: tlsTransport}
+	default:
+		return nil, fmt.Errorf("per-source TLS settings need an *http.Transport, not %T", client.Transport)
+	}
+
+	wrapped := *client
+	wrapped.Transport = transport
+	return &wrapped, nil
+}
+
+// withTLS returns a copy of base applying opts.
+func withTLS(base *http.Transport, opts Options) (*http.Transport, error) {
+	transport := base.Clone()
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if transport.TLSClientConfig != nil {
+		tlsCfg = transport.TLSClientConfig.Clone()
+	}
+	if opts.CAFile != "" {
+		caPEM, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %w", opts.CAFile, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no valid certificates found in CA file %s", opts.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	tlsCfg.InsecureSkipVerify = opts.InsecureSkipVerify
+	transport.TLSClientConfig = tlsCfg
+	return transport, nil
+}
+
+// H2C returns a copy of client that speaks cleartext HTTP/2 with prior knowledge
+// (h2c) to http:// upstreams. https:// requests keep using the client's transport,
+// which negotiates HTTP/2 via ALPN as before.
+func H2C(client *http.Client) *http.Client {
+	base, ok := client.Transport.(*http.Transport)
+	if !ok || base == nil {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	h2c := base.Clone()
+	h2c.Protocols = new(http.Protocols)
+	h2c.Protocols.SetUnencryptedHTTP2(true)
+
+	wrapped := *client
+	wrapped.Transport = &h2cTransport{h2c: h2c, base: base}
+	return &wrapped
+}
+
+// h2cTransport sends http:// requests over h2c and all others over base.
+type h2cTransport struct {
+	h2c  *http.Transport
+	base *http.Transport
+}
+
+func (t *h2cTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "http" {
+		return t.h2c.RoundTrip(req)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// Cache hands out one client per Options, so that calls to a source reuse its
+// connections instead of dialing with a fresh transport each time.
+type Cache struct {
+	base    *http.Client
+	mu      sync.Mutex
+	clients map[Options]*http.Client
+}
+
+// NewCache returns a Cache deriving its clients from base.
+func NewCache(base *http.Client) *Cache {
+	return &Cache{base: base, clients: make(map[Options]*http.Client)}
+}
+
+// Client returns the client applying opts, building it on first use. The base
+// client is returned when opts is empty.
+func (c *Cache) Client(opts Options) (*http.Client, error) {
+	if opts.Empty() {
+		return c.base, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.clients[opts]; ok {
+		return client, nil
+	}
+	client, err := Client(c.base, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.clients[opts] = client
+	return client, nil
+}
@@ -0,0 +1,124 @@
+package tlsclient_test
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/tlsclient"
+)
+
+// writeCAFile writes the certificate of srv to a PEM file and returns its path.
+func writeCAFile(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0o600))
+	return caFile
+}
+
+func TestClient(t *testing.T) {
+	tlsSrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(tlsSrv.Close)
+	h2cSrv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	h2cSrv.Config.Protocols = new(http.Protocols)
+	h2cSrv.Config.Protocols.SetUnencryptedHTTP2(true) // h2c only, no HTTP/1.1
+	h2cSrv.Start()
+	t.Cleanup(h2cSrv.Close)
+	caFile := writeCAFile(t, tlsSrv)
+
+	tests := []struct {
+		name   string
+		client *http.Client
+		h2c    bool
+	}{
+		{name: "default transport", client: &http.Client{Timeout: 5 * time.Second}},
+		{name: "plain transport", client: &http.Client{Timeout: 5 * time.Second, Transport: http.DefaultTransport.(*http.Transport).Clone()}},
+		{name: "h2c transport", client: tlsclient.H2C(&http.Client{Timeout: 5 * time.Second}), h2c: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.client.Get(tlsSrv.URL)
+			require.Error(t, err, "the test server's certificate is not trusted by default")
+
+			for _, opts := range []tlsclient.Options{{CAFile: caFile}, {InsecureSkipVerify: true}} {
+				client, err := tlsclient.Client(tt.client, opts)
+				require.NoError(t, err)
+				assert.Equal(t, tt.client.Timeout, client.Timeout)
+				resp, err := client.Get(tlsSrv.URL)
+				require.NoError(t, err)
+				resp.Body.Close()
+				assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+				if tt.h2c {
+					// http:// requests still use h2c.
+					resp, err := client.Get(h2cSrv.URL)
+					require.NoError(t, err)
+					resp.Body.Close()
+					assert.Equal(t, "HTTP/2.0", resp.Header.Get("X-Proto"))
+				}
+			}
+		})
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	client := &http.Client{}
+	same, err := tlsclient.Client(client, tlsclient.Options{})
+	require.NoError(t, err)
+	assert.Same(t, client, same, "empty options keep the client")
+
+	invalidCA := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(invalidCA, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name   string
+		client *http.Client
+		opts   tlsclient.Options
+	}{
+		{name: "missing CA file", client: client, opts: tlsclient.Options{CAFile: filepath.Join(t.TempDir(), "missing.pem")}},
+		{name: "CA file without certificates", client: client, opts: tlsclient.Options{CAFile: invalidCA}},
+		{name: "unsupported transport", client: &http.Client{Transport: roundTripperFunc(nil)}, opts: tlsclient.Options{InsecureSkipVerify: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tlsclient.Client(tt.client, tt.opts)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCache_Client(t *testing.T) {
+	base := tlsclient.H2C(&http.Client{})
+	cache := tlsclient.NewCache(base)
+
+	client, err := cache.Client(tlsclient.Options{})
+	require.NoError(t, err)
+	assert.Same(t, base, client)
+
+	opts := tlsclient.Options{InsecureSkipVerify: true}
+	first, err := cache.Client(opts)
+	require.NoError(t, err)
+	second, err := cache.Client(opts)
+	require.NoError(t, err)
+	assert.Same(t, first, second, "clients are reused per options")
+	assert.NotSame(t, base, first)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
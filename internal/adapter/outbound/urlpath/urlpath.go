@@ -0,0 +1,33 @@
+package urlpath
+
+import "strings"
+
+// Join joins a base path and a request path into one URL path. Unlike path.Join
+// it never resolves "." or ".." segments and keeps a trailing slash when the last
+// non-empty argument has one, since some APIs route "/users/" differently from
+// "/users". Runs of slashes are collapsed, so the result never contains "//".
+// The result starts with "/" unless both arguments are empty.
+func Join(base, p string) string {
+	last := p
+	if last == "" {
+		last = base
+	}
+	if last == "" {
+		return ""
+	}
+
+	var segments []string
+	for _, part := range []string{base, p} {
+		for _, segment := range strings.Split(part, "/") {
+			if segment != "" {
+				segments = append(segments, segment)
+			}
+		}
+	}
+
+	joined := "/" + strings.Join(segments, "/")
+	if strings.HasSuffix(last, "/") && joined != "/" {
+		joined += "/"
+	}
+	return joined
+}
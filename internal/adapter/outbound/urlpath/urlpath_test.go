@@ -0,0 +1,39 @@
+package urlpath_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/urlpath"
+)
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		path string
+		want string
+	}{
+		{name: "base and path", base: "/api/v1", path: "/users", want: "/api/v1/users"},
+		{name: "trailing slash on path is kept", base: "/api/v1", path: "/users/", want: "/api/v1/users/"},
+		{name: "trailing slash on base is not doubled", base: "/api/v1/", path: "/users", want: "/api/v1/users"},
+		{name: "empty base", base: "", path: "/users", want: "/users"},
+		{name: "empty base keeps trailing slash", base: "", path: "/users/", want: "/users/"},
+		{name: "empty path", base: "/api/v1", path: "", want: "/api/v1"},
+		{name: "empty path keeps base trailing slash", base: "/api/v1/", path: "", want: "/api/v1/"},
+		{name: "both empty", base: "", path: "", want: ""},
+		{name: "root base", base: "/", path: "/users", want: "/users"},
+		{name: "root only", base: "/", path: "/", want: "/"},
+		{name: "missing leading slashes", base: "api", path: "users", want: "/api/users"},
+		{name: "repeated slashes collapsed", base: "/api//v1/", path: "//users//{id}", want: "/api/v1/users/{id}"},
+		{name: "dot segments untouched", base: "/api", path: "/./files/../x", want: "/api/./files/../x"},
+		{name: "connect method", base: "/prefix/", path: "/pkg.v1.Service/Method", want: "/prefix/pkg.v1.Service/Method"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, urlpath.Join(tt.base, tt.path))
+		})
+	}
+}
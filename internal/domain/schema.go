@@ -30,4 +30,7 @@ type APISchema struct {
 	// Example: *openapi3.T for OpenAPI. Use interface{} to keep domain clean,
 	// but requires type assertions downstream.
 	ParsedData interface{}
+	// ResponseStatuses are the source's preferred response statuses for output
+	// schemas (e.g., "202", "default"), tried before 200 and 201. OpenAPI only.
+	ResponseStatuses []string
 }
@@ -8,6 +8,10 @@ type Tool struct {
 	// It MUST be unique within the MCP server.
 	Name string `json:"name"`
 
+	// Title is an optional human-readable display name (e.g., "List all pets"),
+	// shown by clients instead of the sanitized machine Name.
+	Title string `json:"title,omitempty"`
+
 	// Description provides a natural language explanation of what the tool does.
 	// This is crucial for the LLM to understand when to use the tool.
 	Description string `json:"description"`
@@ -21,24 +25,50 @@ type Tool struct {
 	// Uses JSON Schema format.
 	OutputSchema *JSONSchemaProps `json:"output_schema,omitempty"`
 
+	// Annotations carries optional behavior hints for clients (e.g., whether to confirm before calling).
+	// Nil leaves the MCP defaults in place.
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+
+	// Links suggests tools to call next with values from this tool's result, e.g. from
+	// OpenAPI response links. Nil when the schema declares none.
+	Links []ToolLink `json:"links,omitempty"`
+
 	// TODO: Add fields for invocation details (e.g., HTTP method/path, gRPC service/method)
 	// These might live here or in a separate internal mapping structure used by InvokeToolUseCase.
 	// Keeping them out of the core MCP definition for now.
 	// InvocationTarget InvocationDetails
 }
 
+// ToolLink suggests a follow-up tool call, taking arguments from the linking tool's
+// request or result.
+type ToolLink struct {
+	Name        string            `json:"name"`                  // Name of the link in the API schema, e.g. "GetUserByUserId"
+	Tool        string            `json:"tool"`                  // Name of the suggested tool
+	Description string            `json:"description,omitempty"` // What the link is for
+	Parameters  map[string]string `json:"parameters,omitempty"`  // Tool parameter -> value or runtime expression, e.g. "$response.body#/id"
+}
+
+// ToolAnnotations mirrors the MCP tool annotation hints. Nil fields are left unset.
+type ToolAnnotations struct {
+	ReadOnlyHint    *bool `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool `json:"openWorldHint,omitempty"`
+}
+
 // JSONSchemaProps represents the properties of a JSON schema,
 // commonly used for input and output definitions in MCP tools.
 // This is a simplified version; a more complete implementation might import
 // a dedicated JSON schema library or use map[string]interface{}.
 type JSONSchemaProps struct {
-	Type       string                     `json:"type"`                 // e.g., "object", "string", "number", "integer", "boolean", "array"
-	Properties map[string]JSONSchemaProps `json:"properties,omitempty"` // For type "object"
-	Required   []string                   `json:"required,omitempty"`   // For type "object"
-	Items      *JSONSchemaProps           `json:"items,omitempty"`      // For type "array"
-	Format     string                     `json:"format,omitempty"`     // e.g., "date-time", "email"
-	Enum       []interface{}              `json:"enum,omitempty"`       // Possible values
-	// Add other JSON Schema fields as needed: description, default, minimum, maximum, etc.
+	Type        string                     `json:"type"`                  // e.g., "object", "string", "number", "integer", "boolean", "array"
+	Properties  map[string]JSONSchemaProps `json:"properties,omitempty"`  // For type "object"
+	Required    []string                   `json:"required,omitempty"`    // For type "object"
+	Items       *JSONSchemaProps           `json:"items,omitempty"`       // For type "array"
+	Format      string                     `json:"format,omitempty"`      // e.g., "date-time", "email"
+	Enum        []interface{}              `json:"enum,omitempty"`        // Possible values
+	Description string                     `json:"description,omitempty"` // Human-readable description of the value
+	// Add other JSON Schema fields as needed: default, minimum, maximum, etc.
 }
 
 // Consider adding helper functions here later, e.g.:
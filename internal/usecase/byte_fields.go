@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"unicode/utf8"
+
+	"github.com/i2y/mcpizer/internal/domain"
+)
+
+// decodeByteFields replaces the base64 values of the "format: byte" string fields that
+// schema describes in v with their decoded text, so that a tool result shows e.g. a
+// proto bytes field holding JSON or plain text as such. Values that are not valid
+// base64 or do not decode to UTF-8 text are left as they are. v is modified in place.
+func decodeByteFields(v interface{}, schema *domain.JSONSchemaProps) interface{} {
+	if schema == nil {
+		return v
+	}
+	switch value := v.(type) {
+	case string:
+		if schema.Type == "string" && schema.Format == "byte" {
+			if text, ok := decodeBase64Text(value); ok {
+				return text
+			}
+		}
+	case map[string]interface{}:
+		for name, prop := range schema.Properties {
+			if field, ok := value[name]; ok {
+				value[name] = decodeByteFields(field, &prop)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = decodeByteFields(item, schema.Items)
+		}
+	}
+	return v
+}
+
+// decodeBase64Text decodes s, in the standard or URL-safe alphabet with or
+// without padding as proto3 JSON allows, and reports whether it is UTF-8 text.
+func decodeBase64Text(s string) (string, bool) {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if decoded, err := enc.DecodeString(s); err == nil {
+			return string(decoded), utf8.Valid(decoded)
+		}
+	}
+	return "", false
+}
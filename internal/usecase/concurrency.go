@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+)
+
+// In-flight policies decide what happens to a tool call when its source
+// already has SchemaSourceConfig.MaxInFlight calls running.
+const (
+	InFlightPolicyQueue  = "queue"  // wait for a running call to finish (the default)
+	InFlightPolicyReject = "reject" // fail the call immediately
+)
+
+// inFlightLimiter bounds the number of concurrent invocations against one source.
+type inFlightLimiter struct {
+	slots  chan struct{}
+	reject bool
+}
+
+func newInFlightLimiter(maxInFlight int, policy string) *inFlightLimiter {
+	return &inFlightLimiter{
+		slots:  make(chan struct{}, maxInFlight),
+		reject: policy == InFlightPolicyReject,
+	}
+}
+
+// sameAs reports whether l already enforces maxInFlight with policy.
+func (l *inFlightLimiter) sameAs(maxInFlight int, policy string) bool {
+	return cap(l.slots) == maxInFlight && l.reject == (policy == InFlightPolicyReject)
+}
+
+// acquire takes a slot, waiting for one or failing fast depending on the policy.
+// The returned function releases the slot.
+func (l *inFlightLimiter) acquire(ctx context.Context, source string) (func(), error) {
+	release := func() { <-l.slots }
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+	if l.reject {
+		return nil, NewInvocationError(ErrorCategoryRateLimited,
+			fmt.Errorf("source '%s' already has %d calls in flight", source, cap(l.slots)))
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for an in-flight slot for source '%s': %w", source, ctx.Err())
+	}
+}
+
+// updateLimiter installs, replaces, or removes the in-flight limiter of source
+// so that it matches its configuration. Unchanged limiters are kept, so calls
+// running across a re-sync still count against the limit.
+func (uc *SyncSchemaUseCase) updateLimiter(source SchemaSourceConfig) {
+	uc.limitersMu.Lock()
+	defer uc.limitersMu.Unlock()
+
+	if source.MaxInFlight <= 0 {
+		delete(uc.limiters, source.URL)
+		return
+	}
+	if l, ok := uc.limiters[source.URL]; ok && l.sameAs(source.MaxInFlight, source.InFlightPolicy) {
+		return
+	}
+	uc.limiters[source.URL] = newInFlightLimiter(source.MaxInFlight, source.InFlightPolicy)
+}
+
+// limiterFor returns the in-flight limiter of source, or nil if it is unlimited.
+func (uc *SyncSchemaUseCase) limiterFor(source string) *inFlightLimiter {
+	uc.limitersMu.Lock()
+	defer uc.limitersMu.Unlock()
+	return uc.limiters[source]
+}
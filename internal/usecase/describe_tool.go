@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/i2y/mcpizer/internal/domain"
+)
+
+// DescribeToolName is the name of the meta-tool that returns the full definition of
+// another tool. Configured tool name affixes apply to it as well.
+const DescribeToolName = "mcpizer_describe_tool"
+
+// ToolDescription is the full definition of a registered tool, as returned by the
+// describe meta-tool.
+type ToolDescription struct {
+	Name         string                  `json:"name"`
+	Title        string                  `json:"title,omitempty"`
+	Description  string                  `json:"description"`
+	InputSchema  domain.JSONSchemaProps  `json:"inputSchema"`
+	OutputSchema *domain.JSONSchemaProps `json:"outputSchema,omitempty"`
+	Annotations  *domain.ToolAnnotations `json:"annotations,omitempty"`
+	Links        []domain.ToolLink       `json:"links,omitempty"`
+	Target       *ToolTarget             `json:"target,omitempty"`
+}
+
+// ToolTarget tells which upstream endpoint a tool calls. Headers, credentials, and
+// descriptors of the invocation details are left out.
+type ToolTarget struct {
+	Type        string `json:"type"`
+	Host        string `json:"host,omitempty"`
+	BasePath    string `json:"base_path,omitempty"`
+	HTTPMethod  string `json:"http_method,omitempty"`
+	HTTPPath    string `json:"http_path,omitempty"`
+	GRPCService string `json:"grpc_service,omitempty"`
+	GRPCMethod  string `json:"grpc_method,omitempty"`
+	Server      string `json:"server,omitempty"`
+	Method      string `json:"method,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// RegisterDescribeTool registers the describe meta-tool, which takes a tool name and
+// returns the tool's description, input and output JSON Schemas, and target, as kept
+// in the tool repository.
+func (uc *SyncSchemaUseCase) RegisterDescribeTool() error {
+	if uc.repository == nil {
+		return fmt.Errorf("the %s tool needs a tool repository", DescribeToolName)
+	}
+	tool := mcp.NewTool(affixToolName(uc.toolNamePrefix, DescribeToolName, uc.toolNameSuffix),
+		mcp.WithDescription("Returns the full definition of another tool: its description, "+
+			"input and output JSON Schemas, and the API endpoint it calls."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the tool to describe")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+	)
+	uc.mcpServer.AddTool(tool, uc.describeToolHandler)
+	uc.logger.Info("Registered describe tool.", slog.String("toolName", tool.Name))
+	return nil
+}
+
+// describeToolHandler answers calls of the describe meta-tool.
+func (uc *SyncSchemaUseCase) describeToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolErrorResult(err, ErrorCategoryInvalidInput), nil
+	}
+	description, err := uc.describeTool(ctx, name)
+	if err != nil {
+		return newToolErrorResult(err, ErrorCategoryOf(err)), nil
+	}
+	data, err := json.Marshal(description)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode description of tool %s: %w", name, err)
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// describeTool looks up the definition and target of the named tool.
+func (uc *SyncSchemaUseCase) describeTool(ctx context.Context, name string) (*ToolDescription, error) {
+	tool, err := uc.repository.FindToolByName(ctx, name)
+	if errors.Is(err, ErrToolNotFound) || (err == nil && tool == nil) {
+		return nil, NewInvocationError(ErrorCategoryNotFound, fmt.Errorf("no tool named %s", name))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up tool %s: %w", name, err)
+	}
+	description := &ToolDescription{
+		Name:         tool.Name,
+		Title:        tool.Title,
+		Description:  tool.Description,
+		InputSchema:  tool.InputSchema,
+		OutputSchema: tool.OutputSchema,
+		Annotations:  tool.Annotations,
+		Links:        tool.Links,
+	}
+	details, err := uc.repository.FindInvocationDetailsByName(ctx, name)
+	if err != nil {
+		uc.logger.Warn("Failed to look up invocation details of described tool.", slog.String("toolName", name), slog.Any("error", err))
+	} else if details != nil {
+		description.Target = &ToolTarget{
+			Type:        details.Type,
+			Host:        details.Host,
+			BasePath:    details.BasePath,
+			HTTPMethod:  details.HTTPMethod,
+			HTTPPath:    details.HTTPPath,
+			GRPCService: details.GRPCService,
+			GRPCMethod:  details.GRPCMethod,
+			Server:      details.Server,
+			Method:      details.Method,
+			ContentType: details.ContentType,
+		}
+	}
+	return description, nil
+}
@@ -0,0 +1,117 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpServer "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/memrepo"
+	"github.com/i2y/mcpizer/internal/domain"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+func TestSyncSchemaUseCase_RegisterDescribeTool(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	sourceURL := "http://pets.example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+	tool := domain.Tool{
+		Name:        "pets_getpet",
+		Description: "Get a pet by ID",
+		InputSchema: domain.JSONSchemaProps{
+			Type:       "object",
+			Properties: map[string]domain.JSONSchemaProps{"petId": {Type: "integer", Description: "ID of the pet"}},
+			Required:   []string{"petId"},
+		},
+		OutputSchema: &domain.JSONSchemaProps{
+			Type:       "object",
+			Properties: map[string]domain.JSONSchemaProps{"name": {Type: "string"}},
+		},
+	}
+	details := usecase.InvocationDetails{
+		Type:         "http",
+		Host:         "https://pets.example.com",
+		BasePath:     "/v1",
+		HTTPMethod:   "GET",
+		HTTPPath:     "/pets/{petId}",
+		PathParams:   []string{"petId"},
+		HeaderParams: map[string]string{"Authorization": "Bearer secret"},
+	}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil)
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return([]domain.Tool{tool}, []usecase.InvocationDetails{details}, nil)
+
+	handlers := make(map[string]mcpServer.ToolHandlerFunc)
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handlers[args.Get(0).(mcp.Tool).Name] = args.Get(1).(mcpServer.ToolHandlerFunc)
+	})
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: sourceURL}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		new(MockToolInvoker),
+		logger,
+		usecase.WithToolRepository(memrepo.NewInMemoryToolRepository(logger)),
+	)
+	require.NoError(t, uc.RegisterDescribeTool())
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+
+	describe := handlers[usecase.DescribeToolName]
+	require.NotNil(t, describe)
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		var request mcp.CallToolRequest
+		request.Params.Arguments = args
+		result, err := describe(ctx, request)
+		require.NoError(t, err)
+		return result
+	}
+
+	result := call(map[string]any{"name": "pets_getpet"})
+	require.False(t, result.IsError)
+	require.Len(t, result.Content, 1)
+	assert.JSONEq(t, `{
+		"name": "pets_getpet",
+		"description": "Get a pet by ID",
+		"inputSchema": {
+			"type": "object",
+			"properties": {"petId": {"type": "integer", "description": "ID of the pet"}},
+			"required": ["petId"]
+		},
+		"outputSchema": {"type": "object", "properties": {"name": {"type": "string"}}},
+		"target": {
+			"type": "http",
+			"host": "https://pets.example.com",
+			"base_path": "/v1",
+			"http_method": "GET",
+			"http_path": "/pets/{petId}"
+		}
+	}`, result.Content[0].(mcp.TextContent).Text)
+
+	result = call(map[string]any{"name": "pets_deletepet"})
+	assert.True(t, result.IsError)
+	assert.Equal(t, map[string]any{"errorCategory": "not_found"}, result.Meta)
+
+	result = call(map[string]any{})
+	assert.True(t, result.IsError)
+	assert.Equal(t, map[string]any{"errorCategory": "invalid_input"}, result.Meta)
+}
+
+func TestSyncSchemaUseCase_RegisterDescribeTool_NoRepository(t *testing.T) {
+	uc := usecase.NewSyncSchemaUseCase(nil, nil, nil, new(MockMCPServer), new(MockToolInvoker),
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.Error(t, uc.RegisterDescribeTool())
+}
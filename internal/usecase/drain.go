@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// callTracker counts the tool calls in flight so that shutdown can wait for them.
+// Unlike a sync.WaitGroup, calls may start while a Drain is waiting.
+type callTracker struct {
+	mu   sync.Mutex
+	n    int
+	idle chan struct{} // closed when n drops to zero
+}
+
+// start records a call starting. The returned function records it finishing.
+func (t *callTracker) start() func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.n == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.n++
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.n--
+		if t.n == 0 {
+			close(t.idle)
+		}
+	}
+}
+
+// wait blocks until no call is in flight or ctx is done.
+func (t *callTracker) wait(ctx context.Context) error {
+	t.mu.Lock()
+	n, idle := t.n, t.idle
+	t.mu.Unlock()
+	if n == 0 {
+		return nil
+	}
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// running returns the number of calls in flight.
+func (t *callTracker) running() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.n
+}
+
+// Drain waits for the tool calls in flight to finish, so that shutting down does not
+// cut off long upstream calls. It gives up when ctx is done, e.g. when the shutdown
+// timeout expires, and reports how many calls were still running.
+func (uc *SyncSchemaUseCase) Drain(ctx context.Context) error {
+	if err := uc.calls.wait(ctx); err != nil {
+		return fmt.Errorf("%d tool calls still in flight: %w", uc.calls.running(), err)
+	}
+	return nil
+}
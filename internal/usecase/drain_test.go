@@ -0,0 +1,101 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpServer "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/domain"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+func TestSyncSchemaUseCase_Drain(t *testing.T) {
+	tests := []struct {
+		name     string
+		callTime time.Duration
+		timeout  time.Duration
+		wantErr  bool
+	}{
+		{name: "waits for a slow call", callTime: 200 * time.Millisecond, timeout: 5 * time.Second},
+		{name: "gives up at the timeout", callTime: 5 * time.Second, timeout: 100 * time.Millisecond, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			sourceURL := "http://example.com/openapi.yaml"
+			schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+			fetcher := new(MockSchemaFetcher)
+			fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil)
+			generator := new(MockToolGenerator)
+			generator.On("Generate", schema).Return(
+				[]domain.Tool{{Name: "slow_op"}},
+				[]usecase.InvocationDetails{{Type: "http", Host: "http://example.com", HTTPMethod: "GET", HTTPPath: "/slow"}},
+				nil,
+			)
+			var handler mcpServer.ToolHandlerFunc
+			mcpSrv := new(MockMCPServer)
+			mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				handler = args.Get(1).(mcpServer.ToolHandlerFunc)
+			})
+			started := make(chan struct{})
+			release := make(chan struct{})
+			defer close(release)
+			invoker := new(MockToolInvoker)
+			invoker.On("Invoke", mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+				close(started)
+				select {
+				case <-time.After(tt.callTime):
+				case <-release:
+				}
+			}).Return(map[string]interface{}{"ok": true}, nil)
+
+			uc := usecase.NewSyncSchemaUseCase(
+				[]usecase.SchemaSourceConfig{{URL: sourceURL}},
+				map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+				map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+				mcpSrv,
+				invoker,
+				slog.New(slog.NewTextHandler(io.Discard, nil)),
+			)
+			require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+			require.NotNil(t, handler)
+
+			// Nothing in flight: draining returns right away.
+			require.NoError(t, uc.Drain(ctx))
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				_, _ = handler(ctx, mcp.CallToolRequest{})
+			}()
+			<-started
+
+			drainCtx, cancel := context.WithTimeout(ctx, tt.timeout)
+			defer cancel()
+			start := time.Now()
+			err := uc.Drain(drainCtx)
+			elapsed := time.Since(start)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, context.DeadlineExceeded))
+				assert.Contains(t, err.Error(), "1 tool calls still in flight")
+				assert.Less(t, elapsed, tt.callTime)
+				return
+			}
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, elapsed, tt.callTime/2, "Drain returned before the in-flight call finished")
+			<-done
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ErrorCategory classifies a failed tool invocation so that clients can react
+// to it programmatically instead of parsing the error text.
+type ErrorCategory string
+
+const (
+	ErrorCategoryNotFound     ErrorCategory = "not_found"
+	ErrorCategoryUnauthorized ErrorCategory = "unauthorized"
+	ErrorCategoryTimeout      ErrorCategory = "timeout"
+	ErrorCategoryInvalidInput ErrorCategory = "invalid_input"
+	ErrorCategoryUpstream     ErrorCategory = "upstream"
+	ErrorCategoryRateLimited  ErrorCategory = "rate_limited"
+)
+
+// InvocationError is an invocation failure tagged with its category.
+type InvocationError struct {
+	Category ErrorCategory
+	Err      error
+}
+
+// NewInvocationError wraps err with the given category.
+func NewInvocationError(category ErrorCategory, err error) error {
+	return &InvocationError{Category: category, Err: err}
+}
+
+func (e *InvocationError) Error() string { return e.Err.Error() }
+
+func (e *InvocationError) Unwrap() error { return e.Err }
+
+// CategoryForHTTPStatus maps a non-2xx HTTP status code to an error category.
+func CategoryForHTTPStatus(status int) ErrorCategory {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge:
+		return ErrorCategoryInvalidInput
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrorCategoryUnauthorized
+	case http.StatusNotFound, http.StatusGone:
+		return ErrorCategoryNotFound
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return ErrorCategoryTimeout
+	default:
+		return ErrorCategoryUpstream
+	}
+}
+
+// CategoryForCode maps a gRPC or Connect status code name to an error category.
+// Both spellings are accepted ("NotFound" as printed by gRPC, "not_found" as sent by Connect).
+func CategoryForCode(code string) ErrorCategory {
+	switch strings.ToLower(strings.ReplaceAll(code, "_", "")) {
+	case "notfound":
+		return ErrorCategoryNotFound
+	case "unauthenticated", "permissiondenied":
+		return ErrorCategoryUnauthorized
+	case "deadlineexceeded":
+		return ErrorCategoryTimeout
+	case "invalidargument", "outofrange", "failedprecondition":
+		return ErrorCategoryInvalidInput
+	default:
+		return ErrorCategoryUpstream
+	}
+}
+
+// ErrorCategoryOf returns the category of an invocation error. Errors not
+// categorized by an invoker are classified as timeouts when a deadline was
+// exceeded and as upstream failures otherwise.
+func ErrorCategoryOf(err error) ErrorCategory {
+	var invErr *InvocationError
+	if errors.As(err, &invErr) {
+		return invErr.Category
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorCategoryTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorCategoryTimeout
+	}
+	return ErrorCategoryUpstream
+}
@@ -0,0 +1,74 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+func TestErrorCategoryOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want usecase.ErrorCategory
+	}{
+		{
+			name: "HTTP 401",
+			err:  usecase.NewInvocationError(usecase.CategoryForHTTPStatus(http.StatusUnauthorized), errors.New("HTTP 401: unauthorized")),
+			want: usecase.ErrorCategoryUnauthorized,
+		},
+		{
+			name: "HTTP 404",
+			err:  usecase.NewInvocationError(usecase.CategoryForHTTPStatus(http.StatusNotFound), errors.New("HTTP 404: not found")),
+			want: usecase.ErrorCategoryNotFound,
+		},
+		{
+			name: "context deadline",
+			err:  fmt.Errorf("request execution failed: %w", context.DeadlineExceeded),
+			want: usecase.ErrorCategoryTimeout,
+		},
+		{
+			name: "wrapped invocation error",
+			err:  fmt.Errorf("invoke: %w", usecase.NewInvocationError(usecase.ErrorCategoryInvalidInput, errors.New("bad"))),
+			want: usecase.ErrorCategoryInvalidInput,
+		},
+		{
+			name: "uncategorized",
+			err:  errors.New("connection reset"),
+			want: usecase.ErrorCategoryUpstream,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, usecase.ErrorCategoryOf(tt.err))
+		})
+	}
+}
+
+func TestCategoryForCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want usecase.ErrorCategory
+	}{
+		{code: "not_found", want: usecase.ErrorCategoryNotFound},
+		{code: "NotFound", want: usecase.ErrorCategoryNotFound},
+		{code: "unauthenticated", want: usecase.ErrorCategoryUnauthorized},
+		{code: "PermissionDenied", want: usecase.ErrorCategoryUnauthorized},
+		{code: "deadline_exceeded", want: usecase.ErrorCategoryTimeout},
+		{code: "InvalidArgument", want: usecase.ErrorCategoryInvalidInput},
+		{code: "unavailable", want: usecase.ErrorCategoryUpstream},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.want, usecase.CategoryForCode(tt.code))
+		})
+	}
+}
@@ -3,6 +3,9 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
+	"time"
 
 	"github.com/i2y/mcpizer/internal/domain"
 	// Import mcp types needed for the adapter interface
@@ -26,6 +29,51 @@ type SchemaSourceConfig struct {
 	Server  string // For .proto files, the gRPC server endpoint
 	Type    string // Schema type override (e.g., "connect" for Connect-RPC)
 	Mode    string // Invocation mode (e.g., "http" or "grpc" for Connect-RPC)
+
+	ConnectProtocolVersion string // Overrides the Connect-Protocol-Version header ("none" omits it)
+	AcceptEncoding         string // Accept-Encoding sent on Connect-RPC calls (e.g., "gzip")
+
+	ContentType    string // Overrides the request body Content-Type of the source's HTTP tools
+	AcceptLanguage string // Accept-Language sent when fetching the schema and calling its tools
+
+	RequestContentTypes []string // Preferred request body media types for operations offering several
+	ResponseStatuses    []string // Preferred response statuses for OpenAPI output schemas (e.g., "202", "default")
+
+	ResponseHeaders []string // Response headers returned next to the body of HTTP tool results
+
+	// Constant parameters added to every HTTP tool call of the source
+	StaticQueryParams map[string]string
+	StaticHeaders     map[string]string
+	StaticBodyParams  map[string]interface{}
+
+	TLSCAFile             string // PEM CA certificates trusted when fetching the schema and calling its HTTP tools
+	TLSInsecureSkipVerify bool   // Skip TLS certificate verification for the source
+
+	// Credential read from a file (e.g., a mounted Kubernetes secret) and sent in
+	// AuthHeader on the source's HTTP tool calls, re-read every AuthRefreshInterval
+	// when set. An empty AuthHeader sends "Authorization: Bearer <token>".
+	AuthTokenFile       string
+	AuthHeader          string
+	AuthScheme          string
+	AuthRefreshInterval time.Duration
+
+	BasePath string // Replaces the base path derived from the schema's servers for HTTP tools ("/" drops it)
+
+	// Regular expression replacement applied at generation time to the full request
+	// path (BasePath joined with HTTPPath) of the source's HTTP tools
+	PathRewrite            *regexp.Regexp
+	PathRewriteReplacement string
+
+	DiscoverAll bool // Register every spec discovered at the base URL, namespaced by API version
+
+	MaxTools int // Maximum tools registered from this source (0 uses the WithMaxTools default)
+
+	LoadBalancing string // gRPC load balancing policy for multi-address targets (e.g., "round_robin")
+
+	MaxInFlight    int    // Maximum concurrent tool calls against this source (0 means unlimited)
+	InFlightPolicy string // What to do with calls beyond MaxInFlight: InFlightPolicyQueue or InFlightPolicyReject
+
+	Disabled bool // Keep the source configured but neither fetch it nor register its tools
 }
 
 // SchemaFetcher defines the interface for fetching API schemas from various sources.
@@ -34,12 +82,39 @@ type SchemaFetcher interface {
 	FetchWithConfig(ctx context.Context, config SchemaSourceConfig) (domain.APISchema, error)
 }
 
+// MultiSchemaFetcher is implemented by fetchers that can discover several schemas
+// behind one source, e.g. versioned OpenAPI specs at a base URL.
+type MultiSchemaFetcher interface {
+	FetchAll(ctx context.Context, config SchemaSourceConfig) ([]domain.APISchema, error)
+}
+
 // ToolGenerator defines the interface for generating Tools and InvocationDetails
 // from a fetched APISchema.
 type ToolGenerator interface {
 	Generate(schema domain.APISchema) ([]domain.Tool, []InvocationDetails, error)
 }
 
+// ReportingToolGenerator is implemented by generators that can also tell which
+// operations of a schema they skipped, and why.
+type ReportingToolGenerator interface {
+	GenerateWithReport(schema domain.APISchema) ([]domain.Tool, []InvocationDetails, []SkippedOperation, error)
+}
+
+// SkippedOperation is an operation of a schema that no tool could be generated for.
+type SkippedOperation struct {
+	Operation string // e.g. "GET /users/{id}"
+	Line      int    // line declaring the operation in the schema document; 0 if unknown
+	Reason    string
+}
+
+// String formats the operation with its line, e.g. "GET /users/{id} (line 42)".
+func (op SkippedOperation) String() string {
+	if op.Line == 0 {
+		return op.Operation
+	}
+	return fmt.Sprintf("%s (line %d)", op.Operation, op.Line)
+}
+
 // ToolRepository defines the contract for storing and retrieving generated Tools
 // and their InvocationDetails.
 // Implementations could range from in-memory stores to persistent databases.
@@ -57,6 +132,9 @@ type ToolRepository interface {
 
 	// FindInvocationDetailsByName retrieves the invocation details for a specific tool by name.
 	FindInvocationDetailsByName(ctx context.Context, name string) (*InvocationDetails, error)
+
+	// Delete removes the named tools and their invocation details. Unknown names are ignored.
+	Delete(ctx context.Context, names ...string) error
 }
 
 // --- MCP Server Abstraction ---
@@ -70,7 +148,9 @@ type MCPServerAdapter interface {
 	// MCP server library being adapted.
 	// Use the specific type from the mcp-go/server package.
 	AddTool(tool mcp.Tool, handlerFunc mcpGoServer.ToolHandlerFunc)
-	// TODO: Add other methods if SyncSchemaUseCase needs them (e.g., RemoveTool)
+	// DeleteTools unregisters tools by name. Used on re-sync to drop tools that
+	// disappeared from their source.
+	DeleteTools(names ...string)
 }
 
 // --- Tool Invocation Related ---
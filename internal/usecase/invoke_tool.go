@@ -21,6 +21,14 @@ var tracer = otel.Tracer("mcpizer/usecase")
 var (
 	// toolInvocationCounter counts tool invocations, labeled by tool name and success status.
 	toolInvocationCounter metric.Int64Counter
+	// sourceSyncCounter counts the syncs of each enabled source, labeled by success
+	// status. A sync fails if fetching the schema, generating its tools, or
+	// registering them fails.
+	sourceSyncCounter metric.Int64Counter
+	// toolsGeneratedCounter counts the tools generated by each source's syncs.
+	toolsGeneratedCounter metric.Int64Counter
+	// registeredToolsGauge records the number of tools currently registered with the MCP server.
+	registeredToolsGauge metric.Int64Gauge
 )
 
 // initMetrics initializes the OpenTelemetry metrics for this package.
@@ -37,6 +45,30 @@ func initMetrics() {
 		// error handling strategy for production (e.g., log and disable metrics).
 		panic(fmt.Sprintf("Failed to create toolInvocationCounter: %v", err))
 	}
+	sourceSyncCounter, err = meter.Int64Counter(
+		"mcpizer.source.syncs",
+		metric.WithDescription("Counts syncs of configured sources: fetching the schema, generating and registering its tools."),
+		metric.WithUnit("{sync}"),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create sourceSyncCounter: %v", err))
+	}
+	toolsGeneratedCounter, err = meter.Int64Counter(
+		"mcpizer.schema.tools_generated",
+		metric.WithDescription("Counts the tools generated from each source's schema."),
+		metric.WithUnit("{tool}"),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create toolsGeneratedCounter: %v", err))
+	}
+	registeredToolsGauge, err = meter.Int64Gauge(
+		"mcpizer.tools.registered",
+		metric.WithDescription("Number of tools currently registered with the MCP server."),
+		metric.WithUnit("{tool}"),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create registeredToolsGauge: %v", err))
+	}
 }
 
 // Call initMetrics on package load.
@@ -53,6 +85,15 @@ type InvocationDetails struct {
 	// Host is the base URL of the target service (e.g., "http://localhost:8080" or "grpc://localhost:50051").
 	Host string `json:"host"`
 
+	// Source is the URL of the schema source the tool was generated from. It is
+	// recorded with stored tools, so that restored tools get their source's token
+	// file credential before the source syncs.
+	Source string `json:"source,omitempty"`
+
+	// Hosts lists every server the API is served from, Host first. HTTP calls move on
+	// to the next one when a host cannot be connected to.
+	Hosts []string `json:"hosts,omitempty"`
+
 	// BasePath is the extracted from OpenAPI servers (e.g., "/api/v1").
 	BasePath string `json:"base_path,omitempty"`
 
@@ -68,10 +109,22 @@ type InvocationDetails struct {
 	// QueryParams lists the names of parameters expected to be sent as URL query arguments.
 	QueryParams []string `json:"query_params,omitempty"`
 
+	// StaticQueryParams are sent as URL query arguments on every call, e.g. query
+	// parameters whose schema allows a single value. They are not tool inputs.
+	StaticQueryParams map[string]string `json:"static_query_params,omitempty"`
+
 	// HeaderParams defines static headers to be included in the request.
 	// Dynamic headers (e.g., from tool parameters) might be handled separately by the invoker.
 	HeaderParams map[string]string `json:"header_params,omitempty"`
 
+	// StaticBodyParams are added to every JSON object request body, without replacing
+	// fields the caller set. They are not tool inputs.
+	StaticBodyParams map[string]interface{} `json:"static_body_params,omitempty"`
+
+	// DateParams maps the top-level tool inputs that are dates to their format, "date"
+	// or "date-time", so that the invoker can normalize loosely written values.
+	DateParams map[string]string `json:"date_params,omitempty"`
+
 	// BodyParam indicates which single tool input parameter should be marshalled as the HTTP request body.
 	// If empty, the request body might be constructed from multiple parameters or be absent.
 	BodyParam string `json:"body_param,omitempty"`
@@ -93,13 +146,42 @@ type InvocationDetails struct {
 	InputType  string `json:"input_type,omitempty"`
 	OutputType string `json:"output_type,omitempty"`
 
-	// For .proto files: File descriptor for dynamic invocation
+	// For .proto files: the method's file and its dependencies (a FileDescriptorSet),
+	// so that gRPC calls need no server reflection
 	FileDescriptor interface{} `json:"file_descriptor,omitempty"`
 
+	// ResponseHeaders names the response headers returned next to the body, as
+	// {"body": ..., "headers": {...}}. Binary results carry them in BinaryResult.Headers.
+	ResponseHeaders []string `json:"response_headers,omitempty"`
+
+	// TLSCAFile and TLSInsecureSkipVerify configure HTTPS connections to a source whose
+	// certificate is signed by a private CA or self-signed.
+	TLSCAFile             string `json:"tls_ca_file,omitempty"`
+	TLSInsecureSkipVerify bool   `json:"tls_insecure_skip_verify,omitempty"`
+
+	// LoadBalancing is the gRPC load balancing policy (e.g., "round_robin") for targets
+	// that resolve to several addresses, such as "dns:///host:port".
+	LoadBalancing string `json:"load_balancing,omitempty"`
+
 	// ContentType indicates the expected Content-Type for the request body (e.g., "application/json").
 	// Defaults to application/json if involving a body.
 	ContentType string `json:"content_type,omitempty"`
 
+	// Accept is sent as the Accept header when set, listing the media types the
+	// operation declares for its success responses (JSON preferred).
+	Accept string `json:"accept,omitempty"`
+
+	// AcceptLanguage is sent as the Accept-Language header on HTTP and Connect-RPC
+	// calls when set (e.g., "ja-JP"), so that localized APIs answer in that locale.
+	AcceptLanguage string `json:"accept_language,omitempty"`
+
+	// Connect-RPC specific fields
+	// ConnectProtocolVersion overrides the Connect-Protocol-Version header. Empty uses "1", "none" omits it.
+	ConnectProtocolVersion string `json:"connect_protocol_version,omitempty"`
+
+	// AcceptEncoding is sent as the Accept-Encoding header when set (e.g., "gzip").
+	AcceptEncoding string `json:"accept_encoding,omitempty"`
+
 	// TODO: Add authentication details or mechanisms
 }
 
@@ -109,6 +191,18 @@ type ToolInvoker interface {
 	Invoke(ctx context.Context, details InvocationDetails, params map[string]interface{}) (interface{}, error)
 }
 
+// BinaryResult is an invocation result that is not text, such as an image returned by
+// an HTTP endpoint. Tool handlers return it as an image, audio, or embedded blob
+// resource content block instead of text.
+type BinaryResult struct {
+	MIMEType string `json:"mime_type"`
+	Data     []byte `json:"data"`
+	// URI is where the data was fetched from; it identifies an embedded blob resource.
+	URI string `json:"uri,omitempty"`
+	// Headers are the response headers named by InvocationDetails.ResponseHeaders.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
 // InvokeToolUseCase handles receiving a tool invocation request and executing it.
 type InvokeToolUseCase struct {
 	repository ToolRepository
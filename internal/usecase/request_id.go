@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RequestIDMetaKey is the _meta field of a tools/call request whose value, when
+// set, is used as the call's request ID instead of a generated one.
+const RequestIDMetaKey = "requestId"
+
+// maxRequestIDLength bounds the length of request IDs taken from clients.
+const maxRequestIDLength = 128
+
+// RequestID identifies one tool call across mcpizer's logs and traces and the
+// upstream's, which receive it in Header.
+type RequestID struct {
+	ID string
+	// Header is the request header (or gRPC metadata key) carrying ID to the
+	// upstream. Empty keeps the ID out of outbound requests.
+	Header string
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying id, for the invokers to send
+// upstream and log.
+func ContextWithRequestID(ctx context.Context, id RequestID) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID of the tool call ctx belongs to.
+func RequestIDFromContext(ctx context.Context) (RequestID, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(RequestID)
+	return id, ok && id.ID != ""
+}
+
+// RequestIDLogAttr returns the log attribute of ctx's request ID, or an empty
+// attribute (which slog drops) when ctx has none.
+func RequestIDLogAttr(ctx context.Context) slog.Attr {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return slog.String("requestId", id.ID)
+	}
+	return slog.Attr{}
+}
+
+// WithRequestIDHeader sends every tool call's request ID to the upstream in the
+// named header (gRPC metadata key for gRPC calls). The ID is logged and recorded
+// on the call's trace span either way.
+func WithRequestIDHeader(header string) SyncOption {
+	return func(uc *SyncSchemaUseCase) {
+		uc.requestIDHeader = header
+	}
+}
+
+// requestIDOf returns the request ID the client set in the call's _meta, or a new
+// random one. As the ID is sent upstream in a header, a client's ID is only used
+// when it is printable ASCII without spaces and at most maxRequestIDLength long.
+func requestIDOf(request mcp.CallToolRequest) string {
+	if meta := request.Params.Meta; meta != nil {
+		var id string
+		switch v := meta.AdditionalFields[RequestIDMetaKey].(type) {
+		case string:
+			id = v
+		case float64:
+			id = fmt.Sprint(v)
+		}
+		if validRequestID(id) {
+			return id
+		}
+	}
+	return newRequestID()
+}
+
+// validRequestID reports whether id is a usable request ID from a client.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns 16 random bytes in hex.
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,121 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpServer "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/httpinvoker"
+	"github.com/i2y/mcpizer/internal/domain"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+func TestSyncSchemaUseCase_RequestIDHeader(t *testing.T) {
+	var received []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("X-Request-ID"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer upstream.Close()
+
+	tests := []struct {
+		name      string
+		meta      *mcp.Meta
+		wantID    string
+		wantNotID string // a client's ID that must be replaced by a generated one
+	}{
+		{name: "generated"},
+		{
+			name:   "from request meta",
+			meta:   &mcp.Meta{AdditionalFields: map[string]any{usecase.RequestIDMetaKey: "req-42"}},
+			wantID: "req-42",
+		},
+		{
+			name:      "control characters in request meta",
+			meta:      &mcp.Meta{AdditionalFields: map[string]any{usecase.RequestIDMetaKey: "req-42\r\nX-Admin: true"}},
+			wantNotID: "req-42\r\nX-Admin: true",
+		},
+		{
+			name:      "overlong request meta",
+			meta:      &mcp.Meta{AdditionalFields: map[string]any{usecase.RequestIDMetaKey: strings.Repeat("a", 129)}},
+			wantNotID: strings.Repeat("a", 129),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			received = nil
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			sourceURL := upstream.URL + "/openapi.yaml"
+			schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+			tool := domain.Tool{Name: "status", InputSchema: domain.JSONSchemaProps{Type: "object"}}
+			details := usecase.InvocationDetails{Type: "http", Host: upstream.URL, HTTPMethod: "GET", HTTPPath: "/status"}
+
+			fetcher := new(MockSchemaFetcher)
+			fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil)
+			generator := new(MockToolGenerator)
+			generator.On("Generate", schema).Return([]domain.Tool{tool}, []usecase.InvocationDetails{details}, nil)
+			var handler mcpServer.ToolHandlerFunc
+			mcpSrv := new(MockMCPServer)
+			mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				handler = args.Get(1).(mcpServer.ToolHandlerFunc)
+			})
+
+			uc := usecase.NewSyncSchemaUseCase(
+				[]usecase.SchemaSourceConfig{{URL: sourceURL}},
+				map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+				map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+				mcpSrv,
+				httpinvoker.New(upstream.Client(), logger),
+				logger,
+				usecase.WithRequestIDHeader("X-Request-ID"),
+			)
+			require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+			require.NotNil(t, handler)
+
+			var request mcp.CallToolRequest
+			request.Params.Meta = tt.meta
+			result, err := handler(ctx, request)
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+
+			require.Len(t, received, 1)
+			requestID := received[0]
+			require.NotEmpty(t, requestID)
+			if tt.wantID != "" {
+				assert.Equal(t, tt.wantID, requestID)
+			}
+			if tt.wantNotID != "" {
+				assert.NotEqual(t, tt.wantNotID, requestID)
+				assert.Len(t, requestID, 32, "a new ID is generated")
+			}
+
+			// Every log line of the call, in the handler and the invoker, carries the ID.
+			var callLines int
+			for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+				var entry map[string]any
+				require.NoError(t, json.Unmarshal([]byte(line), &entry))
+				if id, ok := entry["requestId"]; ok {
+					callLines++
+					assert.Equal(t, requestID, id, "log line %q", entry["msg"])
+				}
+			}
+			assert.Greater(t, callLines, 1)
+		})
+	}
+}
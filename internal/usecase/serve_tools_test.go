@@ -54,6 +54,11 @@ func (m *MockToolRepository) FindInvocationDetailsByName(ctx context.Context, na
 	return result.(*usecase.InvocationDetails), args.Error(1)
 }
 
+func (m *MockToolRepository) Delete(ctx context.Context, names ...string) error {
+	args := m.Called(ctx, names)
+	return args.Error(0)
+}
+
 func TestServeToolsUseCase_Execute(t *testing.T) {
 	assert := assert.New(t)
 	ctx := context.Background()
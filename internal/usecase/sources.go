@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+)
+
+var (
+	// ErrReloadInProgress is returned by ReloadSources while another reload is running.
+	ErrReloadInProgress = errors.New("a reload is already in progress")
+	// ErrSourceNotFound is returned by RemoveSource for a source that is neither
+	// configured nor has registered tools.
+	ErrSourceNotFound = errors.New("source not found")
+)
+
+// ReloadResult summarizes how ReloadSources changed the configured source set.
+type ReloadResult struct {
+	Added   []string           // Sources that were not configured before
+	Updated []string           // Sources whose configuration changed
+	Removed []string           // Sources that are no longer configured; their tools were removed
+	Failed  []SourceSyncResult // Added or updated sources that failed to sync
+}
+
+// ReloadSources replaces the configured source set with sources, e.g. after the
+// config file changed. New and changed sources are synced, the tools of sources
+// that are gone are removed, and unchanged sources are left alone. Sources that
+// fail to sync stay configured so that the next SyncAllConfiguredSources retries
+// them. Only one reload runs at a time; a concurrent call returns ErrReloadInProgress.
+func (uc *SyncSchemaUseCase) ReloadSources(ctx context.Context, sources []SchemaSourceConfig) (ReloadResult, error) {
+	if !uc.reloadMu.TryLock() {
+		return ReloadResult{}, ErrReloadInProgress
+	}
+	defer uc.reloadMu.Unlock()
+
+	var result ReloadResult
+	var toSync []SchemaSourceConfig
+
+	uc.sourcesMu.Lock()
+	previous := make(map[string]SchemaSourceConfig, len(uc.schemaSources))
+	for _, source := range uc.schemaSources {
+		previous[source.URL] = source
+	}
+	for _, source := range sources {
+		prev, ok := previous[source.URL]
+		switch {
+		case !ok:
+			result.Added = append(result.Added, source.URL)
+			toSync = append(toSync, source)
+		case !reflect.DeepEqual(prev, source):
+			result.Updated = append(result.Updated, source.URL)
+			toSync = append(toSync, source)
+		}
+		delete(previous, source.URL)
+	}
+	for url := range previous {
+		result.Removed = append(result.Removed, url)
+	}
+	uc.schemaSources = append([]SchemaSourceConfig(nil), sources...)
+	uc.sourcesMu.Unlock()
+
+	sort.Strings(result.Removed)
+	uc.logger.Info("Reloading schema sources.",
+		slog.Any("added", result.Added),
+		slog.Any("updated", result.Updated),
+		slog.Any("removed", result.Removed))
+
+	var errs []error
+	for _, url := range result.Removed {
+		if _, err := uc.removeSourceTools(ctx, url); err != nil {
+			errs = append(errs, fmt.Errorf("source '%s': %w", url, err))
+		}
+	}
+	for _, source := range toSync {
+		if err := uc.processSingleSourceAndRegister(ctx, source); err != nil {
+			uc.logger.Error("Failed to process schema source.", slog.String("source", source.URL), slog.Any("error", err))
+			result.Failed = append(result.Failed, SourceSyncResult{Source: source.URL, Err: err})
+			errs = append(errs, fmt.Errorf("source '%s': %w", source.URL, err))
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// RemoveSource drops source from the configured set and unregisters every tool it
+// produced. It returns the names of the removed tools, or ErrSourceNotFound if the
+// source is neither configured nor has registered tools.
+func (uc *SyncSchemaUseCase) RemoveSource(ctx context.Context, source string) ([]string, error) {
+	uc.sourcesMu.Lock()
+	configured := false
+	for i, existing := range uc.schemaSources {
+		if existing.URL == source {
+			uc.schemaSources = append(uc.schemaSources[:i:i], uc.schemaSources[i+1:]...)
+			configured = true
+			break
+		}
+	}
+	uc.sourcesMu.Unlock()
+
+	names, err := uc.removeSourceTools(ctx, source)
+	if err != nil {
+		return names, err
+	}
+	if !configured && len(names) == 0 {
+		return nil, ErrSourceNotFound
+	}
+	uc.logger.Info("Removed schema source.", slog.String("source", source), slog.Int("removed_count", len(names)))
+	return names, nil
+}
+
+// removeSourceTools unregisters every tool registered from source and removes it
+// from the repository. It returns the names of the removed tools.
+func (uc *SyncSchemaUseCase) removeSourceTools(ctx context.Context, source string) ([]string, error) {
+	uc.limitersMu.Lock()
+	delete(uc.limiters, source)
+	uc.limitersMu.Unlock()
+	uc.tokenFilesMu.Lock()
+	delete(uc.tokenFiles, source)
+	uc.tokenFilesMu.Unlock()
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	var names []string
+	for name, reg := range uc.registered {
+		if reg.source == source {
+			names = append(names, name)
+			delete(uc.registered, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	sort.Strings(names)
+	uc.mcpServer.DeleteTools(names...)
+	uc.recordRegisteredTools(ctx)
+	uc.logger.Info("Removed tools of source.", slog.String("source", source), slog.Any("tools", names))
+	if uc.repository != nil {
+		if err := uc.repository.Delete(ctx, names...); err != nil {
+			return names, fmt.Errorf("failed to remove tools from repository: %w", err)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"log/slog"
+	"sort"
+)
+
+// summarySampleSize is how many tool names a source summary lists.
+const summarySampleSize = 5
+
+// ToolSummary is an overview of the registered tools, grouped by source.
+type ToolSummary struct {
+	Sources []SourceSummary `json:"sources"`
+	Total   int             `json:"total"`
+}
+
+// SourceSummary is the number of tools registered from one source, with the first
+// few of their names in sorted order. Tools restored from the repository and not
+// synced since are listed under an empty source.
+type SourceSummary struct {
+	Source string   `json:"source"`
+	Tools  int      `json:"tools"`
+	Sample []string `json:"sample"`
+}
+
+// Summary returns the registered tools grouped by source, sorted by source URL.
+// Enabled configured sources without tools, e.g. because they failed to sync, are
+// listed with none.
+func (uc *SyncSchemaUseCase) Summary() ToolSummary {
+	names := make(map[string][]string)
+	for _, source := range uc.configuredSources() {
+		if !source.Disabled {
+			names[source.URL] = nil
+		}
+	}
+	uc.mu.Lock()
+	for name, reg := range uc.registered {
+		names[reg.source] = append(names[reg.source], name)
+	}
+	total := len(uc.registered)
+	uc.mu.Unlock()
+
+	summary := ToolSummary{Sources: make([]SourceSummary, 0, len(names)), Total: total}
+	for source, tools := range names {
+		sort.Strings(tools)
+		sample := make([]string, 0, summarySampleSize)
+		sample = append(sample, tools[:min(len(tools), summarySampleSize)]...)
+		summary.Sources = append(summary.Sources, SourceSummary{Source: source, Tools: len(tools), Sample: sample})
+	}
+	sort.Slice(summary.Sources, func(i, j int) bool { return summary.Sources[i].Source < summary.Sources[j].Source })
+	return summary
+}
+
+// LogSummary logs the registered tools per source and in total at info level.
+func (uc *SyncSchemaUseCase) LogSummary() {
+	summary := uc.Summary()
+	for _, source := range summary.Sources {
+		uc.logger.Info("Registered tools of schema source.",
+			slog.String("source", source.Source),
+			slog.Int("tool_count", source.Tools),
+			slog.Any("tools", source.Sample))
+	}
+	uc.logger.Info("Registered tools summary.",
+		slog.Int("source_count", len(summary.Sources)),
+		slog.Int("total_tools", summary.Total))
+}
@@ -0,0 +1,68 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/domain"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+func TestSyncSchemaUseCase_Summary(t *testing.T) {
+	ctx := context.Background()
+	petsURL := "http://pets.example.com/openapi.yaml"
+	storeURL := "http://store.example.com/openapi.yaml"
+	downURL := "http://down.example.com/openapi.yaml"
+	petsSchema := domain.APISchema{Source: petsURL, Type: domain.SchemaTypeOpenAPI}
+	storeSchema := domain.APISchema{Source: storeURL, Type: domain.SchemaTypeOpenAPI}
+
+	var petTools []domain.Tool
+	var petDetails []usecase.InvocationDetails
+	for i := 7; i > 0; i-- {
+		petTools = append(petTools, domain.Tool{Name: fmt.Sprintf("pets_op%d", i)})
+		petDetails = append(petDetails, usecase.InvocationDetails{Type: "http", Host: "http://pets.example.com", HTTPMethod: "GET", HTTPPath: "/pets"})
+	}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(petsURL)).Return(petsSchema, nil)
+	fetcher.On("FetchWithConfig", ctx, configFor(storeURL)).Return(storeSchema, nil)
+	fetcher.On("FetchWithConfig", ctx, configFor(downURL)).Return(domain.APISchema{}, errors.New("connection refused"))
+	generator := new(MockToolGenerator)
+	generator.On("Generate", petsSchema).Return(petTools, petDetails, nil)
+	generator.On("Generate", storeSchema).Return(
+		[]domain.Tool{{Name: "store_order"}},
+		[]usecase.InvocationDetails{{Type: "http", Host: "http://store.example.com", HTTPMethod: "POST", HTTPPath: "/order"}},
+		nil,
+	)
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything)
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: storeURL}, {URL: petsURL}, {URL: downURL}, {URL: "http://off.example.com/openapi.yaml", Disabled: true}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		new(MockToolInvoker),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	require.Error(t, uc.SyncAllConfiguredSources(ctx))
+
+	// Sources are sorted, only the first few names of a source are listed, and a
+	// source that failed to sync is listed without tools.
+	assert.Equal(t, usecase.ToolSummary{
+		Sources: []usecase.SourceSummary{
+			{Source: downURL, Tools: 0, Sample: []string{}},
+			{Source: petsURL, Tools: 7, Sample: []string{"pets_op1", "pets_op2", "pets_op3", "pets_op4", "pets_op5"}},
+			{Source: storeURL, Tools: 1, Sample: []string{"store_order"}},
+		},
+		Total: 8,
+	}, uc.Summary())
+}
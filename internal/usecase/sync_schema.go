@@ -2,25 +2,171 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"regexp"
+	"runtime/debug"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/i2y/mcpizer/internal/domain"
 )
 
 // SyncSchemaUseCase orchestrates fetching, generating, and registering tools with an MCP server.
 type SyncSchemaUseCase struct {
-	fetchers      map[domain.SchemaType]SchemaFetcher
-	generators    map[domain.SchemaType]ToolGenerator
-	mcpServer     MCPServerAdapter // Use the interface type
-	invoker       ToolInvoker
-	logger        *slog.Logger
+	fetchers   map[domain.SchemaType]SchemaFetcher
+	generators map[domain.SchemaType]ToolGenerator
+	mcpServer  MCPServerAdapter // Use the interface type
+	invoker    ToolInvoker
+	logger     *slog.Logger
+
+	// schemaSources is the configured source set; SyncSources adds to it at runtime.
+	sourcesMu     sync.RWMutex
 	schemaSources []SchemaSourceConfig
+	reloadMu      sync.Mutex // Serializes ReloadSources
+
+	// registered tracks what is currently registered with the MCP server so that a
+	// re-sync only adds changed tools and removes vanished ones.
+	mu         sync.Mutex
+	registered map[string]registeredTool
+
+	toolNamePrefix string
+	toolNameSuffix string
+
+	// repository, when set, mirrors the registered tools and their invocation details
+	// so that they can be listed and looked up outside the MCP server.
+	repository ToolRepository
+
+	// invocationTimeout bounds every tool call, including time spent queued for
+	// the source's in-flight limit. Zero leaves calls bounded only by their context.
+	invocationTimeout time.Duration
+
+	// maxToolsPerSource and maxTools cap how many tools a source (unless it sets
+	// SchemaSourceConfig.MaxTools) and all sources together register. Zero is unlimited.
+	maxToolsPerSource int
+	maxTools          int
+
+	// decodeByteFields shows base64 "format: byte" result fields as their decoded text.
+	decodeByteFields bool
+
+	// requestIDHeader, when set, carries each tool call's request ID to the upstream.
+	requestIDHeader string
+
+	// limiters bounds concurrent invocations per source URL (see SchemaSourceConfig.MaxInFlight).
+	limitersMu sync.Mutex
+	limiters   map[string]*inFlightLimiter
+
+	// tokenFiles holds the credentials read from files per source URL (see
+	// SchemaSourceConfig.AuthTokenFile).
+	tokenFilesMu sync.Mutex
+	tokenFiles   map[string]*tokenFile
+
+	// calls tracks the tool calls in flight for Drain.
+	calls callTracker
+}
+
+// SyncOption configures optional SyncSchemaUseCase behavior.
+type SyncOption func(*SyncSchemaUseCase)
+
+// WithToolNameAffix adds prefix and suffix to every generated tool name, e.g. to
+// namespace the tools of several mcpizer instances feeding one client.
+func WithToolNameAffix(prefix, suffix string) SyncOption {
+	return func(uc *SyncSchemaUseCase) {
+		uc.toolNamePrefix = prefix
+		uc.toolNameSuffix = suffix
+	}
+}
+
+// WithInvocationTimeout bounds every tool call to d, so that a hung upstream
+// cannot tie up the handler when the MCP request carries no deadline.
+func WithInvocationTimeout(d time.Duration) SyncOption {
+	return func(uc *SyncSchemaUseCase) {
+		uc.invocationTimeout = d
+	}
+}
+
+// WithMaxTools caps the tools registered per source and in total, so that a buggy or
+// huge spec cannot flood the client. Tools beyond a cap are dropped with a warning.
+// Zero leaves the respective count unlimited.
+func WithMaxTools(perSource, total int) SyncOption {
+	return func(uc *SyncSchemaUseCase) {
+		uc.maxToolsPerSource = perSource
+		uc.maxTools = total
+	}
+}
+
+// WithByteFieldDecoding replaces the base64 values of result fields whose output
+// schema has "format: byte" (e.g., proto bytes fields) with their decoded text when
+// they decode to UTF-8. Binary values stay base64.
+func WithByteFieldDecoding(enabled bool) SyncOption {
+	return func(uc *SyncSchemaUseCase) {
+		uc.decodeByteFields = enabled
+	}
+}
+
+// registeredTool records the source and content fingerprint of a registered tool.
+type registeredTool struct {
+	source      string
+	fingerprint string
+}
+
+// fetchedSchemaTypes are the schema types a source can be detected or configured
+// as, each of which needs a fetcher.
+var fetchedSchemaTypes = []domain.SchemaType{
+	domain.SchemaTypeOpenAPI,
+	domain.SchemaTypeGRPC,
+	domain.SchemaTypeProto,
+	domain.SchemaTypeConnect,
+	domain.SchemaTypeGitHub,
+}
+
+// generatedSchemaTypes are the schema types fetchers produce, each of which
+// needs a generator. GitHub sources resolve to OpenAPI or proto when fetched.
+var generatedSchemaTypes = []domain.SchemaType{
+	domain.SchemaTypeOpenAPI,
+	domain.SchemaTypeGRPC,
+	domain.SchemaTypeProto,
+	domain.SchemaTypeConnect,
+	domain.SchemaTypeConnectProto,
+}
+
+// CheckRegistrations reports every schema type that has no fetcher or no
+// generator, so that missing wiring fails at startup instead of per source.
+func CheckRegistrations(fetchers map[domain.SchemaType]SchemaFetcher, generators map[domain.SchemaType]ToolGenerator) error {
+	var errs []error
+	for _, schemaType := range fetchedSchemaTypes {
+		if _, ok := fetchers[schemaType]; !ok {
+			errs = append(errs, fmt.Errorf("no schema fetcher registered for type %s", schemaType))
+		}
+	}
+	for _, schemaType := range generatedSchemaTypes {
+		if _, ok := generators[schemaType]; !ok {
+			errs = append(errs, fmt.Errorf("no tool generator registered for type %s", schemaType))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithToolRepository stores every registered tool and its invocation details in repo,
+// and removes them again when their source stops providing them.
+func WithToolRepository(repo ToolRepository) SyncOption {
+	return func(uc *SyncSchemaUseCase) {
+		uc.repository = repo
+	}
 }
 
 // NewSyncSchemaUseCase creates a new SyncSchemaUseCase.
@@ -31,6 +177,7 @@ func NewSyncSchemaUseCase(
 	mcpSrv MCPServerAdapter, // Use the interface type
 	invoker ToolInvoker,
 	logger *slog.Logger,
+	opts ...SyncOption,
 ) *SyncSchemaUseCase {
 	// Basic validation
 	if mcpSrv == nil {
@@ -39,25 +186,33 @@ func NewSyncSchemaUseCase(
 	if invoker == nil {
 		panic("NewSyncSchemaUseCase requires a non-nil invoker")
 	}
-	return &SyncSchemaUseCase{
+	uc := &SyncSchemaUseCase{
 		fetchers:      fetchers,
 		generators:    generators,
 		mcpServer:     mcpSrv,
 		invoker:       invoker,
 		logger:        logger.With("usecase", "SyncSchema"),
-		schemaSources: schemaSources,
+		schemaSources: append([]SchemaSourceConfig(nil), schemaSources...),
+		registered:    make(map[string]registeredTool),
+		limiters:      make(map[string]*inFlightLimiter),
+		tokenFiles:    make(map[string]*tokenFile),
+	}
+	for _, opt := range opts {
+		opt(uc)
 	}
+	return uc
 }
 
 // SyncAllConfiguredSources fetches schemas from all configured sources,
 // generates tools for each, and registers them with the MCP server.
 // It returns a joined error if any source fails, but attempts to process all sources.
 func (uc *SyncSchemaUseCase) SyncAllConfiguredSources(ctx context.Context) error {
-	uc.logger.Info("Starting sync for all configured schema sources.", slog.Int("source_count", len(uc.schemaSources)))
+	sources := uc.configuredSources()
+	uc.logger.Info("Starting sync for all configured schema sources.", slog.Int("source_count", len(sources)))
 
 	var syncErrors []error
 
-	for _, source := range uc.schemaSources {
+	for _, source := range sources {
 		log := uc.logger.With(slog.String("source", source.URL))
 		log.Info("Processing schema source.")
 
@@ -74,12 +229,599 @@ func (uc *SyncSchemaUseCase) SyncAllConfiguredSources(ctx context.Context) error
 		return errors.Join(syncErrors...)
 	}
 
+	// Every source synced, so restored tools that no source provides anymore are gone upstream.
+	if err := uc.pruneRestoredTools(ctx); err != nil {
+		return err
+	}
+
 	uc.logger.Info("Successfully synced and registered tools for all configured schema sources.")
 	return nil
 }
 
+// configuredSources returns a snapshot of the configured source set.
+func (uc *SyncSchemaUseCase) configuredSources() []SchemaSourceConfig {
+	uc.sourcesMu.RLock()
+	defer uc.sourcesMu.RUnlock()
+	return append([]SchemaSourceConfig(nil), uc.schemaSources...)
+}
+
+// SourceSyncResult is the outcome of syncing one source with SyncSources.
+type SourceSyncResult struct {
+	Source string
+	Err    error
+}
+
+// SyncSources syncs each of sources and returns one result per source, in order.
+// Every source is processed even if some fail. Sources that sync successfully
+// are added to the configured set, replacing a configured source with the same
+// URL, so that SyncAllConfiguredSources keeps them up to date.
+func (uc *SyncSchemaUseCase) SyncSources(ctx context.Context, sources []SchemaSourceConfig) []SourceSyncResult {
+	uc.logger.Info("Starting sync for pushed schema sources.", slog.Int("source_count", len(sources)))
+
+	results := make([]SourceSyncResult, 0, len(sources))
+	for _, source := range sources {
+		log := uc.logger.With(slog.String("source", source.URL))
+		log.Info("Processing schema source.")
+
+		if err := uc.processSingleSourceAndRegister(ctx, source); err != nil {
+			log.Error("Failed to process schema source.", slog.Any("error", err))
+			results = append(results, SourceSyncResult{Source: source.URL, Err: err})
+			continue
+		}
+		uc.addConfiguredSource(source)
+		log.Info("Successfully processed and registered tools for schema source.")
+		results = append(results, SourceSyncResult{Source: source.URL})
+	}
+	return results
+}
+
+// addConfiguredSource adds source to the configured set, replacing the entry with the same URL.
+func (uc *SyncSchemaUseCase) addConfiguredSource(source SchemaSourceConfig) {
+	uc.sourcesMu.Lock()
+	defer uc.sourcesMu.Unlock()
+	for i, existing := range uc.schemaSources {
+		if existing.URL == source.URL {
+			uc.schemaSources[i] = source
+			return
+		}
+	}
+	uc.schemaSources = append(uc.schemaSources, source)
+}
+
+// restoredSource marks tools registered from the repository by RestoreTools
+// that have not been provided by a source since.
+const restoredSource = ""
+
+// RestoreTools registers the tools stored in the repository, e.g. persisted by a
+// previous run, so that the server is usable before the first sync completes.
+// It returns the number of restored tools; without a repository it does nothing.
+// Restored tools are replaced as their sources sync, and the ones no source
+// provides anymore are removed after the next fully successful SyncAllConfiguredSources.
+func (uc *SyncSchemaUseCase) RestoreTools(ctx context.Context) (int, error) {
+	if uc.repository == nil {
+		return 0, nil
+	}
+	tools, err := uc.repository.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list stored tools: %w", err)
+	}
+
+	// Restored tools of sources with a token file send its credential right away.
+	for _, source := range uc.configuredSources() {
+		if source.AuthTokenFile == "" || source.Disabled {
+			continue
+		}
+		if err := uc.updateTokenFile(source); err != nil {
+			uc.logger.Warn("Failed to read token file for restored tools.", slog.String("source", source.URL), slog.Any("error", err))
+		}
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	restored := 0
+	for _, domainTool := range tools {
+		log := uc.logger.With(slog.String("toolName", domainTool.Name))
+		if _, ok := uc.registered[domainTool.Name]; ok {
+			continue // Already registered by a sync
+		}
+		details, err := uc.repository.FindInvocationDetailsByName(ctx, domainTool.Name)
+		if err != nil {
+			log.Warn("Skipping stored tool without invocation details.", slog.Any("error", err))
+			continue
+		}
+		mcpTool, err := uc.convertDomainToolToMCPTool(domainTool)
+		if err != nil {
+			log.Warn("Skipping stored tool that cannot be converted.", slog.Any("error", err))
+			continue
+		}
+		uc.mcpServer.AddTool(*mcpTool, uc.createToolHandler(*details, domainTool, restoredSource))
+		uc.registered[mcpTool.Name] = registeredTool{source: restoredSource, fingerprint: toolFingerprint(*mcpTool, *details)}
+		restored++
+	}
+	uc.recordRegisteredTools(ctx)
+	uc.logger.Info("Restored tools from repository.", slog.Int("count", restored))
+	return restored, nil
+}
+
+// pruneRestoredTools removes restored tools that no source has provided since.
+func (uc *SyncSchemaUseCase) pruneRestoredTools(ctx context.Context) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	var stale []string
+	for name, reg := range uc.registered {
+		if reg.source == restoredSource {
+			stale = append(stale, name)
+			delete(uc.registered, name)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	sort.Strings(stale)
+	uc.mcpServer.DeleteTools(stale...)
+	uc.recordRegisteredTools(ctx)
+	uc.logger.Info("Removed restored tools no longer provided by any source.", slog.Any("tools", stale))
+	if err := uc.repository.Delete(ctx, stale...); err != nil {
+		return fmt.Errorf("failed to remove stale tools from repository: %w", err)
+	}
+	return nil
+}
+
+// GeneratedTool is a tool generated from a schema source, together with its invocation details.
+type GeneratedTool struct {
+	Source  string
+	Tool    domain.Tool
+	Details InvocationDetails
+}
+
+// GenerateAll fetches every configured source and generates its tools without
+// registering them with the MCP server, e.g. to inspect or snapshot the tool set.
+// Like SyncAllConfiguredSources it processes all sources and returns a joined error
+// for the ones that failed, alongside the tools of the ones that succeeded.
+func (uc *SyncSchemaUseCase) GenerateAll(ctx context.Context) ([]GeneratedTool, error) {
+	var generated []GeneratedTool
+	var genErrors []error
+
+	for _, source := range uc.configuredSources() {
+		if source.Disabled {
+			continue
+		}
+		tools, detailsList, _, err := uc.fetchAndGenerate(ctx, source)
+		if err != nil {
+			uc.logger.Error("Failed to generate tools for schema source.", slog.String("source", source.URL), slog.Any("error", err))
+			genErrors = append(genErrors, fmt.Errorf("source '%s': %w", source.URL, err))
+			continue
+		}
+		for i, tool := range tools {
+			if i >= len(detailsList) {
+				uc.logger.Error("Mismatch between tools and details lists", slog.String("toolName", tool.Name))
+				continue
+			}
+			generated = append(generated, GeneratedTool{Source: source.URL, Tool: tool, Details: detailsList[i]})
+		}
+	}
+
+	return generated, errors.Join(genErrors...)
+}
+
 // processSingleSourceAndRegister handles fetching, generating, and registering tools for one source.
-func (uc *SyncSchemaUseCase) processSingleSourceAndRegister(ctx context.Context, source SchemaSourceConfig) error {
+func (uc *SyncSchemaUseCase) processSingleSourceAndRegister(ctx context.Context, source SchemaSourceConfig) (err error) {
+	log := uc.logger.With(slog.String("source", source.URL))
+
+	// A disabled source provides no tools, so any it registered before are removed.
+	if source.Disabled {
+		names, err := uc.removeSourceTools(ctx, source.URL)
+		if err != nil {
+			return err
+		}
+		log.Info("Skipped disabled schema source.", slog.Int("removed_count", len(names)))
+		return nil
+	}
+
+	sourceAttr := attribute.String("source", source.URL)
+	defer func() {
+		sourceSyncCounter.Add(ctx, 1, metric.WithAttributes(sourceAttr, attribute.Bool("success", err == nil)))
+	}()
+
+	if err := uc.updateTokenFile(source); err != nil {
+		return err
+	}
+
+	tools, detailsList, _, err := uc.fetchAndGenerate(ctx, source)
+	if err != nil {
+		return err
+	}
+	toolsGeneratedCounter.Add(ctx, int64(len(tools)), metric.WithAttributes(sourceAttr))
+
+	uc.updateLimiter(source)
+	ranks := sourceRanks(uc.configuredSources())
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.maxTools > 0 {
+		// Sources earlier in the configuration take precedence, whichever syncs first.
+		earlier := uc.toolsOfEarlierSources(ranks, source.URL)
+		if allowed := max(uc.maxTools-earlier, 0); len(tools) > allowed {
+			log.Warn("Source exceeds the total tool limit, dropping the remaining tools.",
+				slog.Int("limit", uc.maxTools), slog.Int("earlier_sources_tools", earlier),
+				slog.Int("generated", len(tools)), slog.Int("kept", allowed))
+			sortToolsByName(tools, detailsList)
+			tools = dropLinksToMissingTools(tools[:allowed])
+		}
+	}
+
+	tools = uc.disambiguateToolNames(log, tools, source.URL)
+
+	registeredCount, unchangedCount := 0, 0
+	seen := make(map[string]struct{}, len(tools))
+	var savedTools []domain.Tool
+	var savedDetails []InvocationDetails
+	for i, domainTool := range tools {
+		toolName := domainTool.Name
+		if i >= len(detailsList) {
+			log.Error("Mismatch between tools and details lists", slog.String("toolName", toolName))
+			continue
+		}
+		invocationDetails := detailsList[i]
+
+		mcpTool, err := uc.convertDomainToolToMCPTool(domainTool)
+		if err != nil {
+			log.Error("Failed to convert domain tool to MCP tool, skipping registration.", slog.String("toolName", toolName), slog.Any("error", err))
+			continue
+		}
+		seen[mcpTool.Name] = struct{}{}
+		savedTools = append(savedTools, domainTool)
+		saved := invocationDetails
+		saved.Source = source.URL
+		savedDetails = append(savedDetails, saved)
+
+		// Skip re-registering tools that are unchanged since the last sync to avoid client churn.
+		// Restored tools are re-registered once so their handler is bound to this source.
+		fingerprint := toolFingerprint(*mcpTool, invocationDetails)
+		if prev, ok := uc.registered[mcpTool.Name]; ok && fingerprint != "" && prev.fingerprint == fingerprint && prev.source == source.URL {
+			unchangedCount++
+			continue
+		}
+
+		handlerFunc := uc.createToolHandler(invocationDetails, domainTool, source.URL)
+
+		uc.mcpServer.AddTool(*mcpTool, handlerFunc)
+		uc.registered[mcpTool.Name] = registeredTool{source: source.URL, fingerprint: fingerprint}
+		log.Debug("Registered tool with MCP server", slog.String("toolName", mcpTool.Name))
+		registeredCount++
+	}
+
+	// Remove tools this source registered previously but no longer provides.
+	var staleTools []string
+	for name, reg := range uc.registered {
+		if _, ok := seen[name]; !ok && reg.source == source.URL {
+			staleTools = append(staleTools, name)
+			delete(uc.registered, name)
+		}
+	}
+	if len(staleTools) > 0 {
+		sort.Strings(staleTools)
+		uc.mcpServer.DeleteTools(staleTools...)
+		log.Info("Removed tools no longer provided by source.", slog.Any("tools", staleTools))
+	}
+	if uc.maxTools > 0 {
+		// Tools of later sources that no longer fit under the total limit make way.
+		if evicted := uc.evictToolsOverLimit(ranks); len(evicted) > 0 {
+			uc.mcpServer.DeleteTools(evicted...)
+			log.Warn("Removed tools of later sources that exceed the total tool limit.",
+				slog.Int("limit", uc.maxTools), slog.Any("tools", evicted))
+			staleTools = append(staleTools, evicted...)
+		}
+	}
+	uc.recordRegisteredTools(ctx)
+
+	if uc.repository != nil {
+		if len(staleTools) > 0 {
+			if err := uc.repository.Delete(ctx, staleTools...); err != nil {
+				return fmt.Errorf("failed to remove stale tools from repository: %w", err)
+			}
+		}
+		if err := uc.repository.Save(ctx, savedTools, savedDetails); err != nil {
+			return fmt.Errorf("failed to store tools in repository: %w", err)
+		}
+	}
+
+	log.Info("Finished processing source, registered tools.",
+		slog.Int("registered_count", registeredCount),
+		slog.Int("unchanged_count", unchangedCount),
+		slog.Int("removed_count", len(staleTools)))
+	return nil
+}
+
+// sourceRanks maps the URL of each source to its position in sources.
+func sourceRanks(sources []SchemaSourceConfig) map[string]int {
+	ranks := make(map[string]int, len(sources))
+	for i, source := range sources {
+		ranks[source.URL] = i
+	}
+	return ranks
+}
+
+// sourceBefore reports whether source a precedes source b for the total tool limit:
+// configured sources in configuration order, then the others by URL.
+func sourceBefore(ranks map[string]int, a, b string) bool {
+	rankA, okA := ranks[a]
+	rankB, okB := ranks[b]
+	switch {
+	case okA && okB:
+		return rankA < rankB
+	case okA != okB:
+		return okA
+	default:
+		return a < b
+	}
+}
+
+// toolsOfEarlierSources counts the registered tools of sources preceding source (see
+// sourceBefore). Restored tools are about to be replaced by their sources, so only
+// synced ones count. Callers hold uc.mu.
+func (uc *SyncSchemaUseCase) toolsOfEarlierSources(ranks map[string]int, source string) int {
+	count := 0
+	for _, reg := range uc.registered {
+		if reg.source != restoredSource && reg.source != source && sourceBefore(ranks, reg.source, source) {
+			count++
+		}
+	}
+	return count
+}
+
+// evictToolsOverLimit unregisters the synced tools beyond the total tool limit,
+// counting sources in order (see sourceBefore) and each source's tools by name, and
+// returns their names. Callers hold uc.mu and delete the tools from the MCP server.
+func (uc *SyncSchemaUseCase) evictToolsOverLimit(ranks map[string]int) []string {
+	bySource := make(map[string][]string)
+	var sources []string
+	for name, reg := range uc.registered {
+		if reg.source == restoredSource {
+			continue
+		}
+		if _, ok := bySource[reg.source]; !ok {
+			sources = append(sources, reg.source)
+		}
+		bySource[reg.source] = append(bySource[reg.source], name)
+	}
+	sort.Slice(sources, func(i, j int) bool { return sourceBefore(ranks, sources[i], sources[j]) })
+
+	var evicted []string
+	kept := 0
+	for _, source := range sources {
+		names := bySource[source]
+		sort.Strings(names)
+		for _, name := range names {
+			if kept < uc.maxTools {
+				kept++
+				continue
+			}
+			evicted = append(evicted, name)
+			delete(uc.registered, name)
+		}
+	}
+	return evicted
+}
+
+// sortToolsByName stably sorts tools by name, keeping detailsList aligned with them,
+// so that a limit keeps the same tools on every sync whatever order the generator
+// produced them in.
+func sortToolsByName(tools []domain.Tool, detailsList []InvocationDetails) {
+	sort.Stable(toolsByName{tools: tools, details: detailsList})
+}
+
+// toolsByName sorts tools and their aligned invocation details by tool name.
+type toolsByName struct {
+	tools   []domain.Tool
+	details []InvocationDetails
+}
+
+func (s toolsByName) Len() int           { return len(s.tools) }
+func (s toolsByName) Less(i, j int) bool { return s.tools[i].Name < s.tools[j].Name }
+func (s toolsByName) Swap(i, j int) {
+	s.tools[i], s.tools[j] = s.tools[j], s.tools[i]
+	if i < len(s.details) && j < len(s.details) {
+		s.details[i], s.details[j] = s.details[j], s.details[i]
+	}
+}
+
+// recordRegisteredTools records the current number of registered tools. Callers hold uc.mu.
+func (uc *SyncSchemaUseCase) recordRegisteredTools(ctx context.Context) {
+	registeredToolsGauge.Record(ctx, int64(len(uc.registered)))
+}
+
+// disambiguateToolNames renames the tools whose name another source registered first,
+// suffixing it with a hash of sourceURL (see sourceToolName), and updates links to
+// them, so that neither source's tool replaces the other's. Restored tools do not
+// count, as their sources replace them. uc.mu must be held.
+func (uc *SyncSchemaUseCase) disambiguateToolNames(log *slog.Logger, tools []domain.Tool, sourceURL string) []domain.Tool {
+	renamed := make(map[string]string)
+	for i := range tools {
+		prev, ok := uc.registered[tools[i].Name]
+		if !ok || prev.source == sourceURL || prev.source == restoredSource {
+			continue
+		}
+		name := sourceToolName(tools[i].Name, sourceURL)
+		log.Warn("Tool name is already registered by another source, registering this source's tool under a suffixed name.",
+			slog.String("toolName", tools[i].Name), slog.String("registered_as", name), slog.String("previous_source", prev.source))
+		renamed[tools[i].Name] = name
+		tools[i].Name = name
+	}
+	if len(renamed) == 0 {
+		return tools
+	}
+	for i := range tools {
+		for j := range tools[i].Links {
+			if name, ok := renamed[tools[i].Links[j].Tool]; ok {
+				tools[i].Links[j].Tool = name
+			}
+		}
+	}
+	return tools
+}
+
+// fetchAndGenerate fetches one source's schema and generates its tools and invocation details,
+// then post-processes the tool names uniformly for every schema type. Names are shortened to
+// MaxToolNameLength last, after any version namespace and affixes were added.
+func (uc *SyncSchemaUseCase) fetchAndGenerate(ctx context.Context, source SchemaSourceConfig) ([]domain.Tool, []InvocationDetails, []SkippedOperation, error) {
+	tools, detailsList, skipped, err := uc.fetchSchemaTools(ctx, source)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for i := range tools {
+		tools[i].Name = affixToolName(uc.toolNamePrefix, tools[i].Name, uc.toolNameSuffix)
+		for j := range tools[i].Links {
+			tools[i].Links[j].Tool = affixToolName(uc.toolNamePrefix, tools[i].Links[j].Tool, uc.toolNameSuffix)
+		}
+	}
+	if source.AcceptLanguage != "" {
+		for i := range detailsList {
+			detailsList[i].AcceptLanguage = source.AcceptLanguage
+		}
+	}
+	if len(source.ResponseHeaders) > 0 {
+		for i := range detailsList {
+			if detailsList[i].Type == "http" {
+				detailsList[i].ResponseHeaders = source.ResponseHeaders
+				if i < len(tools) {
+					tools[i].OutputSchema = responseHeadersSchema(tools[i].OutputSchema, source.ResponseHeaders)
+				}
+			}
+		}
+	}
+	if len(source.StaticQueryParams) > 0 || len(source.StaticHeaders) > 0 || len(source.StaticBodyParams) > 0 {
+		for i := range detailsList {
+			if detailsList[i].Type == "http" {
+				addStaticParams(&detailsList[i], source)
+			}
+		}
+	}
+	if source.TLSCAFile != "" || source.TLSInsecureSkipVerify {
+		for i := range detailsList {
+			if detailsList[i].Type == "http" {
+				detailsList[i].TLSCAFile = source.TLSCAFile
+				detailsList[i].TLSInsecureSkipVerify = source.TLSInsecureSkipVerify
+			}
+		}
+	}
+	if source.ContentType != "" {
+		for i := range detailsList {
+			// Only HTTP operations that send a body have a content type to override.
+			if detailsList[i].Type == "http" && detailsList[i].ContentType != "" {
+				detailsList[i].ContentType = source.ContentType
+			}
+		}
+	}
+	// The base path is replaced first, so that a path rewrite sees the configured one.
+	if source.BasePath != "" {
+		for i := range detailsList {
+			if detailsList[i].Type == "http" {
+				detailsList[i].BasePath = source.BasePath
+			}
+		}
+	}
+	if source.PathRewrite != nil {
+		rewritePaths(detailsList, source.PathRewrite, source.PathRewriteReplacement)
+	}
+	if source.LoadBalancing != "" {
+		for i := range detailsList {
+			if detailsList[i].Type == "grpc" {
+				detailsList[i].LoadBalancing = source.LoadBalancing
+			}
+		}
+	}
+	limit := source.MaxTools
+	if limit == 0 {
+		limit = uc.maxToolsPerSource
+	}
+	if limit > 0 && len(tools) > limit {
+		uc.logger.Warn("Source generated more tools than its limit, dropping the remaining tools.",
+			slog.String("source", source.URL), slog.Int("limit", limit), slog.Int("generated", len(tools)))
+		sortToolsByName(tools, detailsList)
+		tools = dropLinksToMissingTools(tools[:limit])
+		if len(detailsList) > limit {
+			detailsList = detailsList[:limit]
+		}
+	}
+	return tools, detailsList, skipped, nil
+}
+
+// rewritePaths replaces the matches of re in the request path of each HTTP
+// operation, given as its base path joined with its path, by replacement. The
+// rewritten path becomes the operation's path and the base path is cleared.
+func rewritePaths(detailsList []InvocationDetails, re *regexp.Regexp, replacement string) {
+	for i := range detailsList {
+		details := &detailsList[i]
+		if details.Type != "http" {
+			continue
+		}
+		fullPath := details.HTTPPath
+		if details.BasePath != "" {
+			fullPath = strings.TrimRight(details.BasePath, "/") + "/" + strings.TrimLeft(details.HTTPPath, "/")
+		}
+		details.HTTPPath = re.ReplaceAllString(fullPath, replacement)
+		details.BasePath = ""
+	}
+}
+
+// responseHeadersSchema returns the output schema of a tool whose results carry the
+// named response headers next to the body, as {"body": ..., "headers": {...}}. An
+// unknown body schema stays unknown.
+func responseHeadersSchema(body *domain.JSONSchemaProps, names []string) *domain.JSONSchemaProps {
+	headers := domain.JSONSchemaProps{Type: "object", Properties: make(map[string]domain.JSONSchemaProps, len(names))}
+	for _, name := range names {
+		headers.Properties[name] = domain.JSONSchemaProps{Type: "string"}
+	}
+	schema := &domain.JSONSchemaProps{
+		Type:       "object",
+		Properties: map[string]domain.JSONSchemaProps{"headers": headers},
+		Required:   []string{"body", "headers"},
+	}
+	if body != nil {
+		schema.Properties["body"] = *body
+	}
+	return schema
+}
+
+// addStaticParams merges the source's static parameters into details. The maps are
+// copied, as generated details may share them.
+func addStaticParams(details *InvocationDetails, source SchemaSourceConfig) {
+	details.StaticQueryParams = mergeStrings(details.StaticQueryParams, source.StaticQueryParams)
+	details.HeaderParams = mergeStrings(details.HeaderParams, source.StaticHeaders)
+	if len(source.StaticBodyParams) > 0 {
+		body := make(map[string]interface{}, len(details.StaticBodyParams)+len(source.StaticBodyParams))
+		for k, v := range details.StaticBodyParams {
+			body[k] = v
+		}
+		for k, v := range source.StaticBodyParams {
+			body[k] = v
+		}
+		details.StaticBodyParams = body
+	}
+}
+
+// mergeStrings returns a copy of base with extra's entries set, or base itself when
+// extra is empty.
+func mergeStrings(base, extra map[string]string) map[string]string {
+	if len(extra) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(extra))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
+
+// fetchSchemaTools detects the source's type, fetches its schema, and generates its tools.
+func (uc *SyncSchemaUseCase) fetchSchemaTools(ctx context.Context, source SchemaSourceConfig) ([]domain.Tool, []InvocationDetails, []SkippedOperation, error) {
 	log := uc.logger.With(slog.String("source", source.URL))
 
 	// Check if schema type is explicitly configured
@@ -90,9 +832,10 @@ func (uc *SyncSchemaUseCase) processSingleSourceAndRegister(ctx context.Context,
 		log.Debug("Using explicitly configured schema type", slog.String("type", source.Type))
 	} else {
 		// Auto-detect schema type
-		schemaType = uc.determineSchemaType(source.URL)
-		if schemaType == "" {
-			return fmt.Errorf("could not determine schema type from source format")
+		var err error
+		schemaType, err = uc.determineSchemaType(source.URL)
+		if err != nil {
+			return nil, nil, nil, err
 		}
 	}
 	log = log.With(slog.String("detected_type", string(schemaType)))
@@ -101,87 +844,257 @@ func (uc *SyncSchemaUseCase) processSingleSourceAndRegister(ctx context.Context,
 	var fetcher SchemaFetcher
 	var ok bool
 	if strings.HasPrefix(source.URL, "github://") {
-		// Check if we have a GitHub fetcher registered
-		fetcher, ok = uc.fetchers[domain.SchemaType("github")]
+		// Only the GitHub fetcher can read github:// URLs; it dispatches to OpenAPI or proto
+		// parsing itself based on the file it finds.
+		fetcher, ok = uc.fetchers[domain.SchemaTypeGitHub]
 		if !ok {
-			// Fall back to the appropriate fetcher based on file type
-			fetcher, ok = uc.fetchers[schemaType]
+			return nil, nil, nil, fmt.Errorf("no schema fetcher available for github:// source")
 		}
-	} else if strings.HasSuffix(source.URL, ".proto") {
-		// .proto files always use the proto fetcher, regardless of configured type
+	} else if isProtoFile(source.URL) {
+		// .proto files and descriptor sets always use the proto fetcher, regardless of configured type
 		fetcher, ok = uc.fetchers[domain.SchemaTypeProto]
 	} else {
 		fetcher, ok = uc.fetchers[schemaType]
 	}
 
 	if !ok {
-		return fmt.Errorf("no schema fetcher available for type %s", schemaType)
+		return nil, nil, nil, fmt.Errorf("no schema fetcher available for type %s", schemaType)
 	}
 
-	// Use FetchWithConfig if headers are provided or if it's a .proto file with server or if type/mode is configured
-	var fetchedSchema domain.APISchema
-	var err error
-	if len(source.Headers) > 0 || (schemaType == domain.SchemaTypeProto && source.Server != "") || source.Type != "" || source.Mode != "" {
-		fetchedSchema, err = fetcher.FetchWithConfig(ctx, source)
-		if err != nil {
-			return fmt.Errorf("failed to fetch schema with config: %w", err)
+	source = withAcceptLanguageHeader(source)
+
+	if source.DiscoverAll {
+		return uc.fetchAllAndGenerate(ctx, log, fetcher, schemaType, source)
+	}
+
+	fetchedSchema, err := fetcher.FetchWithConfig(ctx, source)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to fetch schema: %w", err)
+	}
+	fetchedSchema.ResponseStatuses = source.ResponseStatuses
+	return uc.generateForSchema(log, schemaType, fetchedSchema)
+}
+
+// withAcceptLanguageHeader returns source with its AcceptLanguage added to the
+// fetch headers, unless they already set Accept-Language. The headers are copied
+// so that the configured source is left untouched.
+func withAcceptLanguageHeader(source SchemaSourceConfig) SchemaSourceConfig {
+	if source.AcceptLanguage == "" {
+		return source
+	}
+	for name := range source.Headers {
+		if strings.EqualFold(name, "Accept-Language") {
+			return source
 		}
-	} else {
-		fetchedSchema, err = fetcher.Fetch(ctx, source.URL)
+	}
+	headers := make(map[string]string, len(source.Headers)+1)
+	for name, value := range source.Headers {
+		headers[name] = value
+	}
+	headers["Accept-Language"] = source.AcceptLanguage
+	source.Headers = headers
+	return source
+}
+
+// fetchAllAndGenerate fetches every schema discovered at a base URL and generates
+// their tools. Tools of versioned specs are prefixed with the version (e.g., "v2_")
+// so that the same operation in different API versions does not collide.
+func (uc *SyncSchemaUseCase) fetchAllAndGenerate(ctx context.Context, log *slog.Logger, fetcher SchemaFetcher, schemaType domain.SchemaType, source SchemaSourceConfig) ([]domain.Tool, []InvocationDetails, []SkippedOperation, error) {
+	multiFetcher, ok := fetcher.(MultiSchemaFetcher)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("schema fetcher for type %s does not support discover_all", schemaType)
+	}
+	schemas, err := multiFetcher.FetchAll(ctx, source)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to discover schemas: %w", err)
+	}
+
+	specURLs := make([]string, len(schemas))
+	for i, schema := range schemas {
+		specURLs[i] = schema.Source
+	}
+	namespaces := specNamespaces(specURLs)
+
+	var allTools []domain.Tool
+	var allDetails []InvocationDetails
+	var allSkipped []SkippedOperation
+	for i, schema := range schemas {
+		schema.ResponseStatuses = source.ResponseStatuses
+		tools, detailsList, skipped, err := uc.generateForSchema(log.With(slog.String("spec", schema.Source)), schemaType, schema)
 		if err != nil {
-			return fmt.Errorf("failed to fetch schema: %w", err)
+			return nil, nil, nil, fmt.Errorf("spec '%s': %w", schema.Source, err)
 		}
+		if namespace := namespaces[i]; namespace != "" {
+			for j := range tools {
+				tools[j].Name = namespace + "_" + tools[j].Name
+				for k := range tools[j].Links {
+					tools[j].Links[k].Tool = namespace + "_" + tools[j].Links[k].Tool
+				}
+			}
+		}
+		allTools = append(allTools, tools...)
+		allDetails = append(allDetails, detailsList...)
+		allSkipped = append(allSkipped, skipped...)
+	}
+	return allTools, allDetails, allSkipped, nil
+}
+
+var versionSegmentPattern = regexp.MustCompile(`^[vV][0-9]+$`)
+
+// specNamespaceHashLength is the number of hex digits of a spec URL hash telling apart
+// specs of the same version.
+const specNamespaceHashLength = 6
+
+// specNamespaces returns the namespace of each spec's tools: its version (see
+// specVersionNamespace), followed by a hash of the spec URL when several specs share
+// it, so that their tools cannot collide.
+func specNamespaces(specURLs []string) []string {
+	namespaces := make([]string, len(specURLs))
+	counts := make(map[string]int, len(specURLs))
+	for i, specURL := range specURLs {
+		namespaces[i] = specVersionNamespace(specURL)
+		counts[namespaces[i]]++
 	}
+	for i, namespace := range namespaces {
+		if counts[namespace] < 2 {
+			continue
+		}
+		sum := sha256.Sum256([]byte(specURLs[i]))
+		hash := hex.EncodeToString(sum[:])[:specNamespaceHashLength]
+		if namespace == "" {
+			namespaces[i] = hash
+		} else {
+			namespaces[i] = namespace + "_" + hash
+		}
+	}
+	return namespaces
+}
+
+// specVersionNamespace returns the first version segment (e.g., "v2") of a spec URL's path,
+// or "" if the path has none.
+func specVersionNamespace(specURL string) string {
+	if u, err := url.Parse(specURL); err == nil {
+		specURL = u.Path
+	}
+	for _, segment := range strings.Split(specURL, "/") {
+		if versionSegmentPattern.MatchString(segment) {
+			return strings.ToLower(segment)
+		}
+	}
+	return ""
+}
+
+// generateForSchema checks a fetched schema against the detected type and generates its tools.
+func (uc *SyncSchemaUseCase) generateForSchema(log *slog.Logger, schemaType domain.SchemaType, fetchedSchema domain.APISchema) ([]domain.Tool, []InvocationDetails, []SkippedOperation, error) {
 	if fetchedSchema.Type == "" {
 		fetchedSchema.Type = schemaType
 		log.Warn("Fetcher did not set schema type, using detected type.")
 	} else if fetchedSchema.Type != schemaType {
-		// Special handling for Connect-RPC with proto files
-		if (schemaType == domain.SchemaTypeConnect && fetchedSchema.Type == domain.SchemaTypeConnectProto) ||
+		if schemaType == domain.SchemaTypeGitHub {
+			// GitHub is only a transport; the fetcher reports the schema it found
+			log.Debug("GitHub source resolved to schema type", slog.String("fetched_type", string(fetchedSchema.Type)))
+		} else if (schemaType == domain.SchemaTypeConnect && fetchedSchema.Type == domain.SchemaTypeConnectProto) ||
 			(schemaType == domain.SchemaTypeConnectProto && fetchedSchema.Type == domain.SchemaTypeConnect) {
 			// These are compatible - both are Connect-RPC, just different configurations
 			log.Debug("Connect-RPC type variation detected, continuing with fetched type")
 		} else {
-			return fmt.Errorf("detected schema type (%s) mismatch with fetched schema type (%s)", schemaType, fetchedSchema.Type)
+			return nil, nil, nil, fmt.Errorf("detected schema type (%s) mismatch with fetched schema type (%s)", schemaType, fetchedSchema.Type)
 		}
 	}
 	log.Info("Schema fetched successfully.")
 
 	generator, ok := uc.generators[fetchedSchema.Type]
 	if !ok {
-		return fmt.Errorf("no tool generator found for schema type %s", fetchedSchema.Type)
+		return nil, nil, nil, fmt.Errorf("no tool generator found for schema type %s", fetchedSchema.Type)
 	}
 	log.Info("Generating tools and invocation details.")
-	tools, detailsList, err := generator.Generate(fetchedSchema)
+	tools, detailsList, skipped, err := uc.generateTools(generator, fetchedSchema)
 	if err != nil {
-		return fmt.Errorf("failed to generate tools/details: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to generate tools/details: %w", err)
 	}
 	log.Info("Generated domain tools and details", slog.Int("count", len(tools)))
+	return tools, detailsList, skipped, nil
+}
 
-	registeredCount := 0
-	for i, domainTool := range tools {
-		toolName := domainTool.Name
-		if i >= len(detailsList) {
-			log.Error("Mismatch between tools and details lists", slog.String("toolName", toolName))
-			continue
-		}
-		invocationDetails := detailsList[i]
+// toolFingerprint hashes a tool definition together with its invocation details.
+// It returns "" if they cannot be serialized, which callers treat as always changed.
+func toolFingerprint(tool mcp.Tool, details InvocationDetails) string {
+	data, err := json.Marshal(struct {
+		Tool    mcp.Tool          `json:"tool"`
+		Details InvocationDetails `json:"details"`
+	}{tool, details})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
 
-		mcpTool, err := uc.convertDomainToolToMCPTool(domainTool)
-		if err != nil {
-			log.Error("Failed to convert domain tool to MCP tool, skipping registration.", slog.String("toolName", toolName), slog.Any("error", err))
-			continue
+// generateTools calls the generator, converting a panic into an error so that a
+// malformed schema only fails its own source instead of crashing the sync. It also
+// returns the operations a reporting generator skipped.
+func (uc *SyncSchemaUseCase) generateTools(generator ToolGenerator, schema domain.APISchema) (tools []domain.Tool, details []InvocationDetails, skipped []SkippedOperation, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			uc.logger.Error("Tool generator panicked",
+				slog.String("source", schema.Source),
+				slog.String("schema_type", string(schema.Type)),
+				slog.Any("panic", r),
+				slog.String("stack", string(debug.Stack())),
+			)
+			tools, details, skipped = nil, nil, nil
+			err = fmt.Errorf("generator panicked: %v", r)
 		}
+	}()
+	if reporter, ok := generator.(ReportingToolGenerator); ok {
+		return reporter.GenerateWithReport(schema)
+	}
+	tools, details, err = generator.Generate(schema)
+	return tools, details, nil, err
+}
 
-		handlerFunc := uc.createToolHandler(invocationDetails, toolName)
-
-		uc.mcpServer.AddTool(*mcpTool, handlerFunc)
-		log.Debug("Registered tool with MCP server", slog.String("toolName", mcpTool.Name))
-		registeredCount++
+// dropLinksToMissingTools removes the links to tools not in tools, e.g. ones dropped
+// by a tool limit, so that descriptions do not suggest tools that are not registered.
+func dropLinksToMissingTools(tools []domain.Tool) []domain.Tool {
+	names := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		names[tool.Name] = true
 	}
+	for i := range tools {
+		var links []domain.ToolLink
+		for _, link := range tools[i].Links {
+			if names[link.Tool] {
+				links = append(links, link)
+			}
+		}
+		tools[i].Links = links
+	}
+	return tools
+}
 
-	log.Info("Finished processing source, registered tools.", slog.Int("registered_count", registeredCount))
-	return nil
+// describeLinks renders a tool's links as a hint appended to its description, so that
+// the model knows which calls usually follow. It returns "" for no links.
+func describeLinks(links []domain.ToolLink) string {
+	if len(links) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\nFollow-up tools:")
+	for _, link := range links {
+		fmt.Fprintf(&b, "\n- %s (%s)", link.Tool, link.Name)
+		if link.Description != "" {
+			fmt.Fprintf(&b, ": %s", strings.TrimSpace(link.Description))
+		}
+		if len(link.Parameters) > 0 {
+			params := make([]string, 0, len(link.Parameters))
+			for name, value := range link.Parameters {
+				params = append(params, name+" = "+value)
+			}
+			sort.Strings(params)
+			fmt.Fprintf(&b, " [arguments: %s]", strings.Join(params, ", "))
+		}
+	}
+	return b.String()
 }
 
 // convertDomainToolToMCPTool converts the internal domain.Tool definition
@@ -192,7 +1105,24 @@ func (uc *SyncSchemaUseCase) convertDomainToolToMCPTool(dTool domain.Tool) (*mcp
 
 	// Start building tool options
 	toolOptions := []mcp.ToolOption{
-		mcp.WithDescription(dTool.Description),
+		mcp.WithDescription(dTool.Description + describeLinks(dTool.Links)),
+	}
+	if dTool.Title != "" {
+		toolOptions = append(toolOptions, mcp.WithTitleAnnotation(dTool.Title))
+	}
+	if a := dTool.Annotations; a != nil {
+		if a.ReadOnlyHint != nil {
+			toolOptions = append(toolOptions, mcp.WithReadOnlyHintAnnotation(*a.ReadOnlyHint))
+		}
+		if a.DestructiveHint != nil {
+			toolOptions = append(toolOptions, mcp.WithDestructiveHintAnnotation(*a.DestructiveHint))
+		}
+		if a.IdempotentHint != nil {
+			toolOptions = append(toolOptions, mcp.WithIdempotentHintAnnotation(*a.IdempotentHint))
+		}
+		if a.OpenWorldHint != nil {
+			toolOptions = append(toolOptions, mcp.WithOpenWorldHintAnnotation(*a.OpenWorldHint))
+		}
 	}
 
 	// Process InputSchema properties
@@ -205,12 +1135,9 @@ func (uc *SyncSchemaUseCase) convertDomainToolToMCPTool(dTool domain.Tool) (*mcp
 
 		for name, prop := range dTool.InputSchema.Properties {
 			isRequired := requiredMap[name]
-			// TODO: Get description from prop.Description if available
-			propDescription := ""
-
 			propertyOpts := []mcp.PropertyOption{}
-			if propDescription != "" {
-				propertyOpts = append(propertyOpts, mcp.Description(propDescription))
+			if prop.Description != "" {
+				propertyOpts = append(propertyOpts, mcp.Description(prop.Description))
 			}
 			if isRequired {
 				propertyOpts = append(propertyOpts, mcp.Required())
@@ -317,10 +1244,10 @@ func convertDomainSchemaToMap(schema *domain.JSONSchemaProps) (map[string]any, e
 	if len(schema.Enum) > 0 {
 		schemaMap["enum"] = schema.Enum
 	}
-	// TODO: Add description, default, validation constraints etc.
-	// if schema.Description != "" {
-	// 	schemaMap["description"] = schema.Description
-	// }
+	if schema.Description != "" {
+		schemaMap["description"] = schema.Description
+	}
+	// TODO: Add default, validation constraints etc.
 
 	switch schema.Type {
 	case "object":
@@ -368,22 +1295,82 @@ func convertDomainSchemaPropertiesToMap(props map[string]domain.JSONSchemaProps)
 // its invocation details and the shared invoker.
 // Return type should match mcpServer.ToolHandlerFunc from the adapter interface
 // Need to import mcpServer alias locally or fully qualify
-func (uc *SyncSchemaUseCase) createToolHandler(details InvocationDetails, toolName string) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) { // Use imported mcp types
+func (uc *SyncSchemaUseCase) createToolHandler(details InvocationDetails, tool domain.Tool, source string) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) { // Use imported mcp types
 	invoker := uc.invoker
-	log := uc.logger.With(slog.String("toolName", toolName))
+	log := uc.logger.With(slog.String("toolName", tool.Name))
 
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		defer uc.calls.start()()
+
+		requestID := RequestID{ID: requestIDOf(request), Header: uc.requestIDHeader}
+		ctx = ContextWithRequestID(ctx, requestID)
+		ctx, span := tracer.Start(ctx, "SyncSchemaUseCase.toolHandler", trace.WithAttributes(
+			attribute.String("tool.name", tool.Name),
+			attribute.String("request.id", requestID.ID),
+		))
+		defer span.End()
+		log := log.With(RequestIDLogAttr(ctx))
+
 		log.Info("Executing MCP tool handler")
 		params := request.GetArguments()
 		log.Debug("Handler received parameters", slog.Any("params", params))
 
-		resultData, invokeErr := invoker.Invoke(ctx, details, params)
+		if uc.invocationTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, uc.invocationTimeout)
+			defer cancel()
+		}
+
+		// The limiter is looked up per call so a re-sync with a new limit takes effect
+		// for tools that were not re-registered.
+		if limiter := uc.limiterFor(source); limiter != nil {
+			release, err := limiter.acquire(ctx, source)
+			if err != nil {
+				category := ErrorCategoryOf(err)
+				log.Warn("Tool call not started, source is at its in-flight limit", slog.Any("error", err), slog.String("category", string(category)))
+				return newToolErrorResult(err, category), nil
+			}
+			defer release()
+		}
+
+		callDetails := details
+		credentialSource := source
+		if source == restoredSource {
+			credentialSource = details.Source
+		}
+		if credential := uc.tokenFileFor(credentialSource); credential != nil {
+			callDetails.HeaderParams = mergeStrings(details.HeaderParams, map[string]string{credential.config.header: credential.headerValue()})
+		}
+
+		resultData, invokeErr := invoker.Invoke(ctx, callDetails, params)
+		if invokeErr != nil && uc.invocationTimeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			invokeErr = NewInvocationError(ErrorCategoryTimeout,
+				fmt.Errorf("tool call exceeded the %s invocation timeout: %w", uc.invocationTimeout, invokeErr))
+		}
 		if invokeErr != nil {
-			log.Error("Tool handler failed during invocation", slog.Any("error", invokeErr))
-			return nil, invokeErr
+			category := ErrorCategoryOf(invokeErr)
+			log.Error("Tool handler failed during invocation", slog.Any("error", invokeErr), slog.String("category", string(category)))
+			span.RecordError(invokeErr)
+			span.SetStatus(codes.Error, invokeErr.Error())
+			return newToolErrorResult(invokeErr, category), nil
 		}
 
 		log.Info("Tool handler invocation successful")
+		if uc.decodeByteFields {
+			resultData = decodeByteFields(resultData, tool.OutputSchema)
+		}
+
+		if binary, ok := resultData.(BinaryResult); ok {
+			log.Debug("Tool result is binary", slog.String("mimeType", binary.MIMEType), slog.Int("size", len(binary.Data)))
+			content := []mcp.Content{binaryContent(binary)}
+			if len(binary.Headers) > 0 {
+				headers, err := json.Marshal(map[string]interface{}{"headers": binary.Headers})
+				if err == nil {
+					content = append(content, mcp.NewTextContent(string(headers)))
+				}
+			}
+			return &mcp.CallToolResult{Content: content}, nil
+		}
 
 		// Convert resultData to appropriate text format
 		var resultText string
@@ -410,30 +1397,83 @@ func (uc *SyncSchemaUseCase) createToolHandler(details InvocationDetails, toolNa
 	}
 }
 
+// binaryContent returns the MCP content block for a binary result: image and audio
+// content for those media types, and an embedded blob resource for anything else.
+func binaryContent(result BinaryResult) mcp.Content {
+	data := base64.StdEncoding.EncodeToString(result.Data)
+	switch {
+	case strings.HasPrefix(result.MIMEType, "image/"):
+		return mcp.NewImageContent(data, result.MIMEType)
+	case strings.HasPrefix(result.MIMEType, "audio/"):
+		return mcp.NewAudioContent(data, result.MIMEType)
+	default:
+		return mcp.NewEmbeddedResource(mcp.BlobResourceContents{URI: result.URI, MIMEType: result.MIMEType, Blob: data})
+	}
+}
+
+// newToolErrorResult reports a failed invocation as an MCP tool error whose
+// _meta carries the error category for programmatic handling.
+func newToolErrorResult(err error, category ErrorCategory) *mcp.CallToolResult {
+	result := mcp.NewToolResultError(err.Error())
+	result.Meta = map[string]any{"errorCategory": string(category)}
+	return result
+}
+
+// supportedSourceFormats lists the source formats determineSchemaType recognizes.
+var supportedSourceFormats = []string{
+	"http:// or https:// (OpenAPI)",
+	"a file path (OpenAPI)",
+	"grpc:// (gRPC reflection)",
+	"connect:// (Connect-RPC)",
+	"github:// (OpenAPI or proto in a GitHub repository)",
+	"a .proto, .pb, or .desc file (protobuf)",
+}
+
+// UnknownSchemaTypeError is returned when the schema type of a source cannot be
+// determined from its URL and no type is configured.
+type UnknownSchemaTypeError struct {
+	Source string
+}
+
+func (e *UnknownSchemaTypeError) Error() string {
+	scheme, _, _ := strings.Cut(e.Source, "://")
+	return fmt.Sprintf("could not determine schema type of source %q: unrecognized scheme %q; supported sources are %s, or set the source's \"type\" field",
+		e.Source, scheme+"://", strings.Join(supportedSourceFormats, ", "))
+}
+
 // determineSchemaType guesses the schema type based on the source string prefix.
-func (uc *SyncSchemaUseCase) determineSchemaType(source string) domain.SchemaType {
-	// Check if it's a .proto file (handle @ref suffix for GitHub URLs)
+// It returns an *UnknownSchemaTypeError for unrecognized sources.
+func (uc *SyncSchemaUseCase) determineSchemaType(source string) (domain.SchemaType, error) {
+	// Check if it's a .proto file or descriptor set (handle @ref suffix for GitHub URLs)
 	sourcePath := source
 	if idx := strings.Index(source, "@"); idx != -1 {
 		sourcePath = source[:idx]
 	}
-	if strings.HasSuffix(sourcePath, ".proto") {
-		return domain.SchemaTypeProto
+	if isProtoFile(sourcePath) {
+		return domain.SchemaTypeProto, nil
 	}
 	if strings.HasPrefix(source, "grpc://") {
-		return domain.SchemaTypeGRPC
+		return domain.SchemaTypeGRPC, nil
 	}
 	if strings.HasPrefix(source, "connect://") {
-		return domain.SchemaTypeConnect
+		return domain.SchemaTypeConnect, nil
 	}
 	if strings.HasPrefix(source, "github://") {
-		// GitHub URLs default to OpenAPI unless they end with .proto
-		return domain.SchemaTypeOpenAPI
+		// GitHub URLs not ending with .proto resolve to OpenAPI once fetched
+		return domain.SchemaTypeGitHub, nil
 	}
 	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") || !strings.Contains(source, "://") {
-		return domain.SchemaTypeOpenAPI
+		return domain.SchemaTypeOpenAPI, nil
 	}
-	return ""
+	return "", &UnknownSchemaTypeError{Source: source}
+}
+
+// isProtoFile reports whether the source names .proto source or a compiled
+// FileDescriptorSet (.pb/.desc from protoc --descriptor_set_out). A #server
+// fragment naming the gRPC endpoint is ignored.
+func isProtoFile(source string) bool {
+	source, _, _ = strings.Cut(source, "#")
+	return strings.HasSuffix(source, ".proto") || strings.HasSuffix(source, ".pb") || strings.HasSuffix(source, ".desc")
 }
 
 // Execute method now uses the interface implicitly via processSingleSourceAndRegister
@@ -2,14 +2,34 @@ package usecase_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 
+	"github.com/i2y/mcpizer/internal/adapter/outbound/httpinvoker"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/memrepo"
+	"github.com/i2y/mcpizer/internal/adapter/outbound/openapi"
 	"github.com/i2y/mcpizer/internal/domain"
 	"github.com/i2y/mcpizer/internal/usecase"
 
@@ -35,6 +55,11 @@ func (m *MockSchemaFetcher) FetchWithConfig(ctx context.Context, config usecase.
 	return args.Get(0).(domain.APISchema), args.Error(1)
 }
 
+// configFor matches the SchemaSourceConfig of the source at url.
+func configFor(url string) interface{} {
+	return mock.MatchedBy(func(config usecase.SchemaSourceConfig) bool { return config.URL == url })
+}
+
 // MockToolGenerator is a mock implementation of the ToolGenerator interface.
 type MockToolGenerator struct {
 	mock.Mock
@@ -67,6 +92,10 @@ func (m *MockMCPServer) AddTool(tool mcp.Tool, handler mcpServer.ToolHandlerFunc
 	m.Called(tool, handler)
 }
 
+func (m *MockMCPServer) DeleteTools(names ...string) {
+	m.Called(names)
+}
+
 // MockToolInvoker is defined elsewhere (e.g., invoke_tool_test.go), remove definition from here.
 /*
  type MockToolInvoker struct {
@@ -107,7 +136,7 @@ func TestSyncSchemaUseCase_Execute(t *testing.T) {
 			name: "Success - OpenAPI schema synced",
 			// Update mockSetup implementation
 			mockSetup: func(fetcher *MockSchemaFetcher, generator *MockToolGenerator, mcpSrv *MockMCPServer, invoker *MockToolInvoker) {
-				fetcher.On("Fetch", ctx, sourceURL).Return(mockSchema, nil).Once()
+				fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(mockSchema, nil).Once()
 				generator.On("Generate", mockSchema).Return(mockTools, mockDetails, nil).Once()
 				// Use mock.Anything for the handler function type matching
 				mcpSrv.On("AddTool", mockExpectedMCPTool, mock.Anything).Once()
@@ -118,7 +147,7 @@ func TestSyncSchemaUseCase_Execute(t *testing.T) {
 		{
 			name: "Failure - Fetch error",
 			mockSetup: func(fetcher *MockSchemaFetcher, generator *MockToolGenerator, mcpSrv *MockMCPServer, invoker *MockToolInvoker) {
-				fetcher.On("Fetch", ctx, sourceURL).Return(domain.APISchema{}, fetchErr).Once()
+				fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(domain.APISchema{}, fetchErr).Once()
 				// Generate and AddTool should not be called
 			},
 			inSource: sourceURL,
@@ -129,7 +158,7 @@ func TestSyncSchemaUseCase_Execute(t *testing.T) {
 		{
 			name: "Failure - Generate error",
 			mockSetup: func(fetcher *MockSchemaFetcher, generator *MockToolGenerator, mcpSrv *MockMCPServer, invoker *MockToolInvoker) {
-				fetcher.On("Fetch", ctx, sourceURL).Return(mockSchema, nil).Once()
+				fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(mockSchema, nil).Once()
 				generator.On("Generate", mockSchema).Return(nil, nil, generateErr).Once()
 				// AddTool should not be called
 			},
@@ -143,7 +172,7 @@ func TestSyncSchemaUseCase_Execute(t *testing.T) {
 			mockSetup: func(fetcher *MockSchemaFetcher, generator *MockToolGenerator, mcpSrv *MockMCPServer, invoker *MockToolInvoker) {
 				// Return schema with a type that has no registered generator
 				unsupportedSchema := domain.APISchema{Source: sourceURL, Type: "graphql", ParsedData: "graphql data"}
-				fetcher.On("Fetch", ctx, sourceURL).Return(unsupportedSchema, nil).Once()
+				fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(unsupportedSchema, nil).Once()
 				// Generate and AddTool should not be called
 			},
 			inSource: sourceURL,
@@ -202,3 +231,1632 @@ func TestSyncSchemaUseCase_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_GeneratorPanic(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	grpcSource := "grpc://broken.example.com:50051"
+	openapiSource := "http://example.com/openapi.yaml"
+	grpcSchema := domain.APISchema{Source: grpcSource, Type: domain.SchemaTypeGRPC}
+	openapiSchema := domain.APISchema{Source: openapiSource, Type: domain.SchemaTypeOpenAPI}
+
+	grpcFetcher := new(MockSchemaFetcher)
+	grpcFetcher.On("FetchWithConfig", ctx, configFor(grpcSource)).Return(grpcSchema, nil).Once()
+	openapiFetcher := new(MockSchemaFetcher)
+	openapiFetcher.On("FetchWithConfig", ctx, configFor(openapiSource)).Return(openapiSchema, nil).Once()
+
+	panickingGenerator := new(MockToolGenerator)
+	panickingGenerator.On("Generate", grpcSchema).Run(func(mock.Arguments) {
+		var descriptor *domain.JSONSchemaProps
+		_ = descriptor.Type // nil dereference
+	}).Return(nil, nil, nil).Once()
+	openapiGenerator := new(MockToolGenerator)
+	openapiGenerator.On("Generate", openapiSchema).Return(
+		[]domain.Tool{{Name: "tool-a", Description: "Tool A Desc"}},
+		[]usecase.InvocationDetails{{Type: "http", HTTPPath: "/path/a"}},
+		nil,
+	).Once()
+
+	mockMCPServer := new(MockMCPServer)
+	mockMCPServer.On("AddTool", mcp.NewTool("tool-a", mcp.WithDescription("Tool A Desc")), mock.Anything).Once()
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: grpcSource}, {URL: openapiSource}},
+		map[domain.SchemaType]usecase.SchemaFetcher{
+			domain.SchemaTypeGRPC:    grpcFetcher,
+			domain.SchemaTypeOpenAPI: openapiFetcher,
+		},
+		map[domain.SchemaType]usecase.ToolGenerator{
+			domain.SchemaTypeGRPC:    panickingGenerator,
+			domain.SchemaTypeOpenAPI: openapiGenerator,
+		},
+		mockMCPServer,
+		new(MockToolInvoker),
+		logger,
+	)
+
+	err := uc.SyncAllConfiguredSources(ctx)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "source 'grpc://broken.example.com:50051': failed to generate tools/details: generator panicked")
+
+	panickingGenerator.AssertExpectations(t)
+	openapiGenerator.AssertExpectations(t)
+	mockMCPServer.AssertExpectations(t)
+}
+
+func TestSyncSchemaUseCase_Execute_PassesTitleAndAnnotations(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	sourceURL := "http://example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+	readOnly, destructive := true, false
+	tool := domain.Tool{
+		Name:        "list_pets",
+		Title:       "List pets",
+		Description: "List pets",
+		Annotations: &domain.ToolAnnotations{ReadOnlyHint: &readOnly, DestructiveHint: &destructive},
+	}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil).Once()
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return([]domain.Tool{tool}, []usecase.InvocationDetails{{Type: "http"}}, nil).Once()
+
+	var registered mcp.Tool
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		registered = args.Get(0).(mcp.Tool)
+	}).Once()
+
+	uc := usecase.NewSyncSchemaUseCase(
+		nil,
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		new(MockToolInvoker),
+		logger,
+	)
+	assert.NoError(t, uc.Execute(ctx, sourceURL))
+
+	assert.Equal(t, "List pets", registered.Annotations.Title)
+	if assert.NotNil(t, registered.Annotations.ReadOnlyHint) {
+		assert.True(t, *registered.Annotations.ReadOnlyHint)
+	}
+	if assert.NotNil(t, registered.Annotations.DestructiveHint) {
+		assert.False(t, *registered.Annotations.DestructiveHint)
+	}
+	mcpSrv.AssertExpectations(t)
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_Resync(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	sourceURL := "http://example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+	toolA := domain.Tool{Name: "tool-a", Description: "Tool A Desc"}
+	toolB := domain.Tool{Name: "tool-b", Description: "Tool B Desc"}
+	detailsA := usecase.InvocationDetails{Type: "http", HTTPPath: "/a"}
+	detailsB := usecase.InvocationDetails{Type: "http", HTTPPath: "/b"}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil)
+	generator := new(MockToolGenerator)
+	mcpSrv := new(MockMCPServer)
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: sourceURL}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		new(MockToolInvoker),
+		logger,
+	)
+
+	// Initial sync registers both tools.
+	generator.On("Generate", schema).Return([]domain.Tool{toolA, toolB}, []usecase.InvocationDetails{detailsA, detailsB}, nil).Once()
+	mcpSrv.On("AddTool", mcp.NewTool("tool-a", mcp.WithDescription("Tool A Desc")), mock.Anything).Once()
+	mcpSrv.On("AddTool", mcp.NewTool("tool-b", mcp.WithDescription("Tool B Desc")), mock.Anything).Once()
+	assert.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	mcpSrv.AssertExpectations(t)
+
+	// No-op re-sync: nothing is re-registered or removed.
+	generator.On("Generate", schema).Return([]domain.Tool{toolA, toolB}, []usecase.InvocationDetails{detailsA, detailsB}, nil).Once()
+	assert.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	mcpSrv.AssertNumberOfCalls(t, "AddTool", 2)
+	mcpSrv.AssertNotCalled(t, "DeleteTools", mock.Anything)
+
+	// tool-a changes and tool-b disappears: only tool-a is re-added and tool-b is removed.
+	changedDetailsA := usecase.InvocationDetails{Type: "http", HTTPPath: "/a/v2"}
+	generator.On("Generate", schema).Return([]domain.Tool{toolA}, []usecase.InvocationDetails{changedDetailsA}, nil).Once()
+	mcpSrv.On("AddTool", mcp.NewTool("tool-a", mcp.WithDescription("Tool A Desc")), mock.Anything).Once()
+	mcpSrv.On("DeleteTools", []string{"tool-b"}).Once()
+	assert.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	mcpSrv.AssertNumberOfCalls(t, "AddTool", 3)
+	mcpSrv.AssertExpectations(t)
+	generator.AssertExpectations(t)
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_TypeOverride(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	// The URL looks like an OpenAPI document, but the config forces gRPC reflection.
+	source := usecase.SchemaSourceConfig{URL: "https://grpc.example.com/openapi.json", Type: string(domain.SchemaTypeGRPC)}
+	grpcSchema := domain.APISchema{Source: source.URL, Type: domain.SchemaTypeGRPC}
+
+	openapiFetcher := new(MockSchemaFetcher)
+	grpcFetcher := new(MockSchemaFetcher)
+	grpcFetcher.On("FetchWithConfig", ctx, source).Return(grpcSchema, nil).Once()
+	openapiGenerator := new(MockToolGenerator)
+	grpcGenerator := new(MockToolGenerator)
+	grpcGenerator.On("Generate", grpcSchema).Return(
+		[]domain.Tool{{Name: "greeter_sayhello", Description: "Calls Greeter.SayHello"}},
+		[]usecase.InvocationDetails{{Type: "grpc", Host: source.URL}},
+		nil,
+	).Once()
+
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mcp.NewTool("greeter_sayhello", mcp.WithDescription("Calls Greeter.SayHello")), mock.Anything).Once()
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{source},
+		map[domain.SchemaType]usecase.SchemaFetcher{
+			domain.SchemaTypeOpenAPI: openapiFetcher,
+			domain.SchemaTypeGRPC:    grpcFetcher,
+		},
+		map[domain.SchemaType]usecase.ToolGenerator{
+			domain.SchemaTypeOpenAPI: openapiGenerator,
+			domain.SchemaTypeGRPC:    grpcGenerator,
+		},
+		mcpSrv,
+		new(MockToolInvoker),
+		logger,
+	)
+
+	assert.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	grpcFetcher.AssertExpectations(t)
+	grpcGenerator.AssertExpectations(t)
+	openapiFetcher.AssertNotCalled(t, "FetchWithConfig", mock.Anything, mock.Anything)
+	openapiGenerator.AssertNotCalled(t, "Generate", mock.Anything)
+	mcpSrv.AssertExpectations(t)
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_GitHubSource(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	tests := []struct {
+		name      string
+		source    usecase.SchemaSourceConfig
+		fetchedAs domain.SchemaType
+	}{
+		{
+			name:      "OpenAPI document",
+			source:    usecase.SchemaSourceConfig{URL: "github://acme/api/openapi.yaml@main"},
+			fetchedAs: domain.SchemaTypeOpenAPI,
+		},
+		{
+			name:      "OpenAPI document with response statuses",
+			source:    usecase.SchemaSourceConfig{URL: "github://acme/api/openapi.yaml@main", ResponseStatuses: []string{"202"}},
+			fetchedAs: domain.SchemaTypeOpenAPI,
+		},
+		{
+			name:      "proto file with server",
+			source:    usecase.SchemaSourceConfig{URL: "github://acme/api/proto/greeter.proto", Server: "grpc://localhost:50051"},
+			fetchedAs: domain.SchemaTypeProto,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetched := domain.APISchema{Source: tt.source.URL, Type: tt.fetchedAs}
+			// The preferred response statuses reach the generator whatever the fetcher.
+			generated := fetched
+			generated.ResponseStatuses = tt.source.ResponseStatuses
+
+			githubFetcher := new(MockSchemaFetcher)
+			githubFetcher.On("FetchWithConfig", ctx, tt.source).Return(fetched, nil).Once()
+			otherFetcher := new(MockSchemaFetcher)
+			generator := new(MockToolGenerator)
+			generator.On("Generate", generated).Return(
+				[]domain.Tool{{Name: "greet", Description: "Greets"}},
+				[]usecase.InvocationDetails{{Type: "http"}},
+				nil,
+			).Once()
+
+			mcpSrv := new(MockMCPServer)
+			mcpSrv.On("AddTool", mcp.NewTool("greet", mcp.WithDescription("Greets")), mock.Anything).Once()
+
+			uc := usecase.NewSyncSchemaUseCase(
+				[]usecase.SchemaSourceConfig{tt.source},
+				map[domain.SchemaType]usecase.SchemaFetcher{
+					domain.SchemaTypeGitHub:  githubFetcher,
+					domain.SchemaTypeOpenAPI: otherFetcher,
+					domain.SchemaTypeProto:   otherFetcher,
+				},
+				map[domain.SchemaType]usecase.ToolGenerator{
+					tt.fetchedAs: generator,
+				},
+				mcpSrv,
+				new(MockToolInvoker),
+				logger,
+			)
+
+			assert.NoError(t, uc.SyncAllConfiguredSources(ctx))
+			githubFetcher.AssertExpectations(t)
+			generator.AssertExpectations(t)
+			otherFetcher.AssertNotCalled(t, "FetchWithConfig", mock.Anything, mock.Anything)
+			mcpSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckRegistrations(t *testing.T) {
+	fetcher := new(MockSchemaFetcher)
+	generator := new(MockToolGenerator)
+	fetchers := map[domain.SchemaType]usecase.SchemaFetcher{
+		domain.SchemaTypeOpenAPI: fetcher,
+		domain.SchemaTypeGRPC:    fetcher,
+		domain.SchemaTypeProto:   fetcher,
+		domain.SchemaTypeConnect: fetcher,
+		domain.SchemaTypeGitHub:  fetcher,
+	}
+	generators := map[domain.SchemaType]usecase.ToolGenerator{
+		domain.SchemaTypeOpenAPI:      generator,
+		domain.SchemaTypeGRPC:         generator,
+		domain.SchemaTypeProto:        generator,
+		domain.SchemaTypeConnect:      generator,
+		domain.SchemaTypeConnectProto: generator,
+	}
+	assert.NoError(t, usecase.CheckRegistrations(fetchers, generators))
+
+	delete(fetchers, domain.SchemaTypeGitHub)
+	delete(generators, domain.SchemaTypeProto)
+	err := usecase.CheckRegistrations(fetchers, generators)
+	assert.ErrorContains(t, err, "no schema fetcher registered for type github")
+	assert.ErrorContains(t, err, "no tool generator registered for type proto")
+}
+
+func TestSyncSchemaUseCase_ToolHandler_ErrorCategory(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	sourceURL := "http://example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+	details := usecase.InvocationDetails{Type: "http", HTTPPath: "/pets/{id}"}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil).Once()
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return([]domain.Tool{{Name: "get_pet", Description: "Get a pet"}}, []usecase.InvocationDetails{details}, nil).Once()
+
+	var handler mcpServer.ToolHandlerFunc
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handler = args.Get(1).(mcpServer.ToolHandlerFunc)
+	}).Once()
+
+	invoker := new(MockToolInvoker)
+	invoker.On("Invoke", mock.Anything, details, mock.Anything).Return(nil,
+		usecase.NewInvocationError(usecase.ErrorCategoryNotFound, errors.New("HTTP 404: pet not found"))).Once()
+
+	uc := usecase.NewSyncSchemaUseCase(
+		nil,
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		invoker,
+		logger,
+	)
+	assert.NoError(t, uc.Execute(ctx, sourceURL))
+	if !assert.NotNil(t, handler) {
+		return
+	}
+
+	result, err := handler(ctx, mcp.CallToolRequest{})
+	assert.NoError(t, err)
+	if assert.NotNil(t, result) {
+		assert.True(t, result.IsError)
+		assert.Equal(t, map[string]any{"errorCategory": "not_found"}, result.Meta)
+		assert.Equal(t, []mcp.Content{mcp.NewTextContent("HTTP 404: pet not found")}, result.Content)
+	}
+	invoker.AssertExpectations(t)
+}
+
+func TestSyncSchemaUseCase_GenerateAll_ToolNameAffix(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	sourceURL := "http://example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+	longName := strings.Repeat("a", 60)
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil).Once()
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return(
+		[]domain.Tool{{Name: "get_pet"}, {Name: longName + "_one"}, {Name: longName + "_two"}},
+		[]usecase.InvocationDetails{{Type: "http"}, {Type: "http"}, {Type: "http"}},
+		nil,
+	).Once()
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: sourceURL}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		new(MockMCPServer),
+		new(MockToolInvoker),
+		logger,
+		usecase.WithToolNameAffix("staging_", "_x"),
+	)
+
+	generated, err := uc.GenerateAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, generated, 3)
+
+	assert.Equal(t, "staging_get_pet_x", generated[0].Tool.Name)
+	for _, g := range generated[1:] {
+		assert.Len(t, g.Tool.Name, usecase.MaxToolNameLength)
+		assert.True(t, strings.HasPrefix(g.Tool.Name, "staging_"+strings.Repeat("a", 10)), g.Tool.Name)
+		assert.True(t, strings.HasSuffix(g.Tool.Name, "_x"), g.Tool.Name)
+	}
+	assert.NotEqual(t, generated[1].Tool.Name, generated[2].Tool.Name)
+}
+
+func TestValidateToolNameAffix(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		suffix  string
+		wantErr string
+	}{
+		{name: "empty"},
+		{name: "valid", prefix: "staging_", suffix: "-v2"},
+		{name: "invalid prefix", prefix: "stag ing", wantErr: "prefix"},
+		{name: "invalid suffix", suffix: ".v2", wantErr: "suffix"},
+		{name: "too long", prefix: strings.Repeat("p", 20), suffix: strings.Repeat("s", 13), wantErr: "at most 32"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := usecase.ValidateToolNameAffix(tt.prefix, tt.suffix)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// blockingInvoker holds every call until release is closed and records the peak concurrency.
+type blockingInvoker struct {
+	started     chan struct{}
+	release     chan struct{}
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (b *blockingInvoker) Invoke(ctx context.Context, details usecase.InvocationDetails, params map[string]interface{}) (interface{}, error) {
+	n := b.inFlight.Add(1)
+	defer b.inFlight.Add(-1)
+	for {
+		peak := b.maxInFlight.Load()
+		if n <= peak || b.maxInFlight.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	b.started <- struct{}{}
+	<-b.release
+	return "ok", nil
+}
+
+func TestSyncSchemaUseCase_ToolHandler_MaxInFlight(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+	}{
+		{name: "queue", policy: usecase.InFlightPolicyQueue},
+		{name: "reject", policy: usecase.InFlightPolicyReject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			source := usecase.SchemaSourceConfig{URL: "http://example.com/openapi.yaml", MaxInFlight: 1, InFlightPolicy: tt.policy}
+			schema := domain.APISchema{Source: source.URL, Type: domain.SchemaTypeOpenAPI}
+			fetcher := new(MockSchemaFetcher)
+			fetcher.On("FetchWithConfig", ctx, configFor(source.URL)).Return(schema, nil).Once()
+			generator := new(MockToolGenerator)
+			generator.On("Generate", schema).Return([]domain.Tool{{Name: "get_pet"}}, []usecase.InvocationDetails{{Type: "http"}}, nil).Once()
+
+			var handler mcpServer.ToolHandlerFunc
+			mcpSrv := new(MockMCPServer)
+			mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				handler = args.Get(1).(mcpServer.ToolHandlerFunc)
+			}).Once()
+
+			invoker := &blockingInvoker{started: make(chan struct{}, 2), release: make(chan struct{})}
+			uc := usecase.NewSyncSchemaUseCase(
+				[]usecase.SchemaSourceConfig{source},
+				map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+				map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+				mcpSrv,
+				invoker,
+				logger,
+			)
+			require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+			require.NotNil(t, handler)
+
+			results := make(chan *mcp.CallToolResult, 2)
+			call := func() {
+				result, err := handler(ctx, mcp.CallToolRequest{})
+				assert.NoError(t, err)
+				results <- result
+			}
+
+			go call()
+			<-invoker.started
+
+			if tt.policy == usecase.InFlightPolicyReject {
+				call()
+				rejected := <-results
+				assert.True(t, rejected.IsError)
+				assert.Equal(t, map[string]any{"errorCategory": "rate_limited"}, rejected.Meta)
+				close(invoker.release)
+				assert.False(t, (<-results).IsError)
+				return
+			}
+
+			go call()
+			select {
+			case <-invoker.started:
+				t.Fatal("second call started while the first was still in flight")
+			case <-time.After(50 * time.Millisecond):
+			}
+			close(invoker.release)
+			<-invoker.started
+			assert.False(t, (<-results).IsError)
+			assert.False(t, (<-results).IsError)
+			assert.Equal(t, int32(1), invoker.maxInFlight.Load())
+		})
+	}
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_ToolRepository(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	sourceURL := "http://example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+	getPet := domain.Tool{Name: "get_pet", Description: "Get a pet"}
+	getPetDetails := usecase.InvocationDetails{Type: "http", HTTPMethod: "GET", HTTPPath: "/pets/{id}"}
+	listPets := domain.Tool{Name: "list_pets", Description: "List pets"}
+	listPetsDetails := usecase.InvocationDetails{Type: "http", HTTPMethod: "GET", HTTPPath: "/pets"}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil).Twice()
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return(
+		[]domain.Tool{getPet, listPets},
+		[]usecase.InvocationDetails{getPetDetails, listPetsDetails},
+		nil,
+	).Once()
+	generator.On("Generate", schema).Return(
+		[]domain.Tool{listPets},
+		[]usecase.InvocationDetails{listPetsDetails},
+		nil,
+	).Once()
+
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything)
+	mcpSrv.On("DeleteTools", []string{"get_pet"}).Once()
+
+	repo := memrepo.NewInMemoryToolRepository(logger)
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: sourceURL}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		new(MockToolInvoker),
+		logger,
+		usecase.WithToolRepository(repo),
+	)
+
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	found, err := repo.FindToolByName(ctx, "get_pet")
+	require.NoError(t, err)
+	assert.Equal(t, getPet, *found)
+	foundDetails, err := repo.FindInvocationDetailsByName(ctx, "get_pet")
+	require.NoError(t, err)
+	wantDetails := getPetDetails
+	wantDetails.Source = sourceURL // stored with the tool's source
+	assert.Equal(t, wantDetails, *foundDetails)
+
+	// A re-sync without get_pet removes it from the repository as well.
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	_, err = repo.FindToolByName(ctx, "get_pet")
+	assert.ErrorIs(t, err, usecase.ErrToolNotFound)
+	list, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Tool{listPets}, list)
+	mcpSrv.AssertExpectations(t)
+}
+
+func TestSyncSchemaUseCase_RestoreTools(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	repo := memrepo.NewInMemoryToolRepository(logger)
+	require.NoError(t, repo.Save(ctx,
+		[]domain.Tool{{Name: "get_pet", Description: "Get a pet"}, {Name: "old_tool", Description: "Gone upstream"}},
+		[]usecase.InvocationDetails{{Type: "http", HTTPPath: "/pets/{id}"}, {Type: "http", HTTPPath: "/old"}},
+	))
+
+	sourceURL := "http://example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil).Once()
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return(
+		[]domain.Tool{{Name: "get_pet", Description: "Get a pet"}},
+		[]usecase.InvocationDetails{{Type: "http", HTTPPath: "/pets/{id}"}},
+		nil,
+	).Once()
+
+	var registered []string
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		registered = append(registered, args.Get(0).(mcp.Tool).Name)
+	})
+	mcpSrv.On("DeleteTools", []string{"old_tool"}).Once()
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: sourceURL}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		new(MockToolInvoker),
+		logger,
+		usecase.WithToolRepository(repo),
+	)
+
+	restored, err := uc.RestoreTools(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, restored)
+	assert.ElementsMatch(t, []string{"get_pet", "old_tool"}, registered)
+
+	// The sync re-provides get_pet and drops the restored tool no source provides anymore.
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	mcpSrv.AssertExpectations(t)
+	_, err = repo.FindToolByName(ctx, "old_tool")
+	assert.ErrorIs(t, err, usecase.ErrToolNotFound)
+	_, err = repo.FindToolByName(ctx, "get_pet")
+	assert.NoError(t, err)
+}
+
+func TestSyncSchemaUseCase_ToolHandler_InvocationTimeout(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// The upstream hangs until the client gives up.
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer upstream.Close()
+
+	source := usecase.SchemaSourceConfig{URL: "http://example.com/openapi.yaml"}
+	schema := domain.APISchema{Source: source.URL, Type: domain.SchemaTypeOpenAPI}
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(source.URL)).Return(schema, nil).Once()
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return(
+		[]domain.Tool{{Name: "slow_op"}},
+		[]usecase.InvocationDetails{{Type: "http", Host: upstream.URL, HTTPMethod: http.MethodGet, HTTPPath: "/slow"}},
+		nil,
+	).Once()
+
+	var handler mcpServer.ToolHandlerFunc
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handler = args.Get(1).(mcpServer.ToolHandlerFunc)
+	}).Once()
+
+	const timeout = 100 * time.Millisecond
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{source},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		httpinvoker.New(&http.Client{}, logger),
+		logger,
+		usecase.WithInvocationTimeout(timeout),
+	)
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	require.NotNil(t, handler)
+
+	start := time.Now()
+	result, err := handler(ctx, mcp.CallToolRequest{})
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, string(usecase.ErrorCategoryTimeout), result.Meta["errorCategory"])
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invocation timeout")
+	assert.Less(t, elapsed, timeout+2*time.Second)
+}
+
+func TestSyncSchemaUseCase_Execute_UnknownSchemaType(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	uc := usecase.NewSyncSchemaUseCase(
+		nil,
+		map[domain.SchemaType]usecase.SchemaFetcher{},
+		map[domain.SchemaType]usecase.ToolGenerator{},
+		new(MockMCPServer),
+		new(MockToolInvoker),
+		logger,
+	)
+
+	err := uc.Execute(context.Background(), "ftp://example.com/openapi.yaml")
+
+	var unknownErr *usecase.UnknownSchemaTypeError
+	require.ErrorAs(t, err, &unknownErr)
+	assert.Equal(t, "ftp://example.com/openapi.yaml", unknownErr.Source)
+	assert.Contains(t, err.Error(), `could not determine schema type of source "ftp://example.com/openapi.yaml": unrecognized scheme "ftp://"`)
+	for _, scheme := range []string{"https://", "grpc://", "connect://", "github://", ".proto"} {
+		assert.Contains(t, err.Error(), scheme)
+	}
+	assert.Contains(t, err.Error(), `"type" field`)
+}
+
+// metricPoints collects reader's metrics and returns the int64 data points of the named metric.
+func metricPoints(t *testing.T, reader *sdkmetric.ManualReader, name string) []metricdata.DataPoint[int64] {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				return data.DataPoints
+			case metricdata.Gauge[int64]:
+				return data.DataPoints
+			}
+		}
+	}
+	return nil
+}
+
+// pointValue returns the value of the data point carrying attrs, or 0 if there is none.
+func pointValue(points []metricdata.DataPoint[int64], attrs ...attribute.KeyValue) int64 {
+	want := attribute.NewSet(attrs...)
+	for _, p := range points {
+		if p.Attributes.Equals(&want) {
+			return p.Value
+		}
+	}
+	return 0
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_Metrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	okURL := "http://metrics-ok.example.com/openapi.yaml"
+	failingURL := "http://metrics-failing.example.com/openapi.yaml"
+	schema := domain.APISchema{Source: okURL, Type: domain.SchemaTypeOpenAPI}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(okURL)).Return(schema, nil)
+	fetcher.On("FetchWithConfig", ctx, configFor(failingURL)).Return(domain.APISchema{}, errors.New("connection refused"))
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return(
+		[]domain.Tool{{Name: "metrics-a"}, {Name: "metrics-b"}},
+		[]usecase.InvocationDetails{{Type: "http", HTTPPath: "/a"}, {Type: "http", HTTPPath: "/b"}},
+		nil,
+	)
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything)
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: okURL}, {URL: failingURL}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		new(MockToolInvoker),
+		logger,
+	)
+
+	require.Error(t, uc.SyncAllConfiguredSources(ctx))
+	require.Error(t, uc.SyncAllConfiguredSources(ctx))
+
+	syncs := metricPoints(t, reader, "mcpizer.source.syncs")
+	assert.Equal(t, int64(2), pointValue(syncs, attribute.String("source", okURL), attribute.Bool("success", true)))
+	assert.Equal(t, int64(0), pointValue(syncs, attribute.String("source", okURL), attribute.Bool("success", false)))
+	assert.Equal(t, int64(2), pointValue(syncs, attribute.String("source", failingURL), attribute.Bool("success", false)))
+	assert.Equal(t, int64(0), pointValue(syncs, attribute.String("source", failingURL), attribute.Bool("success", true)))
+
+	generated := metricPoints(t, reader, "mcpizer.schema.tools_generated")
+	assert.Equal(t, int64(4), pointValue(generated, attribute.String("source", okURL)))
+
+	registered := metricPoints(t, reader, "mcpizer.tools.registered")
+	assert.Equal(t, int64(2), pointValue(registered))
+}
+
+func TestSyncSchemaUseCase_GenerateAll_ContentTypeOverride(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	sourceURL := "http://jsonapi.example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil).Once()
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return(
+		[]domain.Tool{{Name: "create_article"}, {Name: "list_articles"}},
+		[]usecase.InvocationDetails{
+			{Type: "http", HTTPMethod: "POST", ContentType: "application/json"},
+			{Type: "http", HTTPMethod: "GET"},
+		},
+		nil,
+	).Once()
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: sourceURL, ContentType: "application/vnd.api+json"}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		new(MockMCPServer),
+		new(MockToolInvoker),
+		logger,
+	)
+
+	generated, err := uc.GenerateAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, generated, 2)
+	assert.Equal(t, "application/vnd.api+json", generated[0].Details.ContentType)
+	assert.Empty(t, generated[1].Details.ContentType, "operations without a body keep no content type")
+}
+
+func TestSyncSchemaUseCase_GenerateAll_AcceptLanguage(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	source := usecase.SchemaSourceConfig{
+		URL:            "http://i18n.example.com/openapi.yaml",
+		Headers:        map[string]string{"Authorization": "Bearer token"},
+		AcceptLanguage: "ja-JP",
+	}
+	schema := domain.APISchema{Source: source.URL, Type: domain.SchemaTypeOpenAPI}
+
+	fetcher := new(MockSchemaFetcher)
+	fetchConfig := source
+	fetchConfig.Headers = map[string]string{"Authorization": "Bearer token", "Accept-Language": "ja-JP"}
+	fetcher.On("FetchWithConfig", ctx, fetchConfig).Return(schema, nil).Once()
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return(
+		[]domain.Tool{{Name: "get_greeting"}},
+		[]usecase.InvocationDetails{{Type: "http", HTTPMethod: "GET"}},
+		nil,
+	).Once()
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{source},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		new(MockMCPServer),
+		new(MockToolInvoker),
+		logger,
+	)
+
+	generated, err := uc.GenerateAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, generated, 1)
+	assert.Equal(t, "ja-JP", generated[0].Details.AcceptLanguage)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer token"}, source.Headers, "configured headers are not modified")
+	fetcher.AssertExpectations(t)
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_MaxTools(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	generate := func(prefix string, n int) ([]domain.Tool, []usecase.InvocationDetails) {
+		tools := make([]domain.Tool, n)
+		details := make([]usecase.InvocationDetails, n)
+		for i := range tools {
+			// Each tool links to the next one.
+			next := fmt.Sprintf("%s_%d", prefix, (i+1)%n)
+			tools[i] = domain.Tool{Name: fmt.Sprintf("%s_%d", prefix, i), Links: []domain.ToolLink{{Name: "next", Tool: next}}}
+			details[i] = usecase.InvocationDetails{Type: "http", HTTPPath: fmt.Sprintf("/%s/%d", prefix, i)}
+		}
+		return tools, details
+	}
+	urlA, urlB := "http://a.example.com/openapi.yaml", "http://b.example.com/openapi.yaml"
+	schemaA := domain.APISchema{Source: urlA, Type: domain.SchemaTypeOpenAPI}
+	schemaB := domain.APISchema{Source: urlB, Type: domain.SchemaTypeOpenAPI}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(urlA)).Return(schemaA, nil)
+	fetcher.On("FetchWithConfig", ctx, configFor(urlB)).Return(schemaB, nil)
+	generator := new(MockToolGenerator)
+	toolsA, detailsA := generate("a", 5)
+	toolsB, detailsB := generate("b", 3)
+	generator.On("Generate", schemaA).Return(toolsA, detailsA, nil)
+	generator.On("Generate", schemaB).Return(toolsB, detailsB, nil)
+
+	var registered []string
+	descriptions := make(map[string]string)
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		tool := args.Get(0).(mcp.Tool)
+		registered = append(registered, tool.Name)
+		descriptions[tool.Name] = tool.Description
+	})
+
+	uc := usecase.NewSyncSchemaUseCase(
+		// Source A is held to the default of 3; source B to its own 2, of which
+		// the total limit of 4 leaves room for only 1.
+		[]usecase.SchemaSourceConfig{{URL: urlA}, {URL: urlB, MaxTools: 2}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		new(MockToolInvoker),
+		logger,
+		usecase.WithMaxTools(3, 4),
+	)
+
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	assert.Equal(t, []string{"a_0", "a_1", "a_2", "b_0"}, registered)
+	// Links to dropped tools are removed.
+	assert.Contains(t, descriptions["a_1"], "a_2 (next)")
+	assert.NotContains(t, descriptions["a_2"], "Follow-up tools")
+	assert.NotContains(t, descriptions["b_0"], "Follow-up tools")
+
+	// A re-sync keeps the same tools instead of letting a source's own tools count against it.
+	registered = nil
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	assert.Empty(t, registered)
+	mcpSrv.AssertNotCalled(t, "DeleteTools", mock.Anything)
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_CollidingToolNames(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// Two services declaring the same operationIds, whose tools are not namespaced.
+	spec := func(host string) string {
+		return `
+openapi: 3.0.0
+info:
+  title: Users
+  version: 1.0.0
+servers:
+  - url: https://` + host + `
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: ok
+          links:
+            GetUser:
+              operationId: getUser
+              parameters:
+                userId: $response.body#/0/id
+  /users/{userId}:
+    get:
+      operationId: getUser
+      parameters:
+        - name: userId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+`
+	}
+	dir := t.TempDir()
+	pathA, pathB := filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")
+	require.NoError(t, os.WriteFile(pathA, []byte(spec("a.example.com")), 0o600))
+	require.NoError(t, os.WriteFile(pathB, []byte(spec("b.example.com")), 0o600))
+	sum := sha256.Sum256([]byte(pathB))
+	suffix := "_" + hex.EncodeToString(sum[:])[:6]
+
+	registered := make(map[string]mcp.Tool)
+	handlers := make(map[string]mcpServer.ToolHandlerFunc)
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		tool := args.Get(0).(mcp.Tool)
+		registered[tool.Name] = tool
+		handlers[tool.Name] = args.Get(1).(mcpServer.ToolHandlerFunc)
+	})
+	var hosts []string
+	invoker := new(MockToolInvoker)
+	invoker.On("Invoke", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		hosts = append(hosts, args.Get(1).(usecase.InvocationDetails).Host)
+	}).Return(map[string]interface{}{}, nil)
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: pathA}, {URL: pathB}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: openapi.NewSchemaFetcher(nil, logger)},
+		map[domain.SchemaType]usecase.ToolGenerator{
+			domain.SchemaTypeOpenAPI: openapi.NewToolGenerator(logger, openapi.WithNamespace(openapi.NoNamespace)),
+		},
+		mcpSrv,
+		invoker,
+		logger,
+	)
+
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	// The first source keeps the names; the second's tools are suffixed instead of replacing them.
+	names := make([]string, 0, len(registered))
+	for name := range registered {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"listusers", "getuser", "listusers" + suffix, "getuser" + suffix}, names)
+	// Links follow the renamed tools.
+	assert.Contains(t, registered["listusers"].Description, "- getuser (GetUser)")
+	assert.Contains(t, registered["listusers"+suffix].Description, "- getuser"+suffix+" (GetUser)")
+
+	for _, name := range []string{"getuser", "getuser" + suffix} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]interface{}{"userId": "42"}
+		_, err := handlers[name](ctx, req)
+		require.NoError(t, err)
+	}
+	require.Len(t, hosts, 2)
+	assert.Contains(t, hosts[0], "a.example.com")
+	assert.Contains(t, hosts[1], "b.example.com")
+
+	// A re-sync keeps the names, so no tool is re-registered or removed.
+	clear(registered)
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	assert.Empty(t, registered)
+	mcpSrv.AssertNotCalled(t, "DeleteTools", mock.Anything)
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_MaxToolsStable(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// The generator emits operations in map order, so their order differs between syncs.
+	spec := func(title string) string {
+		var b strings.Builder
+		fmt.Fprintf(&b, "openapi: 3.0.0\ninfo:\n  title: %s\n  version: 1.0.0\nservers:\n  - url: https://api.example.com\npaths:\n", title)
+		for i := 0; i < 20; i++ {
+			fmt.Fprintf(&b, "  /items%02d:\n    get:\n      operationId: getItem%02d\n      responses:\n        \"200\":\n          description: ok\n", i, i)
+		}
+		return b.String()
+	}
+	dir := t.TempDir()
+	pathA, pathB := filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")
+	require.NoError(t, os.WriteFile(pathA, []byte(spec("Alpha")), 0o600))
+	require.NoError(t, os.WriteFile(pathB, []byte(spec("Beta")), 0o600))
+
+	registered := make(map[string]bool)
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		registered[args.Get(0).(mcp.Tool).Name] = true
+	})
+	mcpSrv.On("DeleteTools", mock.Anything).Run(func(args mock.Arguments) {
+		for _, name := range args.Get(0).([]string) {
+			delete(registered, name)
+		}
+	})
+
+	sources := []usecase.SchemaSourceConfig{{URL: pathA}, {URL: pathB}}
+	uc := usecase.NewSyncSchemaUseCase(
+		sources,
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: openapi.NewSchemaFetcher(nil, logger)},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: openapi.NewToolGenerator(logger)},
+		mcpSrv,
+		new(MockToolInvoker),
+		logger,
+		usecase.WithMaxTools(5, 8),
+	)
+
+	// The later source syncing first does not keep the earlier one's share of the total.
+	for _, res := range uc.SyncSources(ctx, []usecase.SchemaSourceConfig{sources[1], sources[0]}) {
+		require.NoError(t, res.Err)
+	}
+	want := map[string]bool{
+		"alpha_getitem00": true, "alpha_getitem01": true, "alpha_getitem02": true, "alpha_getitem03": true, "alpha_getitem04": true,
+		"beta_getitem00": true, "beta_getitem01": true, "beta_getitem02": true,
+	}
+	assert.Equal(t, want, registered)
+
+	// Re-syncs keep the same tools, so none is registered or removed again.
+	mcpSrv.Calls = nil
+	for range 3 {
+		require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	}
+	mcpSrv.AssertNotCalled(t, "AddTool", mock.Anything, mock.Anything)
+	mcpSrv.AssertNotCalled(t, "DeleteTools", mock.Anything)
+	assert.Equal(t, want, registered)
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_StaticParams(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	sourceURL := "http://versioned.example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+	tools := []domain.Tool{{Name: "listItems"}, {Name: "createItem"}}
+	generatedQuery := map[string]string{"format": "json"}
+	details := []usecase.InvocationDetails{
+		{Type: "http", HTTPMethod: "GET", HTTPPath: "/items", StaticQueryParams: generatedQuery},
+		{Type: "http", HTTPMethod: "POST", HTTPPath: "/items"},
+	}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil)
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return(tools, details, nil)
+
+	var handlers []mcpServer.ToolHandlerFunc
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handlers = append(handlers, args.Get(1).(mcpServer.ToolHandlerFunc))
+	})
+
+	var invoked []usecase.InvocationDetails
+	invoker := new(MockToolInvoker)
+	invoker.On("Invoke", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		invoked = append(invoked, args.Get(1).(usecase.InvocationDetails))
+	}).Return(map[string]interface{}{}, nil)
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{
+			URL:               sourceURL,
+			StaticQueryParams: map[string]string{"apiVersion": "2024-06-01"},
+			StaticHeaders:     map[string]string{"X-Client": "mcpizer"},
+			StaticBodyParams:  map[string]interface{}{"source": "mcp"},
+		}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		invoker,
+		logger,
+	)
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	require.Len(t, handlers, 2)
+
+	for _, handler := range handlers {
+		result, err := handler(ctx, mcp.CallToolRequest{})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+	}
+	require.Len(t, invoked, 2)
+	for _, d := range invoked {
+		assert.Equal(t, "2024-06-01", d.StaticQueryParams["apiVersion"], d.HTTPPath)
+		assert.Equal(t, map[string]string{"X-Client": "mcpizer"}, d.HeaderParams)
+		assert.Equal(t, map[string]interface{}{"source": "mcp"}, d.StaticBodyParams)
+	}
+	// The generated static query parameters are kept, without modifying the generated map.
+	assert.Equal(t, map[string]string{"format": "json", "apiVersion": "2024-06-01"}, invoked[0].StaticQueryParams)
+	assert.Equal(t, map[string]string{"format": "json"}, generatedQuery)
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_ResponseHeaders(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	sourceURL := "http://orders.example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+	bodySchema := domain.JSONSchemaProps{
+		Type:       "object",
+		Properties: map[string]domain.JSONSchemaProps{"receipt": {Type: "string", Format: "byte"}},
+	}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil)
+	generator := new(MockToolGenerator)
+	// Each sync gets its own slices, as they are modified.
+	for range 2 {
+		generator.On("Generate", schema).Return(
+			[]domain.Tool{{Name: "createOrder", OutputSchema: &bodySchema}},
+			[]usecase.InvocationDetails{{Type: "http", HTTPMethod: "POST", HTTPPath: "/orders"}},
+			nil,
+		).Once()
+	}
+
+	var handler mcpServer.ToolHandlerFunc
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handler = args.Get(1).(mcpServer.ToolHandlerFunc)
+	})
+
+	invoker := new(MockToolInvoker)
+	invoker.On("Invoke", mock.Anything, mock.Anything, mock.Anything).Return(map[string]interface{}{
+		"body":    map[string]interface{}{"receipt": "b2s="},
+		"headers": map[string]string{"Location": "/orders/7"},
+	}, nil)
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: sourceURL, ResponseHeaders: []string{"Location"}}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		invoker,
+		logger,
+		usecase.WithByteFieldDecoding(true),
+	)
+
+	// The output schema advertises the body next to the headers.
+	generated, err := uc.GenerateAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, generated, 1)
+	assert.Equal(t, &domain.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]domain.JSONSchemaProps{
+			"body": bodySchema,
+			"headers": {
+				Type:       "object",
+				Properties: map[string]domain.JSONSchemaProps{"Location": {Type: "string"}},
+			},
+		},
+		Required: []string{"body", "headers"},
+	}, generated[0].Tool.OutputSchema)
+	assert.Equal(t, []string{"Location"}, generated[0].Details.ResponseHeaders)
+
+	// Byte fields of the body are decoded.
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	require.NotNil(t, handler)
+	result, err := handler(ctx, mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	require.Len(t, result.Content, 1)
+	assert.JSONEq(t, `{"body":{"receipt":"ok"},"headers":{"Location":"/orders/7"}}`, result.Content[0].(mcp.TextContent).Text)
+}
+
+func TestSyncSchemaUseCase_ToolHandler_ByteFieldDecoding(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	sourceURL := "grpc://files.example.com:50051"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeGRPC}
+	details := usecase.InvocationDetails{Type: "grpc", GRPCService: "files.v1.Files", GRPCMethod: "Get"}
+	bytesSchema := domain.JSONSchemaProps{Type: "string", Format: "byte"}
+	tool := domain.Tool{
+		Name:        "Files_Get",
+		InputSchema: domain.JSONSchemaProps{Type: "object"},
+		OutputSchema: &domain.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]domain.JSONSchemaProps{
+				"name":    {Type: "string"},
+				"content": bytesSchema,
+				"binary":  bytesSchema,
+				"chunks":  {Type: "array", Items: &bytesSchema},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		want    string
+	}{
+		{
+			name: "disabled keeps base64",
+			want: `{"binary":"/w==","chunks":["aGk="],"content":"aGVsbG8gd29ybGQ=","name":"aGk="}`,
+		},
+		{
+			name:    "enabled decodes text",
+			enabled: true,
+			// name is not a byte field and binary is not UTF-8, so both stay as they are.
+			want: `{"binary":"/w==","chunks":["hi"],"content":"hello world","name":"aGk="}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := new(MockSchemaFetcher)
+			fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil).Once()
+			generator := new(MockToolGenerator)
+			generator.On("Generate", schema).Return([]domain.Tool{tool}, []usecase.InvocationDetails{details}, nil).Once()
+
+			var handler mcpServer.ToolHandlerFunc
+			mcpSrv := new(MockMCPServer)
+			mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				handler = args.Get(1).(mcpServer.ToolHandlerFunc)
+			}).Once()
+
+			invoker := new(MockToolInvoker)
+			invoker.On("Invoke", mock.Anything, details, mock.Anything).Return(map[string]interface{}{
+				"name":    "aGk=",
+				"content": "aGVsbG8gd29ybGQ=",
+				"binary":  "/w==",
+				"chunks":  []interface{}{"aGk="},
+			}, nil).Once()
+
+			uc := usecase.NewSyncSchemaUseCase(
+				nil,
+				map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeGRPC: fetcher},
+				map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeGRPC: generator},
+				mcpSrv,
+				invoker,
+				logger,
+				usecase.WithByteFieldDecoding(tt.enabled),
+			)
+			require.NoError(t, uc.Execute(ctx, sourceURL))
+			require.NotNil(t, handler)
+
+			result, err := handler(ctx, mcp.CallToolRequest{})
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+			require.Len(t, result.Content, 1)
+			assert.JSONEq(t, tt.want, result.Content[0].(mcp.TextContent).Text)
+		})
+	}
+}
+
+func TestSyncSchemaUseCase_ToolHandler_BinaryResult(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	sourceURL := "http://example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+	details := usecase.InvocationDetails{Type: "http", HTTPPath: "/files/{id}"}
+	png := []byte("\x89PNG\r\n\x1a\n")
+
+	tests := []struct {
+		name   string
+		result usecase.BinaryResult
+		want   []mcp.Content
+	}{
+		{
+			name:   "image",
+			result: usecase.BinaryResult{MIMEType: "image/png", Data: png, URI: "http://example.com/files/1"},
+			want:   []mcp.Content{mcp.NewImageContent(base64.StdEncoding.EncodeToString(png), "image/png")},
+		},
+		{
+			name:   "audio",
+			result: usecase.BinaryResult{MIMEType: "audio/wav", Data: []byte("RIFF")},
+			want:   []mcp.Content{mcp.NewAudioContent("UklGRg==", "audio/wav")},
+		},
+		{
+			name:   "other binary",
+			result: usecase.BinaryResult{MIMEType: "application/pdf", Data: []byte("%PDF"), URI: "http://example.com/files/2"},
+			want: []mcp.Content{mcp.NewEmbeddedResource(mcp.BlobResourceContents{
+				URI: "http://example.com/files/2", MIMEType: "application/pdf", Blob: "JVBERg==",
+			})},
+		},
+		{
+			name:   "response headers",
+			result: usecase.BinaryResult{MIMEType: "image/png", Data: png, Headers: map[string]string{"Location": "/files/3"}},
+			want: []mcp.Content{
+				mcp.NewImageContent(base64.StdEncoding.EncodeToString(png), "image/png"),
+				mcp.NewTextContent(`{"headers":{"Location":"/files/3"}}`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := new(MockSchemaFetcher)
+			fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil).Once()
+			generator := new(MockToolGenerator)
+			generator.On("Generate", schema).Return([]domain.Tool{{Name: "get_file"}}, []usecase.InvocationDetails{details}, nil).Once()
+
+			var handler mcpServer.ToolHandlerFunc
+			mcpSrv := new(MockMCPServer)
+			mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				handler = args.Get(1).(mcpServer.ToolHandlerFunc)
+			}).Once()
+
+			invoker := new(MockToolInvoker)
+			invoker.On("Invoke", mock.Anything, details, mock.Anything).Return(tt.result, nil).Once()
+
+			uc := usecase.NewSyncSchemaUseCase(
+				nil,
+				map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+				map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+				mcpSrv,
+				invoker,
+				logger,
+			)
+			require.NoError(t, uc.Execute(ctx, sourceURL))
+			require.NotNil(t, handler)
+
+			result, err := handler(ctx, mcp.CallToolRequest{})
+			require.NoError(t, err)
+			assert.False(t, result.IsError)
+			assert.Equal(t, tt.want, result.Content)
+		})
+	}
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_Disabled(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	enabledURL := "http://orders.example.com/openapi.yaml"
+	disabledURL := "http://flaky.example.com/openapi.yaml"
+	schema := domain.APISchema{Source: enabledURL, Type: domain.SchemaTypeOpenAPI}
+	tool := domain.Tool{Name: "orders_list", Description: "List orders"}
+	details := usecase.InvocationDetails{Type: "http", HTTPPath: "/orders"}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(enabledURL)).Return(schema, nil)
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return([]domain.Tool{tool}, []usecase.InvocationDetails{details}, nil)
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mcp.NewTool("orders_list", mcp.WithDescription("List orders")), mock.Anything).Once()
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: enabledURL}, {URL: disabledURL, Disabled: true}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		new(MockToolInvoker),
+		logger,
+	)
+
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	generated, err := uc.GenerateAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, generated, 1)
+	assert.Equal(t, enabledURL, generated[0].Source)
+	fetcher.AssertNotCalled(t, "FetchWithConfig", mock.Anything, configFor(disabledURL))
+	mcpSrv.AssertExpectations(t)
+
+	// Disabling a source on reload removes the tools it registered.
+	mcpSrv.On("DeleteTools", []string{"orders_list"}).Once()
+	result, err := uc.ReloadSources(ctx, []usecase.SchemaSourceConfig{{URL: enabledURL, Disabled: true}, {URL: disabledURL, Disabled: true}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{enabledURL}, result.Updated)
+	mcpSrv.AssertExpectations(t)
+	fetcher.AssertNumberOfCalls(t, "FetchWithConfig", 2)
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_PathRewrite(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var gotPaths []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	sourceURL := "http://gateway.example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+	tools := []domain.Tool{{Name: "getUser"}, {Name: "listTeams"}}
+	details := []usecase.InvocationDetails{
+		{Type: "http", Host: upstream.URL, BasePath: "/api", HTTPMethod: "GET", HTTPPath: "/users/{id}", PathParams: []string{"id"}},
+		{Type: "http", Host: upstream.URL, HTTPMethod: "GET", HTTPPath: "/api/v2/teams"},
+	}
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil)
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return(tools, details, nil)
+
+	var handlers []mcpServer.ToolHandlerFunc
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handlers = append(handlers, args.Get(1).(mcpServer.ToolHandlerFunc))
+	})
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{
+			URL:                    sourceURL,
+			PathRewrite:            regexp.MustCompile(`^/api(/v\d+)?/`),
+			PathRewriteReplacement: "/internal$1/",
+		}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		httpinvoker.New(upstream.Client(), logger),
+		logger,
+	)
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+	require.Len(t, handlers, 2)
+
+	for _, handler := range handlers {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"id": "42"}
+		result, err := handler(ctx, request)
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+	}
+	assert.Equal(t, []string{"/internal/users/42", "/internal/v2/teams"}, gotPaths)
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_BasePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		basePath string
+		rewrite  *regexp.Regexp
+		wantPath string
+	}{
+		{name: "base path from the servers block", wantPath: "/v1/users/42"},
+		{name: "overridden", basePath: "/public/v1", wantPath: "/public/v1/users/42"},
+		{name: "dropped", basePath: "/", wantPath: "/users/42"},
+		{name: "rewrite sees the override", basePath: "/public/v1", rewrite: regexp.MustCompile("^/public"), wantPath: "/v1/users/42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			var gotPath string
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer upstream.Close()
+
+			sourceURL := "http://gateway.example.com/openapi.yaml"
+			schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+			tools := []domain.Tool{{Name: "getUser"}}
+			details := []usecase.InvocationDetails{
+				{Type: "http", Host: upstream.URL, BasePath: "/v1", HTTPMethod: "GET", HTTPPath: "/users/{id}", PathParams: []string{"id"}},
+			}
+			fetcher := new(MockSchemaFetcher)
+			fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil)
+			generator := new(MockToolGenerator)
+			generator.On("Generate", schema).Return(tools, details, nil)
+
+			var handler mcpServer.ToolHandlerFunc
+			mcpSrv := new(MockMCPServer)
+			mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				handler = args.Get(1).(mcpServer.ToolHandlerFunc)
+			})
+
+			uc := usecase.NewSyncSchemaUseCase(
+				[]usecase.SchemaSourceConfig{{URL: sourceURL, BasePath: tt.basePath, PathRewrite: tt.rewrite}},
+				map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+				map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+				mcpSrv,
+				httpinvoker.New(upstream.Client(), logger),
+				logger,
+			)
+			require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+			require.NotNil(t, handler)
+
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{"id": "42"}
+			result, err := handler(ctx, request)
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+			assert.Equal(t, tt.wantPath, gotPath)
+		})
+	}
+}
+
+func TestSyncSchemaUseCase_Execute_LinksInDescription(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	sourceURL := "http://example.com/openapi.yaml"
+	schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+	tools := []domain.Tool{
+		{
+			Name:        "users_createuser",
+			Description: "Create a user.",
+			Links: []domain.ToolLink{{
+				Name:        "GetUserByUserId",
+				Tool:        "users_getuser",
+				Description: "Fetch the created user.",
+				Parameters:  map[string]string{"userId": "$response.body#/id"},
+			}},
+		},
+		{Name: "users_getuser", Description: "Get a user."},
+	}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil).Once()
+	generator := new(MockToolGenerator)
+	generator.On("Generate", schema).Return(tools, []usecase.InvocationDetails{{Type: "http"}, {Type: "http"}}, nil).Once()
+
+	registered := make(map[string]mcp.Tool)
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		tool := args.Get(0).(mcp.Tool)
+		registered[tool.Name] = tool
+	})
+
+	uc := usecase.NewSyncSchemaUseCase(
+		nil,
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		new(MockToolInvoker),
+		logger,
+		usecase.WithToolNameAffix("staging_", ""),
+	)
+	require.NoError(t, uc.Execute(ctx, sourceURL))
+
+	// Linked tools are named as registered, including the affix.
+	assert.Equal(t, "Create a user.\n\nFollow-up tools:\n"+
+		"- staging_users_getuser (GetUserByUserId): Fetch the created user. [arguments: userId = $response.body#/id]",
+		registered["staging_users_createuser"].Description)
+	assert.Equal(t, "Get a user.", registered["staging_users_getuser"].Description)
+}
+
+// MockMultiSchemaFetcher is a MockSchemaFetcher that also discovers several schemas.
+type MockMultiSchemaFetcher struct {
+	MockSchemaFetcher
+}
+
+func (m *MockMultiSchemaFetcher) FetchAll(ctx context.Context, config usecase.SchemaSourceConfig) ([]domain.APISchema, error) {
+	args := m.Called(ctx, config)
+	return args.Get(0).([]domain.APISchema), args.Error(1)
+}
+
+func TestSyncSchemaUseCase_SyncAllConfiguredSources_DiscoverAllNamespaces(t *testing.T) {
+	ctx := context.Background()
+	source := usecase.SchemaSourceConfig{URL: "https://api.example.com", DiscoverAll: true}
+	specs := []string{
+		"https://api.example.com/v1/openapi.json",
+		"https://api.example.com/v2/openapi.json",
+		"https://api.example.com/v2/admin/openapi.json",
+	}
+	// Long enough that the version namespace pushes it past MaxToolNameLength.
+	longName := "reports_" + strings.Repeat("x", usecase.MaxToolNameLength-len("reports_")-1)
+
+	fetcher := new(MockMultiSchemaFetcher)
+	generator := new(MockToolGenerator)
+	var schemas []domain.APISchema
+	for _, spec := range specs {
+		schema := domain.APISchema{Source: spec, Type: domain.SchemaTypeOpenAPI}
+		schemas = append(schemas, schema)
+		generator.On("Generate", schema).Return(
+			[]domain.Tool{
+				{Name: "listusers"},
+				{Name: "getuser"},
+				{Name: longName},
+			},
+			[]usecase.InvocationDetails{{Type: "http"}, {Type: "http"}, {Type: "http"}}, nil)
+	}
+	fetcher.On("FetchAll", ctx, source).Return(schemas, nil)
+
+	var names []string
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		names = append(names, args.Get(0).(mcp.Tool).Name)
+	})
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{source},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		new(MockToolInvoker),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+
+	require.Len(t, names, 9)
+	unique := make(map[string]bool)
+	for _, name := range names {
+		assert.LessOrEqual(t, len(name), usecase.MaxToolNameLength, name)
+		unique[name] = true
+	}
+	assert.Len(t, unique, 9, "tool names of specs sharing a version are disambiguated")
+	assert.Contains(t, unique, "v1_listusers")
+	assert.NotContains(t, unique, "v2_listusers")
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SyncToolName is the name of the meta-tool that re-syncs schema sources from within
+// an MCP session. Configured tool name affixes apply to it as well.
+const SyncToolName = "mcpizer_sync"
+
+// SyncToolResult is how a sync triggered by the sync meta-tool changed the registered tools.
+type SyncToolResult struct {
+	Added   int      `json:"added"`            // Tools registered that were not before
+	Removed int      `json:"removed"`          // Tools unregistered
+	Tools   int      `json:"tools"`            // Tools registered after the sync
+	Errors  []string `json:"errors,omitempty"` // Sources that failed to sync
+}
+
+// RegisterSyncTool registers the sync meta-tool, which syncs one configured source,
+// given by its URL, or all of them and reports the tools added and removed. Only
+// configured sources can be synced, so callers cannot make mcpizer fetch other URLs.
+func (uc *SyncSchemaUseCase) RegisterSyncTool() {
+	tool := mcp.NewTool(affixToolName(uc.toolNamePrefix, SyncToolName, uc.toolNameSuffix),
+		mcp.WithDescription("Re-fetches the API schemas of the configured sources and updates the "+
+			"available tools. Returns the number of tools added and removed."),
+		mcp.WithString("source", mcp.Description("URL of the configured source to sync; all sources when omitted")),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+	)
+	uc.mcpServer.AddTool(tool, uc.syncToolHandler)
+	uc.logger.Info("Registered sync tool.", slog.String("toolName", tool.Name))
+}
+
+// syncToolHandler answers calls of the sync meta-tool.
+func (uc *SyncSchemaUseCase) syncToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	source := request.GetString("source", "")
+	var syncSource func() error
+	if source == "" {
+		syncSource = func() error { return uc.SyncAllConfiguredSources(ctx) }
+	} else {
+		config, ok := uc.configuredSource(source)
+		if !ok {
+			return newToolErrorResult(NewInvocationError(ErrorCategoryNotFound,
+				fmt.Errorf("no configured source %s", source)), ErrorCategoryNotFound), nil
+		}
+		syncSource = func() error { return uc.processSingleSourceAndRegister(ctx, config) }
+	}
+
+	before := uc.registeredToolNames()
+	syncErr := syncSource()
+	after := uc.registeredToolNames()
+
+	result := SyncToolResult{Tools: len(after)}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			result.Added++
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			result.Removed++
+		}
+	}
+	if syncErr != nil {
+		uc.logger.Warn("Sync requested by the sync tool failed.", slog.String("source", source), slog.Any("error", syncErr))
+		result.Errors = []string{syncErr.Error()}
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sync result: %w", err)
+	}
+	toolResult := mcp.NewToolResultText(string(data))
+	toolResult.IsError = syncErr != nil
+	return toolResult, nil
+}
+
+// configuredSource returns the configuration of the configured source with url.
+func (uc *SyncSchemaUseCase) configuredSource(url string) (SchemaSourceConfig, bool) {
+	for _, source := range uc.configuredSources() {
+		if source.URL == url {
+			return source, true
+		}
+	}
+	return SchemaSourceConfig{}, false
+}
+
+// registeredToolNames returns the names of the tools currently registered by sources.
+func (uc *SyncSchemaUseCase) registeredToolNames() map[string]struct{} {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	names := make(map[string]struct{}, len(uc.registered))
+	for name := range uc.registered {
+		names[name] = struct{}{}
+	}
+	return names
+}
@@ -0,0 +1,109 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpServer "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/domain"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+func TestSyncSchemaUseCase_RegisterSyncTool(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	petsURL := "http://pets.example.com/openapi.yaml"
+	storeURL := "http://store.example.com/openapi.yaml"
+	petsSchema := domain.APISchema{Source: petsURL, Type: domain.SchemaTypeOpenAPI}
+	storeSchema := domain.APISchema{Source: storeURL, Type: domain.SchemaTypeOpenAPI}
+	httpDetails := func(path string) usecase.InvocationDetails {
+		return usecase.InvocationDetails{Type: "http", Host: "http://example.com", HTTPMethod: "GET", HTTPPath: path}
+	}
+
+	fetcher := new(MockSchemaFetcher)
+	fetcher.On("FetchWithConfig", mock.Anything, configFor(petsURL)).Return(petsSchema, nil)
+	fetcher.On("FetchWithConfig", mock.Anything, configFor(storeURL)).Return(storeSchema, nil).Once()
+	fetcher.On("FetchWithConfig", mock.Anything, configFor(storeURL)).Return(domain.APISchema{}, errors.New("store is down"))
+	generator := new(MockToolGenerator)
+	// The pets API gains a tool and drops another between the first and second sync.
+	generator.On("Generate", petsSchema).Return(
+		[]domain.Tool{{Name: "pets_list"}, {Name: "pets_get"}},
+		[]usecase.InvocationDetails{httpDetails("/pets"), httpDetails("/pets/1")},
+		nil,
+	).Once()
+	generator.On("Generate", petsSchema).Return(
+		[]domain.Tool{{Name: "pets_list"}, {Name: "pets_create"}, {Name: "pets_delete"}},
+		[]usecase.InvocationDetails{httpDetails("/pets"), httpDetails("/pets"), httpDetails("/pets/1")},
+		nil,
+	)
+	generator.On("Generate", storeSchema).Return(
+		[]domain.Tool{{Name: "store_order"}},
+		[]usecase.InvocationDetails{httpDetails("/order")},
+		nil,
+	)
+
+	handlers := make(map[string]mcpServer.ToolHandlerFunc)
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handlers[args.Get(0).(mcp.Tool).Name] = args.Get(1).(mcpServer.ToolHandlerFunc)
+	})
+	var deleted []string
+	mcpSrv.On("DeleteTools", mock.Anything).Run(func(args mock.Arguments) {
+		deleted = append(deleted, args.Get(0).([]string)...)
+	})
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: petsURL}, {URL: storeURL}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+		mcpSrv,
+		new(MockToolInvoker),
+		logger,
+	)
+	uc.RegisterSyncTool()
+	require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+
+	syncTool := handlers[usecase.SyncToolName]
+	require.NotNil(t, syncTool)
+	call := func(args map[string]any) (usecase.SyncToolResult, *mcp.CallToolResult) {
+		var request mcp.CallToolRequest
+		request.Params.Arguments = args
+		result, err := syncTool(ctx, request)
+		require.NoError(t, err)
+		var synced usecase.SyncToolResult
+		_ = json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &synced)
+		return synced, result
+	}
+
+	// One source: pets_get is gone, pets_create and pets_delete are new.
+	synced, result := call(map[string]any{"source": petsURL})
+	assert.False(t, result.IsError)
+	assert.Equal(t, usecase.SyncToolResult{Added: 2, Removed: 1, Tools: 4}, synced)
+	assert.Contains(t, handlers, "pets_create")
+	assert.Equal(t, []string{"pets_get"}, deleted)
+
+	// All sources: nothing changes for pets, and the store fails to sync but keeps its tool.
+	synced, result = call(nil)
+	assert.True(t, result.IsError)
+	assert.Equal(t, 4, synced.Tools)
+	assert.Zero(t, synced.Added)
+	assert.Zero(t, synced.Removed)
+	require.Len(t, synced.Errors, 1)
+	assert.Contains(t, synced.Errors[0], "store is down")
+
+	// Sources that are not configured cannot be synced.
+	_, result = call(map[string]any{"source": "http://evil.example.com/openapi.yaml"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "no configured source")
+	fetcher.AssertNotCalled(t, "FetchWithConfig", mock.Anything, configFor("http://evil.example.com/openapi.yaml"))
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults of the header carrying a source's token file credential.
+const (
+	defaultAuthHeader = "Authorization"
+	defaultAuthScheme = "Bearer"
+)
+
+// tokenFile is a credential read from a file, such as a Kubernetes secret mounted
+// into the container, and sent in a header on every HTTP tool call of a source.
+type tokenFile struct {
+	config tokenFileConfig // header and scheme defaulted as sent
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	token  string
+	readAt time.Time
+}
+
+// tokenFileConfig is a source's token file configuration. Without a configured
+// header, the token is sent as "Authorization: Bearer <token>".
+type tokenFileConfig struct {
+	path    string
+	header  string
+	scheme  string
+	refresh time.Duration
+}
+
+func tokenFileConfigOf(source SchemaSourceConfig) tokenFileConfig {
+	config := tokenFileConfig{
+		path:    source.AuthTokenFile,
+		header:  source.AuthHeader,
+		scheme:  source.AuthScheme,
+		refresh: source.AuthRefreshInterval,
+	}
+	if config.header == "" {
+		config.header = defaultAuthHeader
+		if config.scheme == "" {
+			config.scheme = defaultAuthScheme
+		}
+	}
+	return config
+}
+
+// newTokenFile reads the token file configured for source.
+func newTokenFile(source SchemaSourceConfig, logger *slog.Logger) (*tokenFile, error) {
+	f := &tokenFile{
+		config: tokenFileConfigOf(source),
+		logger: logger.With(slog.String("source", source.URL), slog.String("tokenFile", source.AuthTokenFile)),
+	}
+	token, err := readToken(f.config.path)
+	if err != nil {
+		return nil, err
+	}
+	f.token, f.readAt = token, time.Now()
+	return f, nil
+}
+
+// headerValue returns the header value carrying the token. The file is read again
+// once the refresh interval has passed, so that rotated secrets are picked up; if
+// that fails, the previous token is kept.
+func (f *tokenFile) headerValue() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.config.refresh > 0 && time.Since(f.readAt) >= f.config.refresh {
+		if token, err := readToken(f.config.path); err != nil {
+			f.logger.Warn("Failed to re-read token file, keeping the previous token.", slog.Any("error", err))
+		} else {
+			f.token = token
+		}
+		f.readAt = time.Now()
+	}
+	if f.config.scheme == "" {
+		return f.token
+	}
+	return f.config.scheme + " " + f.token
+}
+
+// readToken reads a token from path, without surrounding whitespace such as the
+// trailing newline of a file written by an editor.
+func readToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+	return token, nil
+}
+
+// updateTokenFile installs, replaces, or removes the token file credential of
+// source so that it matches its configuration. It fails when a newly configured
+// file cannot be read.
+func (uc *SyncSchemaUseCase) updateTokenFile(source SchemaSourceConfig) error {
+	uc.tokenFilesMu.Lock()
+	defer uc.tokenFilesMu.Unlock()
+
+	if source.AuthTokenFile == "" {
+		delete(uc.tokenFiles, source.URL)
+		return nil
+	}
+	if f, ok := uc.tokenFiles[source.URL]; ok && f.config == tokenFileConfigOf(source) {
+		return nil
+	}
+	f, err := newTokenFile(source, uc.logger)
+	if err != nil {
+		return fmt.Errorf("source '%s': %w", source.URL, err)
+	}
+	uc.tokenFiles[source.URL] = f
+	return nil
+}
+
+// tokenFileFor returns the token file credential of source, or nil if it has none.
+func (uc *SyncSchemaUseCase) tokenFileFor(source string) *tokenFile {
+	uc.tokenFilesMu.Lock()
+	defer uc.tokenFilesMu.Unlock()
+	return uc.tokenFiles[source]
+}
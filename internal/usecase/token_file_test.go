@@ -0,0 +1,185 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpServer "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/i2y/mcpizer/internal/adapter/outbound/memrepo"
+	"github.com/i2y/mcpizer/internal/domain"
+	"github.com/i2y/mcpizer/internal/usecase"
+)
+
+func TestSyncSchemaUseCase_AuthTokenFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		scheme     string
+		refresh    time.Duration
+		wantHeader string
+		wantFirst  string
+		wantSecond string // after the file was rotated
+	}{
+		{
+			name:       "bearer token read once",
+			wantHeader: "Authorization",
+			wantFirst:  "Bearer first-token",
+			wantSecond: "Bearer first-token",
+		},
+		{
+			name:       "custom header re-read for rotation",
+			header:     "X-API-Key",
+			refresh:    time.Nanosecond,
+			wantHeader: "X-API-Key",
+			wantFirst:  "first-token",
+			wantSecond: "second-token",
+		},
+		{
+			name:       "custom scheme",
+			scheme:     "Token",
+			wantHeader: "Authorization",
+			wantFirst:  "Token first-token",
+			wantSecond: "Token first-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			tokenPath := filepath.Join(t.TempDir(), "token")
+			require.NoError(t, os.WriteFile(tokenPath, []byte("first-token\n"), 0o600))
+
+			sourceURL := "http://billing.example.com/openapi.yaml"
+			schema := domain.APISchema{Source: sourceURL, Type: domain.SchemaTypeOpenAPI}
+			tool := domain.Tool{Name: "billing_getinvoice", InputSchema: domain.JSONSchemaProps{Type: "object"}}
+			details := usecase.InvocationDetails{
+				Type:         "http",
+				Host:         "http://billing.example.com",
+				HTTPMethod:   "GET",
+				HTTPPath:     "/invoices",
+				HeaderParams: map[string]string{"X-Tenant": "acme"},
+			}
+
+			fetcher := new(MockSchemaFetcher)
+			fetcher.On("FetchWithConfig", ctx, configFor(sourceURL)).Return(schema, nil)
+			generator := new(MockToolGenerator)
+			generator.On("Generate", schema).Return([]domain.Tool{tool}, []usecase.InvocationDetails{details}, nil)
+			var handler mcpServer.ToolHandlerFunc
+			mcpSrv := new(MockMCPServer)
+			mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				handler = args.Get(1).(mcpServer.ToolHandlerFunc)
+			})
+			var sent []map[string]string
+			invoker := new(MockToolInvoker)
+			invoker.On("Invoke", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				sent = append(sent, args.Get(1).(usecase.InvocationDetails).HeaderParams)
+			}).Return(map[string]interface{}{"ok": true}, nil)
+
+			uc := usecase.NewSyncSchemaUseCase(
+				[]usecase.SchemaSourceConfig{{
+					URL:                 sourceURL,
+					AuthTokenFile:       tokenPath,
+					AuthHeader:          tt.header,
+					AuthScheme:          tt.scheme,
+					AuthRefreshInterval: tt.refresh,
+				}},
+				map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: fetcher},
+				map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: generator},
+				mcpSrv,
+				invoker,
+				logger,
+			)
+			require.NoError(t, uc.SyncAllConfiguredSources(ctx))
+			require.NotNil(t, handler)
+
+			_, err := handler(ctx, mcp.CallToolRequest{})
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(tokenPath, []byte("second-token\n"), 0o600))
+			_, err = handler(ctx, mcp.CallToolRequest{})
+			require.NoError(t, err)
+
+			require.Len(t, sent, 2)
+			assert.Equal(t, map[string]string{"X-Tenant": "acme", tt.wantHeader: tt.wantFirst}, sent[0])
+			assert.Equal(t, map[string]string{"X-Tenant": "acme", tt.wantHeader: tt.wantSecond}, sent[1])
+			// The generated details are left untouched.
+			assert.Equal(t, map[string]string{"X-Tenant": "acme"}, details.HeaderParams)
+		})
+	}
+}
+
+func TestSyncSchemaUseCase_AuthTokenFile_RestoredTools(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("stored-token\n"), 0o600))
+
+	sourceURL := "http://billing.example.com/openapi.yaml"
+	repo := memrepo.NewInMemoryToolRepository(logger)
+	require.NoError(t, repo.Save(ctx,
+		[]domain.Tool{{Name: "billing_getinvoice"}, {Name: "other_tool"}},
+		[]usecase.InvocationDetails{
+			{Type: "http", Source: sourceURL, HTTPMethod: "GET", HTTPPath: "/invoices"},
+			{Type: "http", Source: "http://other.example.com/openapi.yaml", HTTPMethod: "GET", HTTPPath: "/other"},
+		},
+	))
+
+	handlers := make(map[string]mcpServer.ToolHandlerFunc)
+	mcpSrv := new(MockMCPServer)
+	mcpSrv.On("AddTool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handlers[args.Get(0).(mcp.Tool).Name] = args.Get(1).(mcpServer.ToolHandlerFunc)
+	})
+	var sent []map[string]string
+	invoker := new(MockToolInvoker)
+	invoker.On("Invoke", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sent = append(sent, args.Get(1).(usecase.InvocationDetails).HeaderParams)
+	}).Return(map[string]interface{}{"ok": true}, nil)
+
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: sourceURL, AuthTokenFile: tokenPath}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: new(MockSchemaFetcher)},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: new(MockToolGenerator)},
+		mcpSrv,
+		invoker,
+		logger,
+		usecase.WithToolRepository(repo),
+	)
+	restored, err := uc.RestoreTools(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, restored)
+
+	// Before the source syncs, its restored tools send the credential; others do not.
+	for _, name := range []string{"billing_getinvoice", "other_tool"} {
+		require.Contains(t, handlers, name)
+		_, err := handlers[name](ctx, mcp.CallToolRequest{})
+		require.NoError(t, err)
+	}
+	require.Len(t, sent, 2)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer stored-token"}, sent[0])
+	assert.Empty(t, sent[1])
+}
+
+func TestSyncSchemaUseCase_AuthTokenFile_Missing(t *testing.T) {
+	sourceURL := "http://billing.example.com/openapi.yaml"
+	uc := usecase.NewSyncSchemaUseCase(
+		[]usecase.SchemaSourceConfig{{URL: sourceURL, AuthTokenFile: filepath.Join(t.TempDir(), "missing")}},
+		map[domain.SchemaType]usecase.SchemaFetcher{domain.SchemaTypeOpenAPI: new(MockSchemaFetcher)},
+		map[domain.SchemaType]usecase.ToolGenerator{domain.SchemaTypeOpenAPI: new(MockToolGenerator)},
+		new(MockMCPServer),
+		new(MockToolInvoker),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	err := uc.SyncAllConfiguredSources(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read token file")
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+)
+
+// MaxToolNameLength is the longest tool name accepted by MCP clients.
+const MaxToolNameLength = 64
+
+// maxToolNameAffixLength bounds prefix plus suffix so that enough of the
+// generated name remains to tell tools apart.
+const maxToolNameAffixLength = 32
+
+// toolNameHashLength is the number of hex digits appended to truncated names.
+const toolNameHashLength = 8
+
+// sourceHashLength is the number of hex digits of a source URL hash appended to
+// tool names that another source registered first.
+const sourceHashLength = 6
+
+var toolNameAffixPattern = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+// ValidateToolNameAffix reports whether prefix and suffix can be added to tool names.
+func ValidateToolNameAffix(prefix, suffix string) error {
+	if !toolNameAffixPattern.MatchString(prefix) {
+		return fmt.Errorf("tool name prefix %q may only contain letters, digits, '_' and '-'", prefix)
+	}
+	if !toolNameAffixPattern.MatchString(suffix) {
+		return fmt.Errorf("tool name suffix %q may only contain letters, digits, '_' and '-'", suffix)
+	}
+	if len(prefix)+len(suffix) > maxToolNameAffixLength {
+		return fmt.Errorf("tool name prefix and suffix are %d characters long, at most %d are allowed",
+			len(prefix)+len(suffix), maxToolNameAffixLength)
+	}
+	return nil
+}
+
+// affixToolName wraps name in prefix and suffix. If the result exceeds
+// MaxToolNameLength, the middle part is shortened and suffixed with a hash of
+// the full name, so the affixes are kept and truncated names stay distinct.
+func affixToolName(prefix, name, suffix string) string {
+	full := prefix + name + suffix
+	if len(full) <= MaxToolNameLength {
+		return full
+	}
+	sum := sha256.Sum256([]byte(full))
+	hash := hex.EncodeToString(sum[:])[:toolNameHashLength]
+	keep := MaxToolNameLength - len(prefix) - len(suffix) - len(hash) - 1
+	if keep < 0 {
+		keep = 0
+	}
+	shortened := prefix + name[:keep] + "_" + hash + suffix
+	if len(shortened) > MaxToolNameLength {
+		// Only reachable with affixes that ValidateToolNameAffix rejects.
+		shortened = shortened[:MaxToolNameLength]
+	}
+	return shortened
+}
+
+// sourceToolName suffixes name with a hash of sourceURL, telling the tool apart
+// from a same-named tool of another source. Long names are shortened as by
+// affixToolName.
+func sourceToolName(name, sourceURL string) string {
+	sum := sha256.Sum256([]byte(sourceURL))
+	return affixToolName("", name, "_"+hex.EncodeToString(sum[:])[:sourceHashLength])
+}
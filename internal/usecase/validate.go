@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SourceReport is the outcome of generating the tools of one schema source.
+type SourceReport struct {
+	Source  string
+	Tools   int                // number of tools generated
+	Skipped []SkippedOperation // operations no tool could be generated for
+	Err     error              // why the source failed altogether, if it did
+}
+
+// Validate fetches every enabled configured source and generates its tools without
+// registering them, reporting for each source how many tools it yields, the
+// operations skipped along the way, and the error that failed it, if any.
+func (uc *SyncSchemaUseCase) Validate(ctx context.Context) []SourceReport {
+	var reports []SourceReport
+	for _, source := range uc.configuredSources() {
+		if source.Disabled {
+			continue
+		}
+		tools, _, skipped, err := uc.fetchAndGenerate(ctx, source)
+		if err != nil {
+			uc.logger.Error("Failed to generate tools for schema source.", slog.String("source", source.URL), slog.Any("error", err))
+		}
+		reports = append(reports, SourceReport{Source: source.URL, Tools: len(tools), Skipped: skipped, Err: err})
+	}
+	return reports
+}